@@ -243,6 +243,22 @@ func TestT8n(t *testing.T) {
 			output:      t8nOutput{alloc: false, result: false},
 			expExitCode: 3,
 		},
+		{ // Per-transaction gas cap before Fusaka
+			base: "./testdata/25",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env.json", "London", "",
+			},
+			output: t8nOutput{result: true},
+			expOut: "exp_london.json",
+		},
+		{ // Per-transaction gas cap after Fusaka
+			base: "./testdata/25",
+			input: t8nInput{
+				"alloc.json", "txs.json", "env.json", "Fusaka", "",
+			},
+			output: t8nOutput{result: true},
+			expOut: "exp_fusaka.json",
+		},
 	} {
 		args := []string{"t8n"}
 		args = append(args, tc.output.get()...)
@@ -91,6 +91,7 @@ var (
 		utils.TxPoolGlobalQueueFlag,
 		utils.TxPoolLifetimeFlag,
 		utils.SyncModeFlag,
+		utils.DAPolicyFlag,
 		utils.ExitWhenSyncedFlag,
 		utils.GCModeFlag,
 		utils.SnapshotFlag,
@@ -133,6 +134,7 @@ var (
 		utils.MinerExtraDataFlag,
 		utils.MinerRecommitIntervalFlag,
 		utils.MinerNoVerifyFlag,
+		utils.ValidatorAccountFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV5Flag,
@@ -454,6 +456,57 @@ func startNode(ctx *cli.Context, stack *node.Node, backend ethapi.Backend, isCon
 			utils.Fatalf("Failed to start mining: %v", err)
 		}
 	}
+	if ctx.IsSet(utils.ValidatorAccountFlag.Name) {
+		startValidator(ctx, stack, backend)
+	}
+}
+
+// startValidator decrypts the requested validator key from the keystore and
+// starts the built-in hybrid validator client.
+func startValidator(ctx *cli.Context, stack *node.Node, backend ethapi.Backend) {
+	// Validating only makes sense if a full Ethereum node is running
+	if ctx.String(utils.SyncModeFlag.Name) == "light" {
+		utils.Fatalf("Light clients do not support validating")
+	}
+	ethBackend, ok := backend.(*eth.EthAPIBackend)
+	if !ok {
+		utils.Fatalf("Ethereum service not running")
+	}
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+
+	address := ctx.String(utils.ValidatorAccountFlag.Name)
+	account, err := utils.MakeAddress(ks, address)
+	if err != nil {
+		utils.Fatalf("Invalid validator account: %v", err)
+	}
+	if account, err = ks.Find(account); err != nil {
+		utils.Fatalf("Validator account unavailable: %v", err)
+	}
+	keyjson, err := os.ReadFile(account.URL.Path)
+	if err != nil {
+		utils.Fatalf("Failed to read validator key: %v", err)
+	}
+	passwords := utils.MakePasswordList(ctx)
+	for trials := 0; trials < 3; trials++ {
+		prompt := fmt.Sprintf("Unlocking validator account %s | Attempt %d/%d", address, trials+1, 3)
+		password := utils.GetPassPhraseWithList(prompt, false, 0, passwords)
+
+		var key *keystore.Key
+		if key, err = keystore.DecryptKey(keyjson, password); err == nil {
+			db, err := stack.OpenDatabase("validator", 0, 0, "eth/db/validator/", false)
+			if err != nil {
+				utils.Fatalf("Failed to open slashing protection database: %v", err)
+			}
+			if err := ethBackend.StartValidating(key.PrivateKey, db); err != nil {
+				utils.Fatalf("Failed to start validating: %v", err)
+			}
+			return
+		}
+		if err != keystore.ErrDecrypt {
+			break
+		}
+	}
+	utils.Fatalf("Failed to unlock validator account %s (%v)", address, err)
 }
 
 // unlockAccounts unlocks any account specifically requested.
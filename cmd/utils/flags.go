@@ -225,6 +225,13 @@ var (
 		Value:    &defaultSyncMode,
 		Category: flags.EthCategory,
 	}
+	defaultDAPolicy = ethconfig.Defaults.DAPolicy
+	DAPolicyFlag    = &flags.TextMarshalerFlag{
+		Name:     "peerdas.policy",
+		Usage:    `Handling of post-Fusaka blocks with unavailable data ("warn" or "strict")`,
+		Value:    &defaultDAPolicy,
+		Category: flags.EthCategory,
+	}
 	GCModeFlag = &cli.StringFlag{
 		Name:     "gcmode",
 		Usage:    `Blockchain garbage collection mode ("full", "archive")`,
@@ -562,6 +569,13 @@ var (
 		Category: flags.MinerCategory,
 	}
 
+	// Validator settings
+	ValidatorAccountFlag = &cli.StringFlag{
+		Name:     "validator.account",
+		Usage:    "Keystore account to attest to new blocks with as a hybrid validator (password taken from --password)",
+		Category: flags.ValidatorCategory,
+	}
+
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
 		Name:     "unlock",
@@ -1772,6 +1786,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(SyncModeFlag.Name) {
 		cfg.SyncMode = *flags.GlobalTextMarshaler(ctx, SyncModeFlag.Name).(*downloader.SyncMode)
 	}
+	if ctx.IsSet(DAPolicyFlag.Name) {
+		cfg.DAPolicy = *flags.GlobalTextMarshaler(ctx, DAPolicyFlag.Name).(*core.DataAvailabilityPolicy)
+	}
 	if ctx.IsSet(NetworkIdFlag.Name) {
 		cfg.NetworkId = ctx.Uint64(NetworkIdFlag.Name)
 		cfg.IsNetworkIdSet = true
@@ -323,6 +323,13 @@ func (ethash *Ethash) verifyHeader(chain consensus.ChainHeaderReader, header, pa
 	if err := misc.VerifyForkHashes(chain.Config(), header, uncle); err != nil {
 		return err
 	}
+	// Verify the header's EIP-4844 attributes.
+	if err := misc.VerifyEIP4844Header(chain.Config(), parent, header); err != nil {
+		return err
+	}
+	if err := misc.VerifyEip7594Header(chain.Config(), header); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -686,6 +693,15 @@ func (ethash *Ethash) SealHash(header *types.Header) (hash common.Hash) {
 	if header.BaseFee != nil {
 		enc = append(enc, header.BaseFee)
 	}
+	if header.BlobGasUsed != nil {
+		enc = append(enc, header.BlobGasUsed)
+	}
+	if header.ExcessBlobGas != nil {
+		enc = append(enc, header.ExcessBlobGas)
+	}
+	if header.DACommitment != nil {
+		enc = append(enc, header.DACommitment)
+	}
 	rlp.Encode(hasher, enc)
 	hasher.Sum(hash[:0])
 	return hash
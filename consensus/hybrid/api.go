@@ -9,10 +9,11 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
-	"github.com/ethereum/go-ethereum/rpc"
 )
 
-// API provides validator-related RPC methods.
+// API provides the engine-level RPC methods of the validator namespace, such as
+// attestations and slashing offenses. Validator, staking and finality queries
+// are served by the validator API of the eth service, sharing the namespace.
 type API struct {
 	hybrid *Hybrid
 	chain  consensus.ChainHeaderReader
@@ -26,101 +27,6 @@ func NewAPI(hybrid *Hybrid, chain consensus.ChainHeaderReader) *API {
 	}
 }
 
-// ValidatorInfoResult is the result of getValidatorInfo RPC call.
-type ValidatorInfoResult struct {
-	Address         common.Address `json:"address"`
-	Stake           *big.Int       `json:"stake"`
-	Active          bool           `json:"active"`
-	LastAttestation uint64         `json:"lastAttestation"`
-}
-
-// GetValidatorInfo returns information about a specific validator.
-func (api *API) GetValidatorInfo(ctx context.Context, addr common.Address) (*ValidatorInfoResult, error) {
-	validators := api.hybrid.GetValidators()
-	info, exists := validators[addr]
-	if !exists {
-		return &ValidatorInfoResult{
-			Address: addr,
-			Stake:   big.NewInt(0),
-			Active:  false,
-		}, nil
-	}
-
-	return &ValidatorInfoResult{
-		Address:         info.Address,
-		Stake:           info.Stake,
-		Active:          info.Active,
-		LastAttestation: info.LastAttestation,
-	}, nil
-}
-
-// GetActiveValidators returns the list of active validator addresses.
-func (api *API) GetActiveValidators(ctx context.Context) ([]common.Address, error) {
-	validators := api.hybrid.GetValidators()
-	active := make([]common.Address, 0)
-
-	for addr, info := range validators {
-		if info.Active {
-			active = append(active, addr)
-		}
-	}
-
-	return active, nil
-}
-
-// NetworkStatsResult contains network-wide validator statistics.
-type NetworkStatsResult struct {
-	TotalValidators   int      `json:"totalValidators"`
-	ActiveValidators  int      `json:"activeValidators"`
-	TotalStaked       *big.Int `json:"totalStaked"`
-	MinStake          *big.Int `json:"minStake"`
-	LastFinalizedBlock uint64   `json:"lastFinalizedBlock"`
-	FinalityThreshold uint64   `json:"finalityThreshold"`
-}
-
-// GetNetworkStats returns network-wide validator statistics.
-func (api *API) GetNetworkStats(ctx context.Context) (*NetworkStatsResult, error) {
-	validators := api.hybrid.GetValidators()
-
-	totalCount := len(validators)
-	activeCount := 0
-	totalStaked := big.NewInt(0)
-
-	for _, info := range validators {
-		if info.Active {
-			activeCount++
-			totalStaked.Add(totalStaked, info.Stake)
-		}
-	}
-
-	return &NetworkStatsResult{
-		TotalValidators:    totalCount,
-		ActiveValidators:   activeCount,
-		TotalStaked:        totalStaked,
-		MinStake:           (*big.Int)(api.hybrid.config.MinStake),
-		LastFinalizedBlock: api.hybrid.finalityTracker.GetLastFinalizedBlock(),
-		FinalityThreshold:  api.hybrid.config.FinalityThreshold,
-	}, nil
-}
-
-// GetFinalityStatus returns the finality status of a block.
-func (api *API) GetFinalityStatus(ctx context.Context, blockNumber rpc.BlockNumber) (*FinalityStatus, error) {
-	var number uint64
-	if blockNumber == rpc.LatestBlockNumber || blockNumber == rpc.PendingBlockNumber {
-		header := api.chain.CurrentHeader()
-		number = header.Number.Uint64()
-	} else {
-		number = uint64(blockNumber)
-	}
-
-	header := api.chain.GetHeaderByNumber(number)
-	if header == nil {
-		return nil, nil
-	}
-
-	return api.hybrid.finalityTracker.GetFinalityStatus(number, header.Hash()), nil
-}
-
 // GetAttestations returns attestations for a specific block.
 func (api *API) GetAttestations(ctx context.Context, blockHash common.Hash) (*BlockAttestationsResult, error) {
 	attestations := api.hybrid.GetAttestations(blockHash)
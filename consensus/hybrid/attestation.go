@@ -15,6 +15,10 @@ import (
 	"golang.org/x/crypto/sha3"
 )
 
+// attestationDomain separates attestation signatures from any other message
+// signed with a validator key.
+const attestationDomain = "altcoinchain-hybrid-attestation"
+
 var (
 	// ErrInvalidSignature is returned when an attestation signature is invalid
 	ErrInvalidSignature = errors.New("invalid attestation signature")
@@ -32,6 +36,10 @@ type Attestation struct {
 	BlockHash common.Hash `json:"blockHash"`
 	// BlockNumber is the number of the block being attested to
 	BlockNumber uint64 `json:"blockNumber"`
+	// Source is the latest justified checkpoint known to the validator
+	Source Checkpoint `json:"source"`
+	// Target is the checkpoint of the epoch the attested block belongs to
+	Target Checkpoint `json:"target"`
 	// Signature is the validator's signature over the attestation data
 	Signature []byte `json:"signature"`
 }
@@ -47,6 +55,8 @@ type BlockAttestations struct {
 type AttestationData struct {
 	BlockHash   common.Hash
 	BlockNumber uint64
+	Source      Checkpoint
+	Target      Checkpoint
 }
 
 // Hash returns the hash of the attestation data.
@@ -54,27 +64,44 @@ func (a *AttestationData) Hash() common.Hash {
 	return rlpHash(a)
 }
 
-// NewAttestation creates a new attestation for a block.
-func NewAttestation(validator common.Address, blockHash common.Hash, blockNumber uint64) *Attestation {
+// NewAttestation creates a new attestation for a block, voting for the link
+// between the given source and target checkpoints.
+func NewAttestation(validator common.Address, blockHash common.Hash, blockNumber uint64, source, target Checkpoint) *Attestation {
 	return &Attestation{
 		Validator:   validator,
 		BlockHash:   blockHash,
 		BlockNumber: blockNumber,
+		Source:      source,
+		Target:      target,
 	}
 }
 
-// SigningHash returns the hash that should be signed.
-func (a *Attestation) SigningHash() common.Hash {
-	data := &AttestationData{
+// Hash returns the unique identifier of the attestation, covering the
+// signature as well. It is used to deduplicate attestations on the network.
+func (a *Attestation) Hash() common.Hash {
+	return rlpHash(a)
+}
+
+// Data returns the attestation data covered by the signature.
+func (a *Attestation) Data() *AttestationData {
+	return &AttestationData{
 		BlockHash:   a.BlockHash,
 		BlockNumber: a.BlockNumber,
+		Source:      a.Source,
+		Target:      a.Target,
 	}
-	return data.Hash()
 }
 
-// Sign signs the attestation with the given private key.
-func (a *Attestation) Sign(privKey *ecdsa.PrivateKey) error {
-	hash := a.SigningHash()
+// SigningHash returns the hash that should be signed. It mixes in a fixed
+// domain and the chain ID, so attestations can't be replayed on another chain
+// sharing the same staking keys.
+func (a *Attestation) SigningHash(chainID *big.Int) common.Hash {
+	return rlpHash([]interface{}{attestationDomain, chainID, a.Data()})
+}
+
+// Sign signs the attestation for the given chain with the given private key.
+func (a *Attestation) Sign(privKey *ecdsa.PrivateKey, chainID *big.Int) error {
+	hash := a.SigningHash(chainID)
 	sig, err := crypto.Sign(hash[:], privKey)
 	if err != nil {
 		return err
@@ -83,13 +110,14 @@ func (a *Attestation) Sign(privKey *ecdsa.PrivateKey) error {
 	return nil
 }
 
-// VerifySignature verifies that the attestation signature is valid.
-func (a *Attestation) VerifySignature() bool {
+// VerifySignature verifies that the attestation signature is valid on the
+// given chain.
+func (a *Attestation) VerifySignature(chainID *big.Int) bool {
 	if len(a.Signature) != 65 {
 		return false
 	}
 
-	hash := a.SigningHash()
+	hash := a.SigningHash(chainID)
 	pubKey, err := crypto.SigToPub(hash[:], a.Signature)
 	if err != nil {
 		return false
@@ -99,13 +127,14 @@ func (a *Attestation) VerifySignature() bool {
 	return bytes.Equal(recoveredAddr.Bytes(), a.Validator.Bytes())
 }
 
-// RecoverValidator recovers the validator address from the signature.
-func (a *Attestation) RecoverValidator() (common.Address, error) {
+// RecoverValidator recovers the validator address from the signature made on
+// the given chain.
+func (a *Attestation) RecoverValidator(chainID *big.Int) (common.Address, error) {
 	if len(a.Signature) != 65 {
 		return common.Address{}, ErrInvalidSignature
 	}
 
-	hash := a.SigningHash()
+	hash := a.SigningHash(chainID)
 	pubKey, err := crypto.SigToPub(hash[:], a.Signature)
 	if err != nil {
 		return common.Address{}, err
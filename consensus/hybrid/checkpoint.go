@@ -0,0 +1,69 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+
+package hybrid
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EpochLength is the number of blocks in an epoch. The first block of every
+// epoch is its checkpoint, which validators vote on to justify and finalize.
+const EpochLength = 32
+
+// Checkpoint is an epoch boundary block, the unit of justification and
+// finalization (Casper FFG style).
+type Checkpoint struct {
+	Epoch uint64      `json:"epoch"`
+	Hash  common.Hash `json:"hash"`
+}
+
+// EpochOf returns the epoch the given block number belongs to.
+func EpochOf(number uint64) uint64 {
+	return number / EpochLength
+}
+
+// Number returns the block number of the checkpoint block.
+func (c Checkpoint) Number() uint64 {
+	return c.Epoch * EpochLength
+}
+
+// Checkpoints returns the source and target checkpoints a validator should
+// vote for when attesting to the given block: the latest justified checkpoint
+// and the epoch boundary block on the chain of the attested block.
+func (h *Hybrid) Checkpoints(header *types.Header) (source Checkpoint, target Checkpoint, err error) {
+	target, err = h.targetCheckpoint(header.Hash(), header.Number.Uint64())
+	if err != nil {
+		return Checkpoint{}, Checkpoint{}, err
+	}
+	source = h.finalityTracker.Justified()
+	if source.Epoch > target.Epoch || (source.Epoch == target.Epoch && source != target) {
+		return Checkpoint{}, Checkpoint{}, ErrInvalidCheckpoint
+	}
+	return source, target, nil
+}
+
+// targetCheckpoint returns the epoch boundary block on the chain of the given
+// block, which must be available locally.
+func (h *Hybrid) targetCheckpoint(hash common.Hash, number uint64) (Checkpoint, error) {
+	h.validatorsLock.RLock()
+	chain := h.chain
+	h.validatorsLock.RUnlock()
+
+	if chain == nil {
+		return Checkpoint{}, ErrUnknownCheckpoint
+	}
+	epoch := EpochOf(number)
+	for number > epoch*EpochLength {
+		header := chain.GetHeader(hash, number)
+		if header == nil {
+			return Checkpoint{}, ErrUnknownCheckpoint
+		}
+		hash, number = header.ParentHash, number-1
+	}
+	if chain.GetHeader(hash, number) == nil {
+		return Checkpoint{}, ErrUnknownCheckpoint
+	}
+	return Checkpoint{Epoch: epoch, Hash: hash}, nil
+}
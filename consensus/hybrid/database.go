@@ -0,0 +1,139 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+
+package hybrid
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// writeAttestations persists the attestation set of a block, ordered by
+// validator address.
+func (h *Hybrid) writeAttestations(ba *BlockAttestations) {
+	list := make([]*Attestation, 0, len(ba.Attestations))
+	for _, att := range ba.Attestations {
+		list = append(list, att)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i].Validator[:], list[j].Validator[:]) < 0
+	})
+	blob, err := rlp.EncodeToBytes(list)
+	if err != nil {
+		h.log.Error("Failed to encode attestations", "number", ba.BlockNumber, "hash", ba.BlockHash, "err", err)
+		return
+	}
+	rawdb.WriteHybridAttestations(h.db, ba.BlockNumber, ba.BlockHash, blob)
+}
+
+// writeOffense persists a detected slashable offense.
+func (h *Hybrid) writeOffense(offense *SlashableOffense) {
+	blob, err := rlp.EncodeToBytes(offense)
+	if err != nil {
+		h.log.Error("Failed to encode offense", "validator", offense.Validator, "err", err)
+		return
+	}
+	rawdb.WriteHybridOffense(h.db, offense.BlockNumber, offense.Validator, blob)
+}
+
+// writeVote persists the FFG vote of an attestation, which is kept for the whole
+// surround-vote detection window rather than the attestation window.
+func (h *Hybrid) writeVote(attestation *Attestation) {
+	blob, err := rlp.EncodeToBytes(attestation)
+	if err != nil {
+		h.log.Error("Failed to encode vote", "validator", attestation.Validator, "err", err)
+		return
+	}
+	rawdb.WriteHybridVote(h.db, attestation.Target.Epoch, attestation.Validator, blob)
+}
+
+// loadDatabase restores the attestations, finalized blocks and offenses of the
+// last attestation window before the given head, along with the FFG votes of the
+// surround-vote detection window, so that a restart neither forgets finality nor
+// the double and surround vote history of validators.
+func (h *Hybrid) loadDatabase(head uint64) {
+	var from uint64
+	if head > h.config.AttestationWindow {
+		from = head - h.config.AttestationWindow
+	}
+	var attestations int
+	for _, blob := range rawdb.ReadAllHybridAttestations(h.db, from) {
+		var list []*Attestation
+		if err := rlp.DecodeBytes(blob, &list); err != nil || len(list) == 0 {
+			h.log.Warn("Failed to decode stored attestations", "err", err)
+			continue
+		}
+		ba := &BlockAttestations{
+			BlockHash:    list[0].BlockHash,
+			BlockNumber:  list[0].BlockNumber,
+			Attestations: make(map[common.Address]*Attestation, len(list)),
+		}
+		for _, att := range list {
+			ba.Attestations[att.Validator] = att
+		}
+		h.attestations.Add(ba.BlockHash, ba)
+		h.slashingDetector.restoreAttestations(list)
+		for _, att := range list {
+			h.finalityTracker.ProcessAttestation(att)
+		}
+		attestations += len(list)
+	}
+	if epoch, hash, ok := rawdb.ReadHybridJustified(h.db); ok {
+		h.finalityTracker.SetAnchor(Checkpoint{Epoch: epoch, Hash: hash})
+	}
+	numbers, hashes := rawdb.ReadAllHybridFinalized(h.db, from)
+	for i, number := range numbers {
+		h.finalityTracker.MarkFinalized(number, hashes[i])
+	}
+	var offenses []SlashableOffense
+	for _, blob := range rawdb.ReadAllHybridOffenses(h.db, from) {
+		var offense SlashableOffense
+		if err := rlp.DecodeBytes(blob, &offense); err != nil {
+			h.log.Warn("Failed to decode stored offense", "err", err)
+			continue
+		}
+		offenses = append(offenses, offense)
+	}
+	h.slashingDetector.restoreOffenses(offenses)
+
+	var votes []*Attestation
+	for _, blob := range rawdb.ReadAllHybridVotes(h.db, voteHorizon(EpochOf(head))) {
+		vote := new(Attestation)
+		if err := rlp.DecodeBytes(blob, vote); err != nil {
+			h.log.Warn("Failed to decode stored vote", "err", err)
+			continue
+		}
+		votes = append(votes, vote)
+	}
+	h.slashingDetector.restoreVotes(votes)
+
+	h.log.Info("Loaded hybrid consensus data", "attestations", attestations, "finalized", len(numbers), "offenses", len(offenses), "votes", len(votes))
+}
+
+// prune deletes persisted data that fell out of the attestation window of the
+// given head, along with checkpoint votes that can no longer change finality.
+func (h *Hybrid) prune(head uint64) {
+	if head > h.config.AttestationWindow {
+		rawdb.PruneHybridData(h.db, head-h.config.AttestationWindow)
+	}
+	if epoch := EpochOf(head); epoch > 2 {
+		h.finalityTracker.PruneVotes((epoch - 2) * EpochLength)
+	}
+	if epoch := voteHorizon(EpochOf(head)); epoch > 0 {
+		rawdb.PruneHybridVotes(h.db, epoch)
+	}
+	h.slashingDetector.PruneHistory(EpochOf(head))
+}
+
+// voteHorizon returns the oldest target epoch of the FFG votes retained for
+// surround-vote detection at the given epoch.
+func voteHorizon(epoch uint64) uint64 {
+	if epoch <= maxSpanEpochs {
+		return 0
+	}
+	return epoch - maxSpanEpochs
+}
@@ -0,0 +1,196 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+
+package hybrid
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// EvidenceAddress is the system address slashing evidence is submitted to. A
+// transaction sent to it carries the RLP encoding of two conflicting signed
+// attestations as its data, the same encoding SlashableOffense.Evidence uses.
+// Miners include evidence transactions like any other transaction and every
+// node applies the valid ones when finalizing the block.
+var EvidenceAddress = common.HexToAddress("0x0000000000000000000000000000000000001001")
+
+var (
+	// ErrInvalidEvidence is returned when slashing evidence can't be decoded or
+	// its attestations are not correctly signed by the same validator.
+	ErrInvalidEvidence = errors.New("invalid slashing evidence")
+	// ErrNoConflict is returned when the attestations of slashing evidence do
+	// not conflict with each other.
+	ErrNoConflict = errors.New("attestations do not conflict")
+)
+
+// EncodeEvidence encodes two conflicting attestations as slashing evidence.
+func EncodeEvidence(first, second *Attestation) ([]byte, error) {
+	return rlp.EncodeToBytes([]*Attestation{first, second})
+}
+
+// DecodeEvidence decodes slashing evidence into its two attestations.
+func DecodeEvidence(data []byte) (*Attestation, *Attestation, error) {
+	var atts []*Attestation
+	if err := rlp.DecodeBytes(data, &atts); err != nil || len(atts) != 2 {
+		return nil, nil, ErrInvalidEvidence
+	}
+	return atts[0], atts[1], nil
+}
+
+// VerifyEvidence checks that slashing evidence consists of two attestations
+// signed on the given chain by the same validator which conflict with each
+// other, returning the offending validator and the kind of offense.
+func VerifyEvidence(chainID *big.Int, data []byte) (common.Address, SlashingReason, error) {
+	first, second, err := DecodeEvidence(data)
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	if first.Validator != second.Validator || !first.VerifySignature(chainID) || !second.VerifySignature(chainID) {
+		return common.Address{}, "", ErrInvalidEvidence
+	}
+	reason, ok := conflict(first, second)
+	if !ok {
+		return common.Address{}, "", ErrNoConflict
+	}
+	return first.Validator, reason, nil
+}
+
+// VerifyTransaction checks that a transaction sent to EvidenceAddress in a
+// hybrid block carries valid slashing evidence. Whether the offending validator
+// can still be slashed depends on the state and is left to Finalize. Other
+// transactions are not checked.
+func (h *Hybrid) VerifyTransaction(chain consensus.ChainHeaderReader, header *types.Header, tx *types.Transaction) error {
+	if to := tx.To(); to == nil || *to != EvidenceAddress || !chain.Config().IsHybrid(header.Number) {
+		return nil
+	}
+	_, _, err := VerifyEvidence(chain.Config().ChainID, tx.Data())
+	return err
+}
+
+// conflict returns whether two attestations of the same validator are a
+// slashable combination: two different blocks at the same height, two
+// different checkpoint votes for the same target epoch, or checkpoint votes
+// surrounding each other.
+func conflict(a, b *Attestation) (SlashingReason, bool) {
+	if a.Data().Hash() == b.Data().Hash() {
+		return "", false
+	}
+	if a.BlockNumber == b.BlockNumber && a.BlockHash != b.BlockHash {
+		return SlashDoubleAttestation, true
+	}
+	// Votes without a source -> target link can't conflict any further
+	if a.Source.Epoch >= a.Target.Epoch || b.Source.Epoch >= b.Target.Epoch {
+		return "", false
+	}
+	if a.Target.Epoch == b.Target.Epoch && (a.Source != b.Source || a.Target != b.Target) {
+		return SlashDoubleAttestation, true
+	}
+	if (a.Source.Epoch < b.Source.Epoch && b.Target.Epoch < a.Target.Epoch) ||
+		(b.Source.Epoch < a.Source.Epoch && a.Target.Epoch < b.Target.Epoch) {
+		return SlashSurroundVoting, true
+	}
+	return "", false
+}
+
+// applyEvidence slashes the validators proven to misbehave by the evidence
+// transactions of a block. Blocks with invalid evidence are rejected by
+// VerifyUncles, evidence against validators that are not active or were
+// already slashed is ignored, so the outcome only depends on the block and its
+// parent state.
+func (h *Hybrid) applyEvidence(chainID *big.Int, header *types.Header, statedb *state.StateDB, txs []*types.Transaction) {
+	for _, tx := range txs {
+		if to := tx.To(); to == nil || *to != EvidenceAddress {
+			continue
+		}
+		validator, reason, err := VerifyEvidence(chainID, tx.Data())
+		if err != nil {
+			h.log.Debug("Ignoring invalid slashing evidence", "block", header.Number, "tx", tx.Hash(), "err", err)
+			continue
+		}
+		penalty := SlashStakingValidator(statedb, h.config.StakingContract, validator)
+		if penalty == nil {
+			h.log.Debug("Ignoring slashing evidence against inactive validator", "block", header.Number, "tx", tx.Hash(), "validator", validator)
+			continue
+		}
+		h.log.Info("Slashed validator", "block", header.Number, "validator", validator, "reason", reason, "penalty", penalty)
+	}
+}
+
+// slashingPenaltyPercent is the share of the total stake of a validator
+// removed when slashed, SLASHING_PENALTY_PERCENT of the staking contract.
+const slashingPenaltyPercent = 10
+
+// SlashStakingValidator applies the slash function of the staking contract to
+// a validator directly on its storage: the penalty is taken from the self stake
+// first and from delegations after that, the validator is flagged as slashed
+// and removed from the validator list. It returns the penalty, or nil if the
+// validator is not active or was already slashed.
+func SlashStakingValidator(db StorageWriter, contract common.Address, addr common.Address) *big.Int {
+	v := ReadStakingValidator(db, contract, addr)
+	if !v.IsActive || v.IsSlashed {
+		return nil
+	}
+	penalty := new(big.Int).Mul(v.TotalStake(), big.NewInt(slashingPenaltyPercent))
+	penalty.Div(penalty, big.NewInt(100))
+
+	if v.SelfStake.Cmp(penalty) >= 0 {
+		v.SelfStake.Sub(v.SelfStake, penalty)
+	} else {
+		v.TotalDelegated.Sub(v.TotalDelegated, new(big.Int).Sub(penalty, v.SelfStake))
+		v.SelfStake.SetUint64(0)
+	}
+	base := mappingSlot(addr, validatorsSlot)
+	db.SetState(contract, offsetSlot(base, selfStakeOffset), common.BigToHash(v.SelfStake))
+	db.SetState(contract, offsetSlot(base, totalDelegatedOffset), common.BigToHash(v.TotalDelegated))
+
+	// Clear isActive and set isSlashed, keeping the rest of the packed slot
+	flags := db.GetState(contract, offsetSlot(base, flagsOffset))
+	flags[common.HashLength-1], flags[common.HashLength-2] = 0, 1
+	db.SetState(contract, offsetSlot(base, flagsOffset), flags)
+
+	totalStaked := db.GetState(contract, common.BigToHash(big.NewInt(totalStakedSlot))).Big()
+	db.SetState(contract, common.BigToHash(big.NewInt(totalStakedSlot)), common.BigToHash(subFloor(totalStaked, penalty)))
+
+	removeStakingValidator(db, contract, addr)
+	return penalty
+}
+
+// removeStakingValidator removes a validator from the validator list of the
+// staking contract by swapping it with the last entry, mirroring
+// _removeValidator.
+func removeStakingValidator(db StorageWriter, contract common.Address, addr common.Address) {
+	index := db.GetState(contract, mappingSlot(addr, validatorIndexSlot)).Big().Uint64() // 1-indexed
+	if index == 0 {
+		return
+	}
+	length := db.GetState(contract, common.BigToHash(big.NewInt(validatorListSlot))).Big().Uint64()
+	if length == 0 || index > length {
+		return
+	}
+	if index < length {
+		last := db.GetState(contract, arraySlot(validatorListSlot, length-1))
+		db.SetState(contract, arraySlot(validatorListSlot, index-1), last)
+		db.SetState(contract, mappingSlot(common.BytesToAddress(last.Bytes()), validatorIndexSlot), common.BigToHash(new(big.Int).SetUint64(index)))
+	}
+	db.SetState(contract, arraySlot(validatorListSlot, length-1), common.Hash{})
+	db.SetState(contract, common.BigToHash(new(big.Int).SetUint64(validatorListSlot)), common.BigToHash(new(big.Int).SetUint64(length-1)))
+	db.SetState(contract, mappingSlot(addr, validatorIndexSlot), common.Hash{})
+
+	total := db.GetState(contract, common.BigToHash(big.NewInt(totalValidatorsSlot))).Big()
+	db.SetState(contract, common.BigToHash(big.NewInt(totalValidatorsSlot)), common.BigToHash(subFloor(total, common.Big1)))
+}
+
+// subFloor returns a - b, or zero if b is larger than a.
+func subFloor(a, b *big.Int) *big.Int {
+	if a.Cmp(b) < 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Sub(a, b)
+}
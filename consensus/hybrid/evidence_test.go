@@ -0,0 +1,191 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+//
+// The go-altcoinchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-altcoinchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-altcoinchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package hybrid
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// signedVote creates an attestation of the given block signed by key.
+func signedVote(t *testing.T, key *ecdsa.PrivateKey, hash byte, number, source, target uint64) *Attestation {
+	att := NewAttestation(crypto.PubkeyToAddress(key.PublicKey), common.Hash{hash}, number,
+		Checkpoint{Epoch: source, Hash: common.Hash{byte(source)}},
+		Checkpoint{Epoch: target, Hash: common.Hash{byte(target)}})
+	if err := att.Sign(key, params.TestChainConfig.ChainID); err != nil {
+		t.Fatalf("failed to sign attestation: %v", err)
+	}
+	return att
+}
+
+// Tests that slashing evidence is only accepted if it proves a slashable
+// offense of a single validator.
+func TestVerifyEvidence(t *testing.T) {
+	var (
+		key, _      = crypto.GenerateKey()
+		otherKey, _ = crypto.GenerateKey()
+		validator   = crypto.PubkeyToAddress(key.PublicKey)
+	)
+	forged := signedVote(t, otherKey, 2, 65, 1, 2)
+	forged.Validator = validator
+
+	tests := []struct {
+		first, second *Attestation
+		reason        SlashingReason
+		err           error
+	}{
+		{signedVote(t, key, 1, 65, 1, 2), signedVote(t, key, 2, 65, 1, 2), SlashDoubleAttestation, nil}, // two blocks at one height
+		{signedVote(t, key, 1, 65, 1, 2), signedVote(t, key, 2, 66, 0, 2), SlashDoubleAttestation, nil}, // two links to one target
+		{signedVote(t, key, 1, 97, 2, 3), signedVote(t, key, 2, 129, 1, 4), SlashSurroundVoting, nil},   // surrounding vote
+		{signedVote(t, key, 1, 161, 1, 5), signedVote(t, key, 2, 97, 2, 3), SlashSurroundVoting, nil},   // surrounded vote
+		{signedVote(t, key, 1, 65, 1, 2), signedVote(t, key, 1, 65, 1, 2), "", ErrNoConflict},           // same vote twice
+		{signedVote(t, key, 1, 65, 1, 2), signedVote(t, key, 2, 97, 2, 3), "", ErrNoConflict},           // consecutive votes
+		{signedVote(t, key, 1, 65, 2, 2), signedVote(t, key, 2, 66, 2, 2), "", ErrNoConflict},           // head votes at different heights
+		{signedVote(t, key, 1, 65, 1, 2), signedVote(t, otherKey, 2, 65, 1, 2), "", ErrInvalidEvidence}, // different validators
+		{signedVote(t, key, 1, 65, 1, 2), forged, "", ErrInvalidEvidence},                               // forged signature
+	}
+	for i, tt := range tests {
+		evidence, err := EncodeEvidence(tt.first, tt.second)
+		if err != nil {
+			t.Fatalf("test %d: failed to encode evidence: %v", i, err)
+		}
+		offender, reason, err := VerifyEvidence(params.TestChainConfig.ChainID, evidence)
+		if err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+			continue
+		}
+		if err == nil && (offender != validator || reason != tt.reason) {
+			t.Errorf("test %d: offense mismatch: have %x/%v, want %x/%v", i, offender, reason, validator, tt.reason)
+		}
+	}
+	if _, _, err := VerifyEvidence(params.TestChainConfig.ChainID, []byte{0xc0}); err != ErrInvalidEvidence {
+		t.Errorf("empty evidence: have %v, want %v", err, ErrInvalidEvidence)
+	}
+	if _, _, err := VerifyEvidence(params.TestChainConfig.ChainID, []byte("bogus")); err != ErrInvalidEvidence {
+		t.Errorf("malformed evidence: have %v, want %v", err, ErrInvalidEvidence)
+	}
+}
+
+// Tests that evidence included in a block slashes the offending validator when
+// the block is finalized, while blocks with bogus evidence are rejected.
+func TestSlashingEvidenceInBlock(t *testing.T) {
+	var (
+		key, _       = crypto.GenerateKey()
+		validator    = crypto.PubkeyToAddress(key.PublicKey)
+		honest       = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		senderKey, _ = crypto.GenerateKey()
+		sender       = crypto.PubkeyToAddress(senderKey.PublicKey)
+		staking      = common.HexToAddress("0x139fa30605591055aceada5e841a2252d33b14c7")
+		config       = hybridTestConfig(0, staking)
+		db           = rawdb.NewMemoryDatabase()
+		engine       = New(NewConfig(config.Hybrid), db, ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+		ether        = big.NewInt(1e18)
+		signer       = types.LatestSigner(config)
+	)
+	defer engine.Close()
+
+	genesis := (&core.Genesis{
+		Config:  config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			sender: {Balance: new(big.Int).Mul(big.NewInt(100), ether)},
+			staking: {
+				Balance: new(big.Int),
+				Code:    []byte{0x00},
+				Storage: stakingStorage([]common.Address{validator, honest}, []*StakingValidator{
+					{SelfStake: new(big.Int).Mul(big.NewInt(1000), ether), TotalDelegated: new(big.Int).Mul(big.NewInt(500), ether), IsActive: true},
+					{SelfStake: new(big.Int).Mul(big.NewInt(50), ether), TotalDelegated: new(big.Int), IsActive: true},
+				}),
+			},
+		},
+	}).MustCommit(db)
+
+	valid, _ := EncodeEvidence(signedVote(t, key, 1, 65, 1, 2), signedVote(t, key, 2, 65, 1, 2))
+	bogus, _ := EncodeEvidence(signedVote(t, key, 1, 65, 1, 2), signedVote(t, key, 2, 97, 2, 3))
+
+	evidenceTx := func(nonce uint64, evidence []byte) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(nonce, EvidenceAddress, new(big.Int), 100000, big.NewInt(2*params.InitialBaseFee), evidence), signer, senderKey)
+		if err != nil {
+			t.Fatalf("failed to sign evidence transaction: %v", err)
+		}
+		return tx
+	}
+	blocks, _ := core.GenerateChain(config, genesis, engine, db, 2, func(i int, b *core.BlockGen) {
+		b.AddTx(evidenceTx(uint64(i), valid)) // already slashed in the second block, must be a no-op
+	})
+	chain, err := core.NewBlockChain(db, nil, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	// Blocks carrying bogus evidence must be rejected
+	for i, tt := range []struct {
+		evidence []byte
+		err      error
+	}{
+		{bogus, ErrNoConflict},
+		{[]byte{0x01, 0x02}, ErrInvalidEvidence},
+	} {
+		forged, _ := core.GenerateChain(config, genesis, engine, db, 1, func(_ int, b *core.BlockGen) {
+			b.AddTx(evidenceTx(0, tt.evidence))
+		})
+		if _, err := chain.InsertChain(forged); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: block with bogus evidence error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	// Valid evidence slashes 10% of the total stake, self stake first
+	for _, block := range blocks {
+		statedb, err := chain.StateAt(block.Root())
+		if err != nil {
+			t.Fatalf("failed to retrieve state: %v", err)
+		}
+		v := ReadStakingValidator(statedb, staking, validator)
+		if v.IsActive || !v.IsSlashed {
+			t.Errorf("block %d: validator not slashed: %+v", block.NumberU64(), v)
+		}
+		if have, want := v.SelfStake, new(big.Int).Mul(big.NewInt(850), ether); have.Cmp(want) != 0 {
+			t.Errorf("block %d: self stake mismatch: have %v, want %v", block.NumberU64(), have, want)
+		}
+		if have, want := v.TotalDelegated, new(big.Int).Mul(big.NewInt(500), ether); have.Cmp(want) != 0 {
+			t.Errorf("block %d: delegated stake mismatch: have %v, want %v", block.NumberU64(), have, want)
+		}
+		if have, want := statedb.GetState(staking, common.BigToHash(big.NewInt(totalStakedSlot))).Big(), new(big.Int).Mul(big.NewInt(1400), ether); have.Cmp(want) != 0 {
+			t.Errorf("block %d: total staked mismatch: have %v, want %v", block.NumberU64(), have, want)
+		}
+		if list := ReadValidatorList(statedb, staking); len(list) != 1 || list[0] != honest {
+			t.Errorf("block %d: validator list mismatch: have %x, want [%x]", block.NumberU64(), list, honest)
+		}
+		if set := ReadValidatorSet(statedb, staking, engine.config.MinStake); set[validator] != nil || set[honest] == nil {
+			t.Errorf("block %d: slashed validator still in the set", block.NumberU64())
+		}
+	}
+}
@@ -8,26 +8,40 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/log"
 	lru "github.com/hashicorp/golang-lru"
 )
 
-// FinalityTracker tracks block finality based on validator attestations.
-// A block is considered finalized when it has attestations from validators
-// representing at least 67% of the total stake.
+// FinalityTracker tracks checkpoint justification and finalization based on
+// validator attestations, Casper FFG style. A checkpoint is justified once
+// validators representing the finality threshold of the total stake voted for
+// a link from an already justified source to it. A justified checkpoint is
+// finalized once its direct child checkpoint is justified through it.
 type FinalityTracker struct {
 	hybrid *Hybrid
 
-	// Finalized blocks: blockNumber -> blockHash
+	// Finalized checkpoint blocks: blockNumber -> blockHash
 	finalized *lru.Cache
 
-	// Last finalized block number
+	// Last finalized block number and whether any checkpoint was finalized
 	lastFinalized uint64
+	hasFinalized  bool
+
+	justified    Checkpoint                                     // Latest justified checkpoint
+	justifiedSet map[Checkpoint]struct{}                        // Justified checkpoints within the tracked range
+	links        map[checkpointLink]map[common.Address]struct{} // Voters of each source -> target link
 
 	log log.Logger
 	mu  sync.RWMutex
 }
 
+// checkpointLink is a vote from a source checkpoint to a target checkpoint.
+type checkpointLink struct {
+	source Checkpoint
+	target Checkpoint
+}
+
 // FinalityStatus represents the finality status of a block.
 type FinalityStatus struct {
 	BlockNumber     uint64         `json:"blockNumber"`
@@ -45,69 +59,131 @@ type FinalityStatus struct {
 func NewFinalityTracker(hybrid *Hybrid) *FinalityTracker {
 	finalized, _ := lru.New(1000)
 	return &FinalityTracker{
-		hybrid:    hybrid,
-		finalized: finalized,
-		log:       log.New("module", "finality"),
+		hybrid:       hybrid,
+		finalized:    finalized,
+		justifiedSet: make(map[Checkpoint]struct{}),
+		links:        make(map[checkpointLink]map[common.Address]struct{}),
+		log:          log.New("module", "finality"),
 	}
 }
 
-// CheckFinality checks if a block has reached finality based on attestations.
-func (ft *FinalityTracker) CheckFinality(blockHash common.Hash, attestations *BlockAttestations) bool {
+// SetAnchor marks the given checkpoint as justified, unless a later checkpoint
+// is already justified. It is used to seed the tracker with the genesis block
+// and with the justified checkpoint persisted before a restart.
+func (ft *FinalityTracker) SetAnchor(checkpoint Checkpoint) {
 	ft.mu.Lock()
 	defer ft.mu.Unlock()
 
-	// Don't re-check already finalized blocks
-	if ft.isFinalized(attestations.BlockNumber) {
-		return true
+	ft.justifiedSet[checkpoint] = struct{}{}
+	if checkpoint.Epoch >= ft.justified.Epoch {
+		ft.justified = checkpoint
 	}
+}
 
-	// Get validators and calculate stake
-	validators := ft.hybrid.GetValidators()
-	totalStake := ft.hybrid.GetTotalStake()
+// Justified returns the latest justified checkpoint.
+func (ft *FinalityTracker) Justified() Checkpoint {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+	return ft.justified
+}
 
-	// If no validators, can't finalize
-	if totalStake.Sign() == 0 {
-		return false
-	}
+// ProcessAttestation counts the FFG vote of an accepted attestation and
+// updates justification and finalization. It returns the newly finalized
+// checkpoint, if any. Attestations whose source equals their target carry no
+// FFG vote.
+func (ft *FinalityTracker) ProcessAttestation(attestation *Attestation) *Checkpoint {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
 
-	// Calculate attesting stake
-	attestingStake := attestations.TotalStake(validators)
+	if attestation.Source.Epoch >= attestation.Target.Epoch {
+		return nil
+	}
+	link := checkpointLink{source: attestation.Source, target: attestation.Target}
+	if ft.links[link] == nil {
+		ft.links[link] = make(map[common.Address]struct{})
+	}
+	ft.links[link][attestation.Validator] = struct{}{}
 
-	// Calculate percentage: (attestingStake * 100) / totalStake
-	percentage := new(big.Int).Mul(attestingStake, big.NewInt(100))
-	percentage.Div(percentage, totalStake)
+	return ft.update()
+}
 
-	threshold := ft.hybrid.config.FinalityThreshold
+// update justifies the targets of all supermajority links from justified
+// sources until no more progress is made, finalizing sources whose direct
+// child checkpoint got justified. It returns the latest newly finalized
+// checkpoint, if any. The lock must be held.
+func (ft *FinalityTracker) update() *Checkpoint {
+	validators := ft.hybrid.GetValidators()
+	totalStake := ft.hybrid.GetTotalStake()
 
-	if percentage.Uint64() >= threshold {
-		// Block is finalized!
-		ft.finalized.Add(attestations.BlockNumber, blockHash)
-		if attestations.BlockNumber > ft.lastFinalized {
-			ft.lastFinalized = attestations.BlockNumber
+	// If no validators, can't justify anything
+	if totalStake.Sign() == 0 {
+		return nil
+	}
+	var finalized *Checkpoint
+	for progress := true; progress; {
+		progress = false
+		for link, voters := range ft.links {
+			if _, ok := ft.justifiedSet[link.source]; !ok {
+				continue
+			}
+			percentage := ft.votePercentage(voters, validators, totalStake)
+			if percentage < ft.hybrid.config.FinalityThreshold {
+				continue
+			}
+			if _, ok := ft.justifiedSet[link.target]; !ok {
+				ft.justify(link.target, len(voters), percentage)
+				progress = true
+			}
+			if link.target.Epoch == link.source.Epoch+1 && (!ft.hasFinalized || link.source.Number() > ft.lastFinalized) {
+				source := link.source
+				ft.finalize(source)
+				finalized = &source
+				progress = true
+			}
 		}
+	}
+	return finalized
+}
 
-		ft.log.Info("Block finalized",
-			"number", attestations.BlockNumber,
-			"hash", blockHash.Hex(),
-			"attesters", len(attestations.Attestations),
-			"stake%", percentage.Uint64(),
-		)
+// votePercentage returns the percentage of the total stake held by the given
+// voters.
+func (ft *FinalityTracker) votePercentage(voters map[common.Address]struct{}, validators map[common.Address]*ValidatorInfo, totalStake *big.Int) uint64 {
+	stake := new(big.Int)
+	for addr := range voters {
+		if v, exists := validators[addr]; exists && v.Active {
+			stake.Add(stake, v.Stake)
+		}
+	}
+	// Calculate percentage: (stake * 100) / totalStake
+	percentage := new(big.Int).Mul(stake, big.NewInt(100))
+	return percentage.Div(percentage, totalStake).Uint64()
+}
 
-		return true
+// justify marks a checkpoint as justified and persists it if it is the latest.
+// The lock must be held.
+func (ft *FinalityTracker) justify(checkpoint Checkpoint, voters int, percentage uint64) {
+	ft.justifiedSet[checkpoint] = struct{}{}
+	if checkpoint.Epoch > ft.justified.Epoch {
+		ft.justified = checkpoint
+		rawdb.WriteHybridJustified(ft.hybrid.db, checkpoint.Epoch, checkpoint.Hash)
 	}
+	ft.log.Info("Checkpoint justified", "epoch", checkpoint.Epoch, "hash", checkpoint.Hash.Hex(), "voters", voters, "stake%", percentage)
+}
 
-	ft.log.Debug("Block not yet finalized",
-		"number", attestations.BlockNumber,
-		"hash", blockHash.Hex(),
-		"attesters", len(attestations.Attestations),
-		"stake%", percentage.Uint64(),
-		"threshold", threshold,
-	)
+// finalize marks a justified checkpoint as finalized and persists it. The lock
+// must be held.
+func (ft *FinalityTracker) finalize(checkpoint Checkpoint) {
+	number := checkpoint.Number()
 
-	return false
+	ft.finalized.Add(number, checkpoint.Hash)
+	ft.lastFinalized, ft.hasFinalized = number, true
+	rawdb.WriteHybridFinalized(ft.hybrid.db, number, checkpoint.Hash)
+
+	ft.log.Info("Checkpoint finalized", "epoch", checkpoint.Epoch, "number", number, "hash", checkpoint.Hash.Hex())
 }
 
-// IsFinalized returns whether a block has been finalized.
+// IsFinalized returns whether a block number is at or below the last finalized
+// checkpoint.
 func (ft *FinalityTracker) IsFinalized(blockNumber uint64) bool {
 	ft.mu.RLock()
 	defer ft.mu.RUnlock()
@@ -116,11 +192,11 @@ func (ft *FinalityTracker) IsFinalized(blockNumber uint64) bool {
 
 // isFinalized is the internal implementation (assumes lock is held).
 func (ft *FinalityTracker) isFinalized(blockNumber uint64) bool {
-	_, ok := ft.finalized.Get(blockNumber)
-	return ok
+	return ft.hasFinalized && blockNumber <= ft.lastFinalized
 }
 
-// GetFinalizedBlock returns the hash of the finalized block at the given number.
+// GetFinalizedBlock returns the hash of the finalized checkpoint block at the
+// given number.
 func (ft *FinalityTracker) GetFinalizedBlock(blockNumber uint64) (common.Hash, bool) {
 	ft.mu.RLock()
 	defer ft.mu.RUnlock()
@@ -140,12 +216,15 @@ func (ft *FinalityTracker) GetLastFinalizedBlock() uint64 {
 
 // GetFinalityStatus returns detailed finality status for a block.
 func (ft *FinalityTracker) GetFinalityStatus(blockNumber uint64, blockHash common.Hash) *FinalityStatus {
-	ft.mu.RLock()
-	defer ft.mu.RUnlock()
-
+	// Query the engine before taking the tracker lock, the engine counts votes
+	// into the tracker after releasing its own locks only
 	validators := ft.hybrid.GetValidators()
 	totalStake := ft.hybrid.GetTotalStake()
 	totalValidators := ft.hybrid.GetActiveValidatorCount()
+	attestations := ft.hybrid.GetAttestations(blockHash)
+
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
 
 	status := &FinalityStatus{
 		BlockNumber:     blockNumber,
@@ -156,8 +235,6 @@ func (ft *FinalityTracker) GetFinalityStatus(blockNumber uint64, blockHash commo
 		Threshold:       ft.hybrid.config.FinalityThreshold,
 	}
 
-	// Get attestations for this block
-	attestations := ft.hybrid.GetAttestations(blockHash)
 	if attestations != nil {
 		status.AttesterCount = attestations.AttesterCount()
 		status.AttestingStake = attestations.TotalStake(validators)
@@ -186,8 +263,8 @@ func (ft *FinalityTracker) MarkFinalized(blockNumber uint64, blockHash common.Ha
 	defer ft.mu.Unlock()
 
 	ft.finalized.Add(blockNumber, blockHash)
-	if blockNumber > ft.lastFinalized {
-		ft.lastFinalized = blockNumber
+	if !ft.hasFinalized || blockNumber > ft.lastFinalized {
+		ft.lastFinalized, ft.hasFinalized = blockNumber, true
 	}
 }
 
@@ -215,6 +292,50 @@ func (ft *FinalityTracker) CanReorg(targetBlockNumber uint64) bool {
 	return targetBlockNumber >= ft.lastFinalized
 }
 
+// finalize marks the given block as the finalized block of the local chain,
+// provided it is canonical and newer than the current finalized block. From
+// then on fork choice refuses any reorg dropping it.
+func (h *Hybrid) finalize(hash common.Hash, number uint64) {
+	h.validatorsLock.RLock()
+	chain := h.chain
+	h.validatorsLock.RUnlock()
+
+	if chain == nil {
+		return
+	}
+	if current := chain.CurrentFinalizedBlock(); current != nil && current.NumberU64() >= number {
+		return
+	}
+	if header := chain.GetHeaderByNumber(number); header == nil || header.Hash() != hash {
+		h.log.Warn("Finalized block is not canonical", "number", number, "hash", hash)
+		return
+	}
+	block := chain.GetBlock(hash, number)
+	if block == nil {
+		return
+	}
+	chain.SetFinalized(block)
+	h.log.Info("Updated finalized block", "number", number, "hash", hash)
+}
+
+// PruneVotes forgets the votes for, and the justification of, checkpoints below
+// the given block number. The latest justified checkpoint is always retained.
+func (ft *FinalityTracker) PruneVotes(beforeBlock uint64) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	for link := range ft.links {
+		if link.target.Number() < beforeBlock {
+			delete(ft.links, link)
+		}
+	}
+	for checkpoint := range ft.justifiedSet {
+		if checkpoint.Number() < beforeBlock && checkpoint != ft.justified {
+			delete(ft.justifiedSet, checkpoint)
+		}
+	}
+}
+
 // PruneOldBlocks removes finality data for blocks older than the given number.
 func (ft *FinalityTracker) PruneOldBlocks(beforeBlock uint64) {
 	ft.mu.Lock()
@@ -19,23 +19,31 @@
 // attestations. Miners create blocks using PoW, and validators with 32+ ALT stake
 // attest to blocks for finality.
 //
-// Block Rewards (2 ALT total per block):
+// Block Rewards (2 ALT total per block, split by the configured percentages):
 //   - 1 ALT to the PoW miner who found the block
-//   - 1 ALT to the PoS validator pool (distributed based on stake)
+//   - 1 ALT to the PoS validators that attested the parent block (distributed
+//     based on stake)
 package hybrid
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
 	lru "github.com/hashicorp/golang-lru"
 )
 
@@ -50,6 +58,17 @@ var (
 	ErrValidatorNotActive = errors.New("validator not active")
 	// ErrInsufficientStake is returned when validator has insufficient stake
 	ErrInsufficientStake = errors.New("insufficient stake")
+	// ErrInvalidCheckpoint is returned when an attestation's source or target
+	// checkpoint doesn't match the attested block
+	ErrInvalidCheckpoint = errors.New("invalid checkpoint in attestation")
+	// ErrUnknownCheckpoint is returned when a checkpoint block is not available locally
+	ErrUnknownCheckpoint = errors.New("unknown checkpoint block")
+	// ErrUnknownValidatorSet is returned when the state of an attested block is
+	// not available locally to derive its validator set from
+	ErrUnknownValidatorSet = errors.New("unknown validator set")
+	// ErrSlashableAttestation is returned when an attestation conflicts with an
+	// earlier one of the same validator
+	ErrSlashableAttestation = errors.New("slashable attestation")
 
 	// HybridBlockReward is the total block reward in hybrid mode (2 ALT)
 	HybridBlockReward = big.NewInt(2e18)
@@ -59,6 +78,33 @@ var (
 	HybridValidatorReward = big.NewInt(1e18)
 )
 
+const (
+	// inmemoryValidatorSets is the number of recent validator sets to keep in memory.
+	inmemoryValidatorSets = 128
+)
+
+// ChainReader defines the blockchain access needed by the hybrid engine to
+// track the validator set stored in the staking contract.
+type ChainReader interface {
+	consensus.ChainHeaderReader
+
+	// StateAt returns a state database for the given state root.
+	StateAt(root common.Hash) (*state.StateDB, error)
+
+	// SubscribeChainHeadEvent registers a subscription for new canonical heads.
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+
+	// GetBlock retrieves a block from the database by hash and number.
+	GetBlock(hash common.Hash, number uint64) *types.Block
+
+	// CurrentFinalizedBlock retrieves the current finalized block.
+	CurrentFinalizedBlock() *types.Block
+
+	// SetFinalized sets the finalized block, which fork choice will never
+	// reorg out of the canonical chain.
+	SetFinalized(block *types.Block)
+}
+
 // Config contains the configuration parameters of the hybrid consensus engine.
 type Config struct {
 	// Period is the minimum time between blocks (in seconds)
@@ -90,10 +136,40 @@ func DefaultConfig() *Config {
 	}
 }
 
+// NewConfig converts the chain configuration's hybrid section into an engine
+// configuration. Unset fields fall back to the values of DefaultConfig.
+func NewConfig(cfg *params.HybridConfig) *Config {
+	config := DefaultConfig()
+	if cfg == nil {
+		return config
+	}
+	if cfg.Period != 0 {
+		config.Period = cfg.Period
+	}
+	if cfg.FinalityThreshold != 0 {
+		config.FinalityThreshold = cfg.FinalityThreshold
+	}
+	if cfg.AttestationWindow != 0 {
+		config.AttestationWindow = cfg.AttestationWindow
+	}
+	if cfg.StakingContract != (common.Address{}) {
+		config.StakingContract = cfg.StakingContract
+	}
+	if cfg.MinStake != nil {
+		config.MinStake = new(big.Int).Set(cfg.MinStake.ToInt())
+	}
+	if cfg.MinerRewardPercent != 0 || cfg.ValidatorRewardPercent != 0 {
+		config.MinerRewardPercent = cfg.MinerRewardPercent
+		config.ValidatorRewardPercent = cfg.ValidatorRewardPercent
+	}
+	return config
+}
+
 // Hybrid is a hybrid PoW/PoS consensus engine.
 // It wraps ethash for PoW block production and adds PoS finality.
 type Hybrid struct {
 	config *Config
+	db     ethdb.Database // Database to persist attestations, finality and offenses
 	ethash *ethash.Ethash
 
 	// Attestation tracking
@@ -102,8 +178,14 @@ type Hybrid struct {
 
 	// Validator tracking
 	validators     map[common.Address]*ValidatorInfo
+	validatorSets  *lru.Cache // blockHash -> map[common.Address]*ValidatorInfo
 	validatorsLock sync.RWMutex
 
+	chain     ChainReader   // Blockchain used to load validator sets, nil until Start
+	quit      chan struct{} // Termination channel of the head tracking loop
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+
 	// Finality tracking
 	finalityTracker *FinalityTracker
 
@@ -113,6 +195,9 @@ type Hybrid struct {
 	// Slashing detection
 	slashingDetector *SlashingDetector
 
+	signer common.Address // Miner account signing block payload transactions
+	signFn SignTxFn       // Signer function to authorize payload transactions with
+
 	log log.Logger
 	mu  sync.RWMutex
 }
@@ -126,21 +211,25 @@ type ValidatorInfo struct {
 }
 
 // New creates a new hybrid consensus engine.
-func New(config *Config, ethashConfig ethash.Config, notify []string, noverify bool) *Hybrid {
+func New(config *Config, db ethdb.Database, ethashConfig ethash.Config, notify []string, noverify bool) *Hybrid {
 	if config == nil {
 		config = DefaultConfig()
 	}
 
 	attestations, _ := lru.New(int(config.AttestationWindow * 2))
 	finalized, _ := lru.New(1000)
+	validatorSets, _ := lru.New(inmemoryValidatorSets)
 
 	h := &Hybrid{
-		config:       config,
-		ethash:       ethash.New(ethashConfig, notify, noverify),
-		attestations: attestations,
-		finalized:    finalized,
-		validators:   make(map[common.Address]*ValidatorInfo),
-		log:          log.New("consensus", "hybrid"),
+		config:        config,
+		db:            db,
+		ethash:        ethash.New(ethashConfig, notify, noverify),
+		attestations:  attestations,
+		finalized:     finalized,
+		validators:    make(map[common.Address]*ValidatorInfo),
+		validatorSets: validatorSets,
+		quit:          make(chan struct{}),
+		log:           log.New("consensus", "hybrid"),
 	}
 
 	h.finalityTracker = NewFinalityTracker(h)
@@ -154,14 +243,18 @@ func NewFaker() *Hybrid {
 	config := DefaultConfig()
 	attestations, _ := lru.New(int(config.AttestationWindow * 2))
 	finalized, _ := lru.New(1000)
+	validatorSets, _ := lru.New(inmemoryValidatorSets)
 
 	h := &Hybrid{
-		config:       config,
-		ethash:       ethash.NewFaker(),
-		attestations: attestations,
-		finalized:    finalized,
-		validators:   make(map[common.Address]*ValidatorInfo),
-		log:          log.New("consensus", "hybrid"),
+		config:        config,
+		db:            rawdb.NewMemoryDatabase(),
+		ethash:        ethash.NewFaker(),
+		attestations:  attestations,
+		finalized:     finalized,
+		validators:    make(map[common.Address]*ValidatorInfo),
+		validatorSets: validatorSets,
+		quit:          make(chan struct{}),
+		log:           log.New("consensus", "hybrid"),
 	}
 
 	h.finalityTracker = NewFinalityTracker(h)
@@ -187,8 +280,15 @@ func (h *Hybrid) VerifyHeaders(chain consensus.ChainHeaderReader, headers []*typ
 	return h.ethash.VerifyHeaders(chain, headers, seals)
 }
 
-// VerifyUncles verifies that the given block's uncles conform to the consensus rules.
+// VerifyUncles verifies that the uncles of a block conform to the PoW rules and
+// that every slashing evidence transaction of a hybrid block carries valid
+// evidence, bogus evidence invalidates the whole block.
 func (h *Hybrid) VerifyUncles(chain consensus.ChainReader, block *types.Block) error {
+	for i, tx := range block.Transactions() {
+		if err := h.VerifyTransaction(chain, block.Header(), tx); err != nil {
+			return fmt.Errorf("transaction %d: %w", i, err)
+		}
+	}
 	return h.ethash.VerifyUncles(chain, block)
 }
 
@@ -198,46 +298,23 @@ func (h *Hybrid) Prepare(chain consensus.ChainHeaderReader, header *types.Header
 }
 
 // Finalize runs any post-transaction state modifications (e.g. block rewards).
-// In hybrid mode, this distributes rewards between miners and validators.
+// In hybrid mode, this slashes validators proven to misbehave by evidence
+// included in the block and distributes rewards between miners and validators.
 func (h *Hybrid) Finalize(chain consensus.ChainHeaderReader, header *types.Header, statedb *state.StateDB, txs []*types.Transaction, uncles []*types.Header) {
 	config := chain.Config()
 
 	// Check if hybrid consensus is active
-	if config.Hybrid == nil || !config.IsHybrid(header.Number) {
+	if !config.IsHybrid(header.Number) {
 		// Fall back to pure ethash
 		h.ethash.Finalize(chain, header, statedb, txs, uncles)
 		return
 	}
 
-	// Hybrid block rewards: 1 ALT to PoW miner, 1 ALT to PoS validators
-	// Total: 2 ALT per block
-	minerReward := new(big.Int).Set(HybridMinerReward)
-	validatorReward := new(big.Int).Set(HybridValidatorReward)
-
-	// Calculate uncle rewards (reduced in hybrid mode)
-	// Uncle creators get 1/8 of miner reward, miner gets small bonus
-	r := new(big.Int)
-	for _, uncle := range uncles {
-		r.Add(uncle.Number, big.NewInt(8))
-		r.Sub(r, header.Number)
-		r.Mul(r, HybridMinerReward)
-		r.Div(r, big.NewInt(8))
-		// Uncle reward
-		uncleCreatorReward := new(big.Int).Set(r)
-		statedb.AddBalance(uncle.Coinbase, uncleCreatorReward)
-
-		// Miner gets 1/32 of miner reward per uncle included
-		r.Div(HybridMinerReward, big.NewInt(32))
-		minerReward.Add(minerReward, r)
-	}
-
-	// Credit miner
-	statedb.AddBalance(header.Coinbase, minerReward)
+	// Slash the validators proven to misbehave by the evidence in the block
+	h.applyEvidence(config.ChainID, header, statedb, txs)
 
-	// Credit validator rewards to the staking contract
-	// Validators can claim their share proportionally based on stake
-	stakingContract := h.config.StakingContract
-	statedb.AddBalance(stakingContract, validatorReward)
+	// Split the block reward between the miner and the attesting validators
+	minerReward, validatorReward := h.accumulateRewards(config.ChainID, header, statedb, txs, uncles)
 
 	// Store the validator reward for tracking/logging
 	h.mu.Lock()
@@ -247,19 +324,19 @@ func (h *Hybrid) Finalize(chain consensus.ChainHeaderReader, header *types.Heade
 	h.log.Debug("Hybrid block finalized",
 		"block", header.Number,
 		"minerReward", minerReward,
-		"validatorReward", validatorReward,
-		"stakingContract", stakingContract)
+		"validatorReward", validatorReward)
 
 	header.Root = statedb.IntermediateRoot(config.IsEIP158(header.Number))
 }
 
 // FinalizeAndAssemble runs any post-transaction state modifications and assembles the final block.
 func (h *Hybrid) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, statedb *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
-	// Finalize block
+	// Finalize block. Rewards are credited here only, the embedded ethash
+	// engine must not finalize the block a second time.
 	h.Finalize(chain, header, statedb, txs, uncles)
 
 	// Assemble and return the final block
-	return h.ethash.FinalizeAndAssemble(chain, header, statedb, txs, uncles, receipts)
+	return types.NewBlock(header, txs, uncles, receipts, trie.NewStackTrie(nil)), nil
 }
 
 // Seal generates a new sealing request for the given input block.
@@ -281,7 +358,7 @@ func (h *Hybrid) CalcDifficulty(chain consensus.ChainHeaderReader, time uint64,
 func (h *Hybrid) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 	apis := h.ethash.APIs(chain)
 
-	// Add hybrid-specific APIs
+	// Add hybrid-specific APIs, merged with the validator API of the eth service
 	apis = append(apis, rpc.API{
 		Namespace: "validator",
 		Service:   NewAPI(h, chain),
@@ -292,6 +369,10 @@ func (h *Hybrid) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 
 // Close terminates any background threads maintained by the consensus engine.
 func (h *Hybrid) Close() error {
+	h.closeOnce.Do(func() {
+		close(h.quit)
+	})
+	h.wg.Wait()
 	return h.ethash.Close()
 }
 
@@ -300,8 +381,33 @@ func (h *Hybrid) Hashrate() float64 {
 	return h.ethash.Hashrate()
 }
 
+// Threads returns the number of mining threads currently enabled.
+func (h *Hybrid) Threads() int {
+	return h.ethash.Threads()
+}
+
+// SetThreads updates the number of mining threads of the embedded PoW engine.
+func (h *Hybrid) SetThreads(threads int) {
+	h.ethash.SetThreads(threads)
+}
+
 // AddAttestation adds a new attestation from a validator.
 func (h *Hybrid) AddAttestation(attestation *Attestation) error {
+	if err := h.storeAttestation(attestation); err != nil {
+		return err
+	}
+	// Count the checkpoint vote, pinning any newly finalized checkpoint in the local
+	// chain. The finality tracker reads attestations back while holding its own
+	// lock, so the vote must not be counted while holding h.mu.
+	if checkpoint := h.finalityTracker.ProcessAttestation(attestation); checkpoint != nil {
+		h.finalize(checkpoint.Hash, checkpoint.Number())
+	}
+	return nil
+}
+
+// storeAttestation verifies an attestation and adds it to the attestations of
+// the attested block.
+func (h *Hybrid) storeAttestation(attestation *Attestation) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -313,7 +419,7 @@ func (h *Hybrid) AddAttestation(attestation *Attestation) error {
 	// Check for slashing conditions
 	if slashable := h.slashingDetector.CheckAttestation(attestation); slashable != nil {
 		h.log.Warn("Slashable attestation detected", "validator", attestation.Validator, "reason", slashable.Reason)
-		return ErrInvalidAttestation
+		return ErrSlashableAttestation
 	}
 
 	// Get or create block attestations
@@ -337,31 +443,51 @@ func (h *Hybrid) AddAttestation(attestation *Attestation) error {
 	// Add attestation
 	blockAttestations.Attestations[attestation.Validator] = attestation
 	h.attestations.Add(blockHash, blockAttestations)
+	h.writeAttestations(blockAttestations)
 
 	// Update validator's last attestation
+	h.validatorsLock.Lock()
 	if validator, exists := h.validators[attestation.Validator]; exists {
 		validator.LastAttestation = attestation.BlockNumber
 	}
-
-	// Check if block is now finalized
-	h.finalityTracker.CheckFinality(blockHash, blockAttestations)
+	h.validatorsLock.Unlock()
 
 	h.log.Debug("Attestation added", "block", blockHash, "validator", attestation.Validator, "total", len(blockAttestations.Attestations))
 
 	return nil
 }
 
+// chainID returns the ID of the chain attestations are signed for, or nil if
+// the engine is not attached to a chain yet.
+func (h *Hybrid) chainID() *big.Int {
+	h.validatorsLock.RLock()
+	defer h.validatorsLock.RUnlock()
+
+	if h.chain == nil {
+		return nil
+	}
+	return h.chain.Config().ChainID
+}
+
 // verifyAttestation verifies an attestation is valid.
 func (h *Hybrid) verifyAttestation(attestation *Attestation) error {
-	// Verify signature
-	if !attestation.VerifySignature() {
+	// Verify the signature was made for the local chain
+	chainID := h.chainID()
+	if chainID == nil || !attestation.VerifySignature(chainID) {
 		return ErrInvalidAttestation
 	}
 
-	// Check validator is active
-	h.validatorsLock.RLock()
-	validator, exists := h.validators[attestation.Validator]
-	h.validatorsLock.RUnlock()
+	// Check the source and target checkpoints match the attested block
+	if err := h.verifyCheckpoints(attestation); err != nil {
+		return err
+	}
+
+	// Check validator is active in the set derived from the attested block
+	validators, err := h.validatorSetAt(attestation.BlockHash)
+	if err != nil {
+		return err
+	}
+	validator, exists := validators[attestation.Validator]
 
 	if !exists || !validator.Active {
 		return ErrValidatorNotActive
@@ -375,6 +501,28 @@ func (h *Hybrid) verifyAttestation(attestation *Attestation) error {
 	return nil
 }
 
+// verifyCheckpoints verifies that the target checkpoint of an attestation is the
+// epoch boundary block on the chain of the attested block and that the source
+// checkpoint doesn't come after it. Attestations for blocks not available
+// locally are rejected.
+func (h *Hybrid) verifyCheckpoints(attestation *Attestation) error {
+	source, target := attestation.Source, attestation.Target
+	if target.Epoch != EpochOf(attestation.BlockNumber) {
+		return ErrInvalidCheckpoint
+	}
+	if source.Epoch > target.Epoch || (source.Epoch == target.Epoch && source != target) {
+		return ErrInvalidCheckpoint
+	}
+	want, err := h.targetCheckpoint(attestation.BlockHash, attestation.BlockNumber)
+	if err != nil {
+		return err
+	}
+	if target != want {
+		return ErrInvalidCheckpoint
+	}
+	return nil
+}
+
 // GetAttestations returns all attestations for a block.
 func (h *Hybrid) GetAttestations(blockHash common.Hash) *BlockAttestations {
 	h.mu.RLock()
@@ -391,6 +539,16 @@ func (h *Hybrid) IsFinalized(blockNumber uint64) bool {
 	return h.finalityTracker.IsFinalized(blockNumber)
 }
 
+// FinalityStatus returns the attestation progress of a block.
+func (h *Hybrid) FinalityStatus(blockNumber uint64, blockHash common.Hash) *FinalityStatus {
+	return h.finalityTracker.GetFinalityStatus(blockNumber, blockHash)
+}
+
+// Justified returns the latest justified checkpoint.
+func (h *Hybrid) Justified() Checkpoint {
+	return h.finalityTracker.Justified()
+}
+
 // GetFinalizedBlock returns the hash of the finalized block at the given number.
 func (h *Hybrid) GetFinalizedBlock(blockNumber uint64) (common.Hash, bool) {
 	return h.finalityTracker.GetFinalizedBlock(blockNumber)
@@ -0,0 +1,601 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+//
+// The go-altcoinchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-altcoinchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-altcoinchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package hybrid
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// hybridTestConfig returns a chain config that switches to hybrid consensus
+// at the given block.
+func hybridTestConfig(fork int64, staking common.Address) *params.ChainConfig {
+	config := *params.TestChainConfig
+	config.HybridBlock = big.NewInt(fork)
+	config.Hybrid = &params.HybridConfig{
+		Period:                 12,
+		FinalityThreshold:      67,
+		AttestationWindow:      32,
+		StakingContract:        staking,
+		MinStake:               (*hexutil.Big)(new(big.Int).Mul(big.NewInt(32), big.NewInt(1e18))),
+		MinerRewardPercent:     50,
+		ValidatorRewardPercent: 50,
+	}
+	return &config
+}
+
+func TestNewConfig(t *testing.T) {
+	staking := common.HexToAddress("0x139fa30605591055aceada5e841a2252d33b14c7")
+	config := NewConfig(hybridTestConfig(0, staking).Hybrid)
+
+	if config.Period != 12 {
+		t.Errorf("period mismatch: have %d, want %d", config.Period, 12)
+	}
+	if config.StakingContract != staking {
+		t.Errorf("staking contract mismatch: have %x, want %x", config.StakingContract, staking)
+	}
+	if config.MinerRewardPercent != 50 || config.ValidatorRewardPercent != 50 {
+		t.Errorf("reward split mismatch: have %d/%d, want 50/50", config.MinerRewardPercent, config.ValidatorRewardPercent)
+	}
+	// A missing hybrid section must fall back to the defaults
+	if have, want := NewConfig(nil).FinalityThreshold, DefaultConfig().FinalityThreshold; have != want {
+		t.Errorf("default threshold mismatch: have %d, want %d", have, want)
+	}
+}
+
+// Tests that a chain can be mined and imported across the hybrid fork block and
+// that block rewards switch from ethash to the hybrid split at the boundary.
+func TestRewardsAcrossHybridFork(t *testing.T) {
+	var (
+		fork    = int64(3)
+		blocks  = 6
+		miner   = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		staking = common.HexToAddress("0x139fa30605591055aceada5e841a2252d33b14c7")
+		config  = hybridTestConfig(fork, staking)
+		db      = rawdb.NewMemoryDatabase()
+		engine  = New(NewConfig(config.Hybrid), db, ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+		genesis = (&core.Genesis{Config: config, BaseFee: big.NewInt(params.InitialBaseFee)}).MustCommit(db)
+	)
+	defer engine.Close()
+
+	chain, _ := core.GenerateChain(config, genesis, engine, db, blocks, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(miner)
+	})
+
+	// Import the generated chain into a fresh node, running the engine again
+	diskdb := rawdb.NewMemoryDatabase()
+	(&core.Genesis{Config: config, BaseFee: big.NewInt(params.InitialBaseFee)}).MustCommit(diskdb)
+
+	bc, err := core.NewBlockChain(diskdb, nil, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer bc.Stop()
+
+	if n, err := bc.InsertChain(chain); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	state, err := bc.State()
+	if err != nil {
+		t.Fatalf("failed to retrieve head state: %v", err)
+	}
+	var (
+		preFork  = fork - 1
+		postFork = int64(blocks) - preFork
+	)
+	wantMiner := new(big.Int).Mul(ethash.ConstantinopleBlockReward, big.NewInt(preFork))
+	wantMiner.Add(wantMiner, new(big.Int).Mul(HybridMinerReward, big.NewInt(postFork)))
+	if have := state.GetBalance(miner); have.Cmp(wantMiner) != 0 {
+		t.Errorf("miner balance mismatch: have %v, want %v", have, wantMiner)
+	}
+	wantStaking := new(big.Int).Mul(HybridValidatorReward, big.NewInt(postFork))
+	if have := state.GetBalance(staking); have.Cmp(wantStaking) != 0 {
+		t.Errorf("staking contract balance mismatch: have %v, want %v", have, wantStaking)
+	}
+	// Without attesting validators, the validator rewards go to the reward pool
+	if have := state.GetState(staking, common.BigToHash(big.NewInt(rewardPoolSlot))).Big(); have.Cmp(wantStaking) != 0 {
+		t.Errorf("reward pool mismatch: have %v, want %v", have, wantStaking)
+	}
+}
+
+// stakingStorage lays out the given validators the way the ValidatorStaking
+// contract stores them.
+func stakingStorage(validators []common.Address, stakes []*StakingValidator) map[common.Hash]common.Hash {
+	storage := map[common.Hash]common.Hash{
+		common.BigToHash(big.NewInt(validatorListSlot)):   common.BigToHash(big.NewInt(int64(len(validators)))),
+		common.BigToHash(big.NewInt(totalValidatorsSlot)): common.BigToHash(big.NewInt(int64(len(validators)))),
+	}
+	totalStaked := new(big.Int)
+	for i, addr := range validators {
+		storage[arraySlot(validatorListSlot, uint64(i))] = common.BytesToHash(addr.Bytes())
+		storage[mappingSlot(addr, validatorIndexSlot)] = common.BigToHash(big.NewInt(int64(i + 1)))
+		totalStaked.Add(totalStaked, stakes[i].TotalStake())
+
+		var (
+			v     = stakes[i]
+			base  = mappingSlot(addr, validatorsSlot)
+			flags common.Hash
+		)
+		if v.IsActive {
+			flags[common.HashLength-1] = 1
+		}
+		if v.IsSlashed {
+			flags[common.HashLength-2] = 1
+		}
+		storage[offsetSlot(base, selfStakeOffset)] = common.BigToHash(v.SelfStake)
+		storage[offsetSlot(base, totalDelegatedOffset)] = common.BigToHash(v.TotalDelegated)
+		storage[offsetSlot(base, commissionOffset)] = common.BigToHash(new(big.Int).SetUint64(v.Commission))
+		storage[offsetSlot(base, lastActiveBlockOffset)] = common.BigToHash(new(big.Int).SetUint64(v.LastActiveBlock))
+		storage[offsetSlot(base, flagsOffset)] = flags
+	}
+	storage[common.BigToHash(big.NewInt(totalStakedSlot))] = common.BigToHash(totalStaked)
+	return storage
+}
+
+// Tests that the validator set is derived from the staking contract storage of
+// the canonical head and used to accept or reject attestations.
+func TestValidatorSetFromStakingContract(t *testing.T) {
+	var (
+		key, _   = crypto.GenerateKey()
+		active   = crypto.PubkeyToAddress(key.PublicKey)
+		slashed  = common.HexToAddress("0x2000000000000000000000000000000000000002")
+		delegate = common.HexToAddress("0x3000000000000000000000000000000000000003")
+		poor     = common.HexToAddress("0x4000000000000000000000000000000000000004")
+		staking  = common.HexToAddress("0x139fa30605591055aceada5e841a2252d33b14c7")
+		config   = hybridTestConfig(0, staking)
+		db       = rawdb.NewMemoryDatabase()
+		engine   = New(NewConfig(config.Hybrid), db, ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+		ether    = big.NewInt(1e18)
+	)
+	defer engine.Close()
+
+	stake := func(self, delegated int64) (*big.Int, *big.Int) {
+		return new(big.Int).Mul(big.NewInt(self), ether), new(big.Int).Mul(big.NewInt(delegated), ether)
+	}
+	var stakes []*StakingValidator
+	for _, v := range []struct {
+		self, delegated int64
+		active, slashed bool
+	}{
+		{1000, 0, true, false},
+		{1000, 0, false, true},
+		{20, 20, true, false},
+		{20, 0, true, false},
+	} {
+		self, delegated := stake(v.self, v.delegated)
+		stakes = append(stakes, &StakingValidator{SelfStake: self, TotalDelegated: delegated, IsActive: v.active, IsSlashed: v.slashed})
+	}
+	genesis := &core.Genesis{
+		Config:  config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			staking: {
+				Balance: new(big.Int),
+				Code:    []byte{0x00},
+				Storage: stakingStorage([]common.Address{active, slashed, delegate, poor}, stakes),
+			},
+		},
+	}
+	genesis.MustCommit(db)
+
+	chain, err := core.NewBlockChain(db, nil, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	engine.Start(chain)
+
+	validators := engine.GetValidators()
+	if len(validators) != 4 {
+		t.Fatalf("validator count mismatch: have %d, want %d", len(validators), 4)
+	}
+	for addr, want := range map[common.Address]bool{active: true, slashed: false, delegate: true, poor: false} {
+		if have := validators[addr].Active; have != want {
+			t.Errorf("validator %x: active mismatch: have %v, want %v", addr, have, want)
+		}
+	}
+	if have, want := validators[delegate].Stake, new(big.Int).Mul(big.NewInt(40), ether); have.Cmp(want) != 0 {
+		t.Errorf("delegated stake mismatch: have %v, want %v", have, want)
+	}
+	// Attestations are only accepted from active validators
+	head := chain.CurrentHeader()
+
+	source, target, err := engine.Checkpoints(head)
+	if err != nil {
+		t.Fatalf("failed to derive checkpoints: %v", err)
+	}
+	att := NewAttestation(active, head.Hash(), head.Number.Uint64(), source, target)
+	if err := att.Sign(key, params.TestChainConfig.ChainID); err != nil {
+		t.Fatalf("failed to sign attestation: %v", err)
+	}
+	if err := engine.AddAttestation(att); err != nil {
+		t.Errorf("attestation from active validator rejected: %v", err)
+	}
+	// Attestations signed for another chain must not be replayable
+	replayed := NewAttestation(active, head.Hash(), head.Number.Uint64(), source, target)
+	if err := replayed.Sign(key, big.NewInt(2)); err != nil {
+		t.Fatalf("failed to sign attestation: %v", err)
+	}
+	if err := engine.AddAttestation(replayed); err != ErrInvalidAttestation {
+		t.Errorf("attestation from another chain: have %v, want %v", err, ErrInvalidAttestation)
+	}
+	otherKey, _ := crypto.GenerateKey()
+	att = NewAttestation(crypto.PubkeyToAddress(otherKey.PublicKey), head.Hash(), head.Number.Uint64(), source, target)
+	if err := att.Sign(otherKey, params.TestChainConfig.ChainID); err != nil {
+		t.Fatalf("failed to sign attestation: %v", err)
+	}
+	if err := engine.AddAttestation(att); err != ErrValidatorNotActive {
+		t.Errorf("attestation from unknown validator: have %v, want %v", err, ErrValidatorNotActive)
+	}
+}
+
+// Tests that a checkpoint finalized by validator attestations is persisted as
+// the finalized block of the chain and can't be reorged away by a heavier PoW
+// fork.
+func TestFinalityPreventsReorg(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		validator = crypto.PubkeyToAddress(key.PublicKey)
+		staking   = common.HexToAddress("0x139fa30605591055aceada5e841a2252d33b14c7")
+		config    = hybridTestConfig(0, staking)
+		db        = rawdb.NewMemoryDatabase()
+		engine    = New(NewConfig(config.Hybrid), db, ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+		stake     = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	)
+	defer engine.Close()
+
+	genesis := (&core.Genesis{
+		Config:  config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			staking: {
+				Balance: new(big.Int),
+				Code:    []byte{0x00},
+				Storage: stakingStorage([]common.Address{validator}, []*StakingValidator{{SelfStake: stake, TotalDelegated: new(big.Int), IsActive: true}}),
+			},
+		},
+	}).MustCommit(db)
+
+	// Blocks 1..66, spanning the checkpoints of epochs 1 and 2
+	canonical, _ := core.GenerateChain(config, genesis, engine, db, 2*EpochLength+2, nil)
+	heavier, _ := core.GenerateChain(config, canonical[EpochLength-2], engine, db, 2*EpochLength, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{0x01})
+	})
+	extension, _ := core.GenerateChain(config, canonical[EpochLength+7], engine, db, EpochLength, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{0x02})
+	})
+
+	chain, err := core.NewBlockChain(db, nil, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	engine.Start(chain)
+
+	if n, err := chain.InsertChain(canonical); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	// Justify epoch 1 and then epoch 2 with the votes of the only validator,
+	// finalizing the checkpoint of epoch 1
+	checkpoint := canonical[EpochLength-1]
+	for _, block := range []*types.Block{canonical[EpochLength], canonical[2*EpochLength]} {
+		source, target, err := engine.Checkpoints(block.Header())
+		if err != nil {
+			t.Fatalf("failed to derive checkpoints of block %d: %v", block.NumberU64(), err)
+		}
+		att := NewAttestation(validator, block.Hash(), block.NumberU64(), source, target)
+		if err := att.Sign(key, params.TestChainConfig.ChainID); err != nil {
+			t.Fatalf("failed to sign attestation: %v", err)
+		}
+		if err := engine.AddAttestation(att); err != nil {
+			t.Fatalf("failed to add attestation: %v", err)
+		}
+	}
+	if have, want := engine.finalityTracker.Justified(), (Checkpoint{Epoch: 2, Hash: canonical[2*EpochLength-1].Hash()}); have != want {
+		t.Errorf("justified checkpoint mismatch: have %v, want %v", have, want)
+	}
+	if have := chain.CurrentFinalizedBlock(); have == nil || have.Hash() != checkpoint.Hash() {
+		t.Fatalf("finalized block mismatch: have %v, want %x", have, checkpoint.Hash())
+	}
+	if have := rawdb.ReadFinalizedBlockHash(db); have != checkpoint.Hash() {
+		t.Errorf("persisted finalized hash mismatch: have %x, want %x", have, checkpoint.Hash())
+	}
+	// A heavier fork dropping the finalized block must be stored but not adopted
+	if n, err := chain.InsertChain(heavier); err != nil {
+		t.Fatalf("failed to insert fork block %d: %v", n, err)
+	}
+	if have, want := chain.CurrentBlock().Hash(), canonical[len(canonical)-1].Hash(); have != want {
+		t.Errorf("head reorged below finalized block: have %x, want %x", have, want)
+	}
+	// A heavier fork building on top of the finalized block is still adopted
+	if n, err := chain.InsertChain(extension); err != nil {
+		t.Fatalf("failed to insert extension block %d: %v", n, err)
+	}
+	if have, want := chain.CurrentBlock().Hash(), extension[len(extension)-1].Hash(); have != want {
+		t.Errorf("head mismatch: have %x, want %x", have, want)
+	}
+}
+
+// Tests that attestations with mismatching checkpoints are rejected.
+func TestAttestationCheckpoints(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		validator = crypto.PubkeyToAddress(key.PublicKey)
+		staking   = common.HexToAddress("0x139fa30605591055aceada5e841a2252d33b14c7")
+		config    = hybridTestConfig(0, staking)
+		db        = rawdb.NewMemoryDatabase()
+		engine    = New(NewConfig(config.Hybrid), db, ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+		stake     = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	)
+	defer engine.Close()
+
+	genesis := (&core.Genesis{
+		Config:  config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			staking: {
+				Balance: new(big.Int),
+				Code:    []byte{0x00},
+				Storage: stakingStorage([]common.Address{validator}, []*StakingValidator{{SelfStake: stake, TotalDelegated: new(big.Int), IsActive: true}}),
+			},
+		},
+	}).MustCommit(db)
+
+	blocks, _ := core.GenerateChain(config, genesis, engine, db, EpochLength+2, nil)
+	chain, err := core.NewBlockChain(db, nil, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	engine.Start(chain)
+
+	var (
+		block    = blocks[EpochLength]
+		genesisC = Checkpoint{Epoch: 0, Hash: genesis.Hash()}
+		epochC   = Checkpoint{Epoch: 1, Hash: blocks[EpochLength-1].Hash()}
+	)
+	tests := []struct {
+		source, target Checkpoint
+		err            error
+	}{
+		{genesisC, epochC, nil},
+		{genesisC, Checkpoint{Epoch: 1, Hash: block.Hash()}, ErrInvalidCheckpoint},
+		{genesisC, Checkpoint{Epoch: 0, Hash: genesis.Hash()}, ErrInvalidCheckpoint},
+		{Checkpoint{Epoch: 2}, epochC, ErrInvalidCheckpoint},
+		{Checkpoint{Epoch: 1, Hash: genesis.Hash()}, epochC, ErrInvalidCheckpoint},
+	}
+	for i, tt := range tests {
+		att := NewAttestation(validator, block.Hash(), block.NumberU64(), tt.source, tt.target)
+		if err := att.Sign(key, params.TestChainConfig.ChainID); err != nil {
+			t.Fatalf("test %d: failed to sign attestation: %v", i, err)
+		}
+		if err := engine.verifyAttestation(att); err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	// Attestations of unknown blocks can't be checked against their checkpoints
+	att := NewAttestation(validator, common.Hash{0x01}, block.NumberU64(), genesisC, epochC)
+	if err := att.Sign(key, params.TestChainConfig.ChainID); err != nil {
+		t.Fatalf("failed to sign attestation: %v", err)
+	}
+	if err := engine.verifyAttestation(att); err != ErrUnknownCheckpoint {
+		t.Errorf("unknown block error mismatch: have %v, want %v", err, ErrUnknownCheckpoint)
+	}
+}
+
+// Tests that the slashing detector catches double votes on a target epoch as
+// well as surrounding and surrounded votes, and records both conflicting
+// attestations as evidence.
+func TestSurroundVoteDetection(t *testing.T) {
+	vote := func(validator common.Address, source, target uint64) *Attestation {
+		return NewAttestation(validator, common.Hash{byte(source), byte(target)}, target*EpochLength+source,
+			Checkpoint{Epoch: source, Hash: common.Hash{byte(source)}},
+			Checkpoint{Epoch: target, Hash: common.Hash{byte(target), byte(source)}})
+	}
+	tests := []struct {
+		votes  [][2]uint64
+		reason SlashingReason
+	}{
+		{[][2]uint64{{1, 2}, {2, 3}, {3, 4}}, ""},                          // honest chain of votes
+		{[][2]uint64{{1, 2}, {0, 2}}, SlashDoubleAttestation},              // two targets in one epoch
+		{[][2]uint64{{2, 3}, {1, 4}}, SlashSurroundVoting},                 // surrounding a previous vote
+		{[][2]uint64{{1, 5}, {2, 3}}, SlashSurroundVoting},                 // surrounded by a previous vote
+		{[][2]uint64{{1, 2}, {3, 5}, {2, 4}}, ""},                          // overlapping but not surrounding
+		{[][2]uint64{{0, 1}, {1, 2}, {2, 6}, {3, 5}}, SlashSurroundVoting}, // surrounded after a gap
+	}
+	for i, tt := range tests {
+		var (
+			validator = common.Address{byte(i + 1)}
+			sd        = NewSlashingDetector(NewFaker())
+			atts      []*Attestation
+		)
+		for _, v := range tt.votes {
+			att := vote(validator, v[0], v[1])
+			atts = append(atts, att)
+			sd.CheckAttestation(att)
+		}
+		slashes := sd.GetPendingSlashes()
+		if tt.reason == "" {
+			if len(slashes) != 0 {
+				t.Errorf("test %d: unexpected slashing: %v", i, slashes[0].Reason)
+			}
+			continue
+		}
+		if len(slashes) != 1 {
+			t.Fatalf("test %d: slashing count mismatch: have %d, want 1", i, len(slashes))
+		}
+		if slashes[0].Validator != validator || slashes[0].Reason != tt.reason {
+			t.Errorf("test %d: slashing mismatch: have %x/%v, want %x/%v", i, slashes[0].Validator, slashes[0].Reason, validator, tt.reason)
+		}
+		var evidence []*Attestation
+		if err := rlp.DecodeBytes(slashes[0].Evidence, &evidence); err != nil {
+			t.Fatalf("test %d: failed to decode evidence: %v", i, err)
+		}
+		if len(evidence) != 2 || evidence[1].Data().Hash() != atts[len(atts)-1].Data().Hash() {
+			t.Errorf("test %d: evidence doesn't contain the offending vote", i)
+		}
+	}
+}
+
+// Tests that the FFG votes of validators are kept for the whole surround-vote
+// detection window, so surrounding votes are still slashable after a restart
+// long after the attestation window of the surrounded vote passed.
+func TestSurroundVoteHistoryAcrossRestart(t *testing.T) {
+	var (
+		validator = common.Address{0x01}
+		engine    = NewFaker()
+	)
+	vote := func(source, target uint64) *Attestation {
+		return NewAttestation(validator, common.Hash{byte(source), byte(target)}, target*EpochLength+source,
+			Checkpoint{Epoch: source, Hash: common.Hash{byte(source)}},
+			Checkpoint{Epoch: target, Hash: common.Hash{byte(target), byte(source)}})
+	}
+	surrounded := vote(2, 3)
+	if offense := engine.slashingDetector.CheckAttestation(surrounded); offense != nil {
+		t.Fatalf("honest vote reported as %v", offense.Reason)
+	}
+	restart := func(head uint64) *SlashingDetector {
+		engine.prune(head)
+
+		restarted := NewFaker()
+		restarted.db = engine.db
+		restarted.loadDatabase(head)
+		return restarted.slashingDetector
+	}
+	// Restart well past the attestation window but within the detection window
+	offense := restart(100 * EpochLength).CheckAttestation(vote(1, 4))
+	if offense == nil || offense.Reason != SlashSurroundVoting {
+		t.Fatalf("surrounding vote after restart not detected: have %v", offense)
+	}
+	var evidence []*Attestation
+	if err := rlp.DecodeBytes(offense.Evidence, &evidence); err != nil {
+		t.Fatalf("failed to decode evidence: %v", err)
+	}
+	if len(evidence) != 2 || evidence[0].Data().Hash() != surrounded.Data().Hash() {
+		t.Errorf("evidence doesn't contain the surrounded vote")
+	}
+	// Restart past the detection window, the vote must be forgotten
+	if offense := restart((maxSpanEpochs + 10) * EpochLength).CheckAttestation(vote(0, 5)); offense != nil {
+		t.Errorf("vote outside of the detection window still tracked: %v", offense.Reason)
+	}
+}
+
+// Tests that attestations, finality and slashing evidence survive an engine
+// restart on the same database.
+func TestPersistenceAcrossRestart(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		validator = crypto.PubkeyToAddress(key.PublicKey)
+		staking   = common.HexToAddress("0x139fa30605591055aceada5e841a2252d33b14c7")
+		config    = hybridTestConfig(0, staking)
+		db        = rawdb.NewMemoryDatabase()
+		engine    = New(NewConfig(config.Hybrid), db, ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+		stake     = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+	)
+	genesis := (&core.Genesis{
+		Config:  config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			staking: {
+				Balance: new(big.Int),
+				Code:    []byte{0x00},
+				Storage: stakingStorage([]common.Address{validator}, []*StakingValidator{{SelfStake: stake, TotalDelegated: new(big.Int), IsActive: true}}),
+			},
+		},
+	}).MustCommit(db)
+
+	blocks, _ := core.GenerateChain(config, genesis, engine, db, 2*EpochLength+2, nil)
+	forks, _ := core.GenerateChain(config, blocks[2*EpochLength-1], engine, db, 1, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{0x01})
+	})
+	chain, err := core.NewBlockChain(db, nil, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	if n, err := chain.InsertChain(forks); err != nil {
+		t.Fatalf("failed to insert fork block %d: %v", n, err)
+	}
+	engine.Start(chain)
+
+	attest := func(engine *Hybrid, block *types.Block) error {
+		source, target, err := engine.Checkpoints(block.Header())
+		if err != nil {
+			return err
+		}
+		att := NewAttestation(validator, block.Hash(), block.NumberU64(), source, target)
+		if err := att.Sign(key, params.TestChainConfig.ChainID); err != nil {
+			t.Fatalf("failed to sign attestation: %v", err)
+		}
+		return engine.AddAttestation(att)
+	}
+	// Finalize the checkpoint of epoch 1 and justify the one of epoch 2
+	for _, block := range []*types.Block{blocks[EpochLength], blocks[2*EpochLength]} {
+		if err := attest(engine, block); err != nil {
+			t.Fatalf("failed to add attestation: %v", err)
+		}
+	}
+	engine.Close()
+
+	// Restart the engine and ensure nothing was forgotten
+	engine = New(NewConfig(config.Hybrid), db, ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+	engine.Start(chain)
+
+	if atts := engine.GetAttestations(blocks[2*EpochLength].Hash()); atts == nil || !atts.HasAttested(validator) {
+		t.Errorf("attestation lost across restart")
+	}
+	if !engine.IsFinalized(EpochLength) || engine.IsFinalized(2*EpochLength) {
+		t.Errorf("finality lost across restart")
+	}
+	if have, want := engine.finalityTracker.Justified(), (Checkpoint{Epoch: 2, Hash: blocks[2*EpochLength-1].Hash()}); have != want {
+		t.Errorf("justified checkpoint mismatch: have %v, want %v", have, want)
+	}
+	if err := attest(engine, forks[0]); err != ErrSlashableAttestation {
+		t.Errorf("double vote after restart: have %v, want %v", err, ErrSlashableAttestation)
+	}
+	engine.Close()
+
+	engine = New(NewConfig(config.Hybrid), db, ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+	defer engine.Close()
+	engine.Start(chain)
+
+	slashes := engine.slashingDetector.GetPendingSlashes()
+	if len(slashes) != 1 || slashes[0].Validator != validator || slashes[0].Reason != SlashDoubleAttestation {
+		t.Errorf("slashing evidence lost across restart: have %v", slashes)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+
+package hybrid
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SignTxFn signs a transaction with a backing account.
+type SignTxFn func(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+
+// Authorize injects the account a miner signs the payload transactions of its
+// blocks with.
+func (h *Hybrid) Authorize(signer common.Address, signFn SignTxFn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.signer = signer
+	h.signFn = signFn
+}
+
+// payloadCall is a system transaction to embed in a block.
+type payloadCall struct {
+	to   common.Address
+	data []byte
+}
+
+// Payload returns the system transactions a miner embeds in a block before any
+// other transaction: the attestations collected for the parent block, which
+// earn the attesting validators their share of the block reward, followed by
+// slashing evidence for the pending offenses found by the slashing detector.
+// The transactions are signed by the authorized account with consecutive
+// nonces starting at its nonce in the given state. Nothing is returned before
+// the hybrid fork or if no account was authorized.
+func (h *Hybrid) Payload(chain consensus.ChainHeaderReader, header *types.Header, statedb *state.StateDB) types.Transactions {
+	h.mu.RLock()
+	signer, signFn := h.signer, h.signFn
+	var atts []*Attestation
+	if cached, ok := h.attestations.Get(header.ParentHash); ok {
+		for _, att := range cached.(*BlockAttestations).Attestations {
+			atts = append(atts, att)
+		}
+	}
+	h.mu.RUnlock()
+
+	config := chain.Config()
+	if signFn == nil || !config.IsHybrid(header.Number) {
+		return nil
+	}
+	var calls []payloadCall
+	if len(atts) > 0 {
+		sort.Slice(atts, func(i, j int) bool {
+			return bytes.Compare(atts[i].Validator[:], atts[j].Validator[:]) < 0
+		})
+		data, err := EncodeAttestations(atts)
+		if err != nil {
+			h.log.Warn("Failed to encode parent attestations", "err", err)
+		} else {
+			calls = append(calls, payloadCall{to: AttestationAddress, data: data})
+		}
+	}
+	for _, offense := range h.slashingDetector.GetPendingSlashes() {
+		// Evidence against validators slashed in the meantime is useless
+		if v := ReadStakingValidator(statedb, h.config.StakingContract, offense.Validator); !v.IsActive || v.IsSlashed {
+			h.slashingDetector.RemovePendingSlash(offense.Validator, offense.Reason)
+			continue
+		}
+		if _, _, err := VerifyEvidence(config.ChainID, offense.Evidence); err != nil {
+			h.log.Warn("Dropping invalid pending slashing evidence", "validator", offense.Validator, "err", err)
+			h.slashingDetector.RemovePendingSlash(offense.Validator, offense.Reason)
+			continue
+		}
+		calls = append(calls, payloadCall{to: EvidenceAddress, data: offense.Evidence})
+	}
+	var (
+		txs   types.Transactions
+		nonce = statedb.GetNonce(signer)
+	)
+	for _, call := range calls {
+		gas, err := core.IntrinsicGas(call.data, nil, nil, false, true, config.IsIstanbul(header.Number), config.IsShanghai(header.Number))
+		if err != nil {
+			h.log.Warn("Failed to compute payload transaction gas", "err", err)
+			return txs
+		}
+		var tx *types.Transaction
+		if header.BaseFee != nil {
+			tx = types.NewTx(&types.DynamicFeeTx{
+				ChainID:   config.ChainID,
+				Nonce:     nonce,
+				GasTipCap: new(big.Int),
+				GasFeeCap: new(big.Int).Set(header.BaseFee),
+				Gas:       gas,
+				To:        &call.to,
+				Data:      call.data,
+			})
+		} else {
+			tx = types.NewTransaction(nonce, call.to, new(big.Int), gas, new(big.Int), call.data)
+		}
+		signed, err := signFn(accounts.Account{Address: signer}, tx, config.ChainID)
+		if err != nil {
+			h.log.Warn("Failed to sign payload transaction", "err", err)
+			return txs
+		}
+		txs = append(txs, signed)
+		nonce++
+	}
+	return txs
+}
@@ -0,0 +1,274 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+//
+// The go-altcoinchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-altcoinchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-altcoinchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package hybrid
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testMinerBackend is the blockchain and transaction pool a test miner builds
+// blocks from.
+type testMinerBackend struct {
+	chain  *core.BlockChain
+	txPool *core.TxPool
+}
+
+func (b *testMinerBackend) BlockChain() *core.BlockChain { return b.chain }
+func (b *testMinerBackend) TxPool() *core.TxPool         { return b.txPool }
+
+// newTestMiner creates a miner on top of the given chain, signing the payload
+// transactions of the engine with key, along with the pool it mines from.
+func newTestMiner(t *testing.T, chain *core.BlockChain, engine *Hybrid, key *ecdsa.PrivateKey) (*miner.Miner, *core.TxPool) {
+	poolConfig := core.DefaultTxPoolConfig
+	poolConfig.Journal = ""
+
+	engine.Authorize(crypto.PubkeyToAddress(key.PublicKey), func(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+		return types.SignTx(tx, types.LatestSignerForChainID(chainID), key)
+	})
+	backend := &testMinerBackend{chain: chain, txPool: core.NewTxPool(poolConfig, chain.Config(), chain)}
+	t.Cleanup(backend.txPool.Stop)
+
+	config := &miner.Config{Recommit: time.Second, GasCeil: params.GenesisGasLimit}
+	return miner.New(backend, config, chain.Config(), new(event.TypeMux), engine, nil), backend.txPool
+}
+
+// mineBlock builds a block on top of the current head of the chain with the
+// test miner and imports it.
+func mineBlock(t *testing.T, chain *core.BlockChain, m *miner.Miner, coinbase common.Address) *types.Block {
+	head := chain.CurrentBlock()
+	block, err := m.GetSealingBlockSync(head.Hash(), head.Time()+1, coinbase, common.Hash{}, false)
+	if err != nil {
+		t.Fatalf("failed to mine block: %v", err)
+	}
+	if n, err := chain.InsertChain(types.Blocks{block}); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	return block
+}
+
+// Tests that a double vote detected by the engine is embedded as slashing
+// evidence into the next mined block, and that importing the block slashes
+// the stake of the offending validator.
+func TestMinedSlashingEvidence(t *testing.T) {
+	var (
+		key, _      = crypto.GenerateKey()
+		minerKey, _ = crypto.GenerateKey()
+		validator   = crypto.PubkeyToAddress(key.PublicKey)
+		coinbase    = crypto.PubkeyToAddress(minerKey.PublicKey)
+		staking     = common.HexToAddress("0x139fa30605591055aceada5e841a2252d33b14c7")
+		config      = hybridTestConfig(0, staking)
+		db          = rawdb.NewMemoryDatabase()
+		engine      = New(NewConfig(config.Hybrid), db, ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+		ether       = big.NewInt(1e18)
+	)
+	defer engine.Close()
+
+	genesis := (&core.Genesis{
+		Config:  config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			coinbase: {Balance: ether},
+			staking: {
+				Balance: new(big.Int),
+				Code:    []byte{0x00},
+				Storage: stakingStorage([]common.Address{validator}, []*StakingValidator{
+					{SelfStake: new(big.Int).Mul(big.NewInt(1000), ether), TotalDelegated: new(big.Int), IsActive: true},
+				}),
+			},
+		},
+	}).MustCommit(db)
+
+	canonical, _ := core.GenerateChain(config, genesis, engine, db, 2, nil)
+	fork, _ := core.GenerateChain(config, genesis, engine, db, 1, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{0x01})
+	})
+	chain, err := core.NewBlockChain(db, nil, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	engine.Start(chain)
+
+	for _, blocks := range []types.Blocks{canonical, fork} {
+		if n, err := chain.InsertChain(blocks); err != nil {
+			t.Fatalf("failed to insert block %d: %v", n, err)
+		}
+	}
+	// Attest two different blocks at height 1, the second vote is slashable
+	for i, block := range []*types.Block{canonical[0], fork[0]} {
+		source, target, err := engine.Checkpoints(block.Header())
+		if err != nil {
+			t.Fatalf("failed to derive checkpoints of block %x: %v", block.Hash(), err)
+		}
+		att := NewAttestation(validator, block.Hash(), block.NumberU64(), source, target)
+		if err := att.Sign(key, config.ChainID); err != nil {
+			t.Fatalf("failed to sign attestation: %v", err)
+		}
+		if err := engine.AddAttestation(att); (i == 0) != (err == nil) {
+			t.Fatalf("vote %d: unexpected result: %v", i, err)
+		}
+	}
+	if pending := engine.slashingDetector.GetPendingSlashes(); len(pending) != 1 {
+		t.Fatalf("pending offenses mismatch: have %d, want 1", len(pending))
+	}
+	m, pool := newTestMiner(t, chain, engine, minerKey)
+	defer m.Close()
+
+	block := mineBlock(t, chain, m, coinbase)
+	if txs := block.Transactions(); len(txs) != 1 || *txs[0].To() != EvidenceAddress {
+		t.Fatalf("mined block lacks the slashing evidence: %v", txs)
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatalf("failed to retrieve head state: %v", err)
+	}
+	v := ReadStakingValidator(statedb, staking, validator)
+	if !v.IsSlashed || v.IsActive {
+		t.Errorf("validator not slashed: slashed %v, active %v", v.IsSlashed, v.IsActive)
+	}
+	if want := new(big.Int).Mul(big.NewInt(900), ether); v.SelfStake.Cmp(want) != 0 {
+		t.Errorf("self stake mismatch: have %v, want %v", v.SelfStake, want)
+	}
+	// Bogus evidence submitted to the pool would invalidate the block, skip it
+	bogus, err := types.SignTx(types.NewTransaction(1, EvidenceAddress, new(big.Int), 100000, big.NewInt(2*params.InitialBaseFee), []byte{0x01}), types.LatestSigner(config), minerKey)
+	if err != nil {
+		t.Fatalf("failed to sign bogus evidence: %v", err)
+	}
+	if err := pool.AddLocal(bogus); err != nil {
+		t.Fatalf("failed to add bogus evidence to the pool: %v", err)
+	}
+	// The evidence is not embedded again once the validator is slashed
+	if block := mineBlock(t, chain, m, coinbase); len(block.Transactions()) != 0 {
+		t.Errorf("evidence embedded again: %v", block.Transactions())
+	}
+	if pending := engine.slashingDetector.GetPendingSlashes(); len(pending) != 0 {
+		t.Errorf("pending offenses left: %d", len(pending))
+	}
+}
+
+// Tests that the attestations the engine collected for a block are embedded
+// into the mined child block, and that importing it pays the attesters their
+// share of the block reward.
+func TestMinedAttestationRewards(t *testing.T) {
+	var (
+		largeKey, _ = crypto.GenerateKey()
+		smallKey, _ = crypto.GenerateKey()
+		minerKey, _ = crypto.GenerateKey()
+		large       = crypto.PubkeyToAddress(largeKey.PublicKey)
+		small       = crypto.PubkeyToAddress(smallKey.PublicKey)
+		coinbase    = crypto.PubkeyToAddress(minerKey.PublicKey)
+		staking     = common.HexToAddress("0x139fa30605591055aceada5e841a2252d33b14c7")
+		config      = hybridTestConfig(0, staking)
+		db          = rawdb.NewMemoryDatabase()
+		engine      = New(NewConfig(config.Hybrid), db, ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+		ether       = big.NewInt(1e18)
+	)
+	defer engine.Close()
+
+	(&core.Genesis{
+		Config:  config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			coinbase: {Balance: ether},
+			staking: {
+				Balance: new(big.Int),
+				Code:    []byte{0x00},
+				Storage: stakingStorage([]common.Address{large, small}, []*StakingValidator{
+					{SelfStake: new(big.Int).Mul(big.NewInt(300), ether), TotalDelegated: new(big.Int), IsActive: true},
+					{SelfStake: new(big.Int).Mul(big.NewInt(100), ether), TotalDelegated: new(big.Int), IsActive: true},
+				}),
+			},
+		},
+	}).MustCommit(db)
+
+	chain, err := core.NewBlockChain(db, nil, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	engine.Start(chain)
+
+	m, _ := newTestMiner(t, chain, engine, minerKey)
+	defer m.Close()
+
+	// Nobody attested the genesis block, the first block carries no payload
+	parent := mineBlock(t, chain, m, coinbase)
+	if txs := parent.Transactions(); len(txs) != 0 {
+		t.Fatalf("unexpected payload in first block: %v", txs)
+	}
+	for _, key := range []*ecdsa.PrivateKey{largeKey, smallKey} {
+		source, target, err := engine.Checkpoints(parent.Header())
+		if err != nil {
+			t.Fatalf("failed to derive checkpoints: %v", err)
+		}
+		att := NewAttestation(crypto.PubkeyToAddress(key.PublicKey), parent.Hash(), parent.NumberU64(), source, target)
+		if err := att.Sign(key, config.ChainID); err != nil {
+			t.Fatalf("failed to sign attestation: %v", err)
+		}
+		if err := engine.AddAttestation(att); err != nil {
+			t.Fatalf("failed to add attestation: %v", err)
+		}
+	}
+	block := mineBlock(t, chain, m, coinbase)
+	if txs := block.Transactions(); len(txs) != 1 || *txs[0].To() != AttestationAddress {
+		t.Fatalf("mined block lacks the attestations: %v", txs)
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatalf("failed to retrieve head state: %v", err)
+	}
+	// The validator reward is split 300:100 between the attesters
+	validators := new(big.Int).Mul(HybridBlockReward, big.NewInt(int64(config.Hybrid.ValidatorRewardPercent)))
+	validators.Div(validators, big.NewInt(100))
+
+	if have, want := statedb.GetBalance(large), new(big.Int).Div(new(big.Int).Mul(validators, big.NewInt(3)), big.NewInt(4)); have.Cmp(want) != 0 {
+		t.Errorf("large validator balance mismatch: have %v, want %v", have, want)
+	}
+	if have, want := statedb.GetBalance(small), new(big.Int).Div(validators, big.NewInt(4)); have.Cmp(want) != 0 {
+		t.Errorf("small validator balance mismatch: have %v, want %v", have, want)
+	}
+	for _, addr := range []common.Address{large, small} {
+		if have := ReadStakingValidator(statedb, staking, addr).LastActiveBlock; have != block.NumberU64() {
+			t.Errorf("validator %x last active block mismatch: have %d, want %d", addr, have, block.NumberU64())
+		}
+	}
+	// The miner pays the base fee of the payload out of its own reward
+	miners := new(big.Int).Mul(HybridBlockReward, big.NewInt(2*int64(config.Hybrid.MinerRewardPercent)))
+	miners.Div(miners, big.NewInt(100))
+	miners.Add(miners, ether)
+	miners.Sub(miners, new(big.Int).Mul(block.BaseFee(), new(big.Int).SetUint64(block.GasUsed())))
+	if have := statedb.GetBalance(coinbase); have.Cmp(miners) != 0 {
+		t.Errorf("miner balance mismatch: have %v, want %v", have, miners)
+	}
+}
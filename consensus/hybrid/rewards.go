@@ -0,0 +1,146 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+
+package hybrid
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// AttestationAddress is the system address attestations are included in blocks
+// through. A transaction sent to it carries an RLP list of signed attestations
+// of the parent block as its data. The validators attesting the parent block
+// this way share the validator part of the block reward.
+var AttestationAddress = common.HexToAddress("0x0000000000000000000000000000000000001002")
+
+// rewardShareScale is the precision of the accumulated reward per delegated
+// share of the staking contract.
+var rewardShareScale = big.NewInt(1e18)
+
+// EncodeAttestations encodes attestations as the data of a transaction to
+// AttestationAddress.
+func EncodeAttestations(attestations []*Attestation) ([]byte, error) {
+	return rlp.EncodeToBytes(attestations)
+}
+
+// includedAttesters returns the active validators with a valid attestation of
+// the parent block included in the given transactions, in order of inclusion,
+// along with their total stake. Only the state of the block is consulted, so
+// all nodes agree on the result.
+func (h *Hybrid) includedAttesters(chainID *big.Int, header *types.Header, statedb *state.StateDB, txs []*types.Transaction) ([]common.Address, map[common.Address]*StakingValidator) {
+	var (
+		attesters []common.Address
+		stakes    = make(map[common.Address]*StakingValidator)
+	)
+	for _, tx := range txs {
+		if to := tx.To(); to == nil || *to != AttestationAddress {
+			continue
+		}
+		var attestations []*Attestation
+		if err := rlp.DecodeBytes(tx.Data(), &attestations); err != nil {
+			h.log.Debug("Ignoring malformed attestations", "block", header.Number, "tx", tx.Hash(), "err", err)
+			continue
+		}
+		for _, att := range attestations {
+			if att.BlockHash != header.ParentHash || att.BlockNumber+1 != header.Number.Uint64() {
+				continue
+			}
+			if _, ok := stakes[att.Validator]; ok || !att.VerifySignature(chainID) {
+				continue
+			}
+			v := ReadStakingValidator(statedb, h.config.StakingContract, att.Validator)
+			if !v.IsActive || v.IsSlashed || v.TotalStake().Cmp(h.config.MinStake) < 0 {
+				continue
+			}
+			attesters = append(attesters, att.Validator)
+			stakes[att.Validator] = v
+		}
+	}
+	return attesters, stakes
+}
+
+// accumulateRewards credits the block reward, split between the miner and the
+// validators according to the configured percentages. The validator part is
+// shared by the validators that attested the parent block, in proportion to
+// their stake, or added to the reward pool of the staking contract if nobody
+// did. It returns the rewards of the miner and the validators.
+func (h *Hybrid) accumulateRewards(chainID *big.Int, header *types.Header, statedb *state.StateDB, txs []*types.Transaction, uncles []*types.Header) (*big.Int, *big.Int) {
+	var (
+		baseReward      = new(big.Int).Div(new(big.Int).Mul(HybridBlockReward, new(big.Int).SetUint64(h.config.MinerRewardPercent)), big.NewInt(100))
+		minerReward     = new(big.Int).Set(baseReward)
+		validatorReward = new(big.Int).Div(new(big.Int).Mul(HybridBlockReward, new(big.Int).SetUint64(h.config.ValidatorRewardPercent)), big.NewInt(100))
+	)
+	// Uncle creators get a reduced miner reward, the miner a bonus per uncle
+	r := new(big.Int)
+	for _, uncle := range uncles {
+		r.Add(uncle.Number, big.NewInt(8))
+		r.Sub(r, header.Number)
+		r.Mul(r, baseReward)
+		r.Div(r, big.NewInt(8))
+		statedb.AddBalance(uncle.Coinbase, r)
+
+		r.Div(baseReward, big.NewInt(32))
+		minerReward.Add(minerReward, r)
+	}
+	statedb.AddBalance(header.Coinbase, minerReward)
+
+	contract := h.config.StakingContract
+	attesters, stakes := h.includedAttesters(chainID, header, statedb, txs)
+	if len(attesters) == 0 {
+		// No online validators, add to the reward pool for later
+		pool := statedb.GetState(contract, common.BigToHash(big.NewInt(rewardPoolSlot))).Big()
+		statedb.SetState(contract, common.BigToHash(big.NewInt(rewardPoolSlot)), common.BigToHash(pool.Add(pool, validatorReward)))
+		statedb.AddBalance(contract, validatorReward)
+		return minerReward, validatorReward
+	}
+	attestingStake := new(big.Int)
+	for _, addr := range attesters {
+		attestingStake.Add(attestingStake, stakes[addr].TotalStake())
+	}
+	paid := new(big.Int)
+	for _, addr := range attesters {
+		reward := new(big.Int).Mul(validatorReward, stakes[addr].TotalStake())
+		reward.Div(reward, attestingStake)
+
+		creditStakingValidator(statedb, contract, addr, stakes[addr], reward)
+		recordStakingAttestation(statedb, contract, addr, header.Number)
+		paid.Add(paid, reward)
+	}
+	return minerReward, paid
+}
+
+// creditStakingValidator pays a validator reward the way the staking contract
+// distributes rewards: the validator receives its commission directly and the
+// rest accrues to its delegators, kept by the contract until claimed. Without
+// delegators the validator receives the whole reward.
+func creditStakingValidator(statedb *state.StateDB, contract common.Address, addr common.Address, v *StakingValidator, reward *big.Int) {
+	if reward.Sign() == 0 {
+		return
+	}
+	if v.TotalDelegated.Sign() == 0 {
+		statedb.AddBalance(addr, reward)
+		return
+	}
+	commission := new(big.Int).Mul(reward, new(big.Int).SetUint64(v.Commission))
+	commission.Div(commission, big.NewInt(100))
+	delegatorReward := new(big.Int).Sub(reward, commission)
+
+	slot := offsetSlot(mappingSlot(addr, validatorsSlot), accRewardPerShareOffset)
+	perShare := new(big.Int).Mul(delegatorReward, rewardShareScale)
+	perShare.Div(perShare, v.TotalDelegated)
+	statedb.SetState(contract, slot, common.BigToHash(perShare.Add(perShare, statedb.GetState(contract, slot).Big())))
+
+	statedb.AddBalance(addr, commission)
+	statedb.AddBalance(contract, delegatorReward)
+}
+
+// recordStakingAttestation updates the last active block of a validator in the
+// staking contract, mirroring recordAttestation.
+func recordStakingAttestation(db StorageWriter, contract common.Address, addr common.Address, number *big.Int) {
+	db.SetState(contract, offsetSlot(mappingSlot(addr, validatorsSlot), lastActiveBlockOffset), common.BigToHash(number))
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+//
+// The go-altcoinchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-altcoinchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-altcoinchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package hybrid
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the validator part of the block reward is shared by the validators
+// whose attestation of the parent block was included, in proportion to their
+// stake, and that the miner receives the configured percentage.
+func TestRewardsToAttesters(t *testing.T) {
+	var (
+		soloKey, _     = crypto.GenerateKey()
+		delegateKey, _ = crypto.GenerateKey()
+		absentKey, _   = crypto.GenerateKey()
+		senderKey, _   = crypto.GenerateKey()
+		solo           = crypto.PubkeyToAddress(soloKey.PublicKey)
+		delegate       = crypto.PubkeyToAddress(delegateKey.PublicKey)
+		absent         = crypto.PubkeyToAddress(absentKey.PublicKey)
+		sender         = crypto.PubkeyToAddress(senderKey.PublicKey)
+		miner          = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		staking        = common.HexToAddress("0x139fa30605591055aceada5e841a2252d33b14c7")
+		config         = hybridTestConfig(0, staking)
+		db             = rawdb.NewMemoryDatabase()
+		engine         = New(NewConfig(config.Hybrid), db, ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+		ether          = big.NewInt(1e18)
+		signer         = types.LatestSigner(config)
+	)
+	defer engine.Close()
+
+	genesis := (&core.Genesis{
+		Config:  config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			sender: {Balance: new(big.Int).Mul(big.NewInt(100), ether)},
+			staking: {
+				Balance: new(big.Int),
+				Code:    []byte{0x00},
+				Storage: stakingStorage([]common.Address{solo, delegate, absent}, []*StakingValidator{
+					{SelfStake: new(big.Int).Mul(big.NewInt(300), ether), TotalDelegated: new(big.Int), IsActive: true},
+					{SelfStake: new(big.Int).Mul(big.NewInt(100), ether), TotalDelegated: new(big.Int).Mul(big.NewInt(100), ether), Commission: 10, IsActive: true},
+					{SelfStake: new(big.Int).Mul(big.NewInt(500), ether), TotalDelegated: new(big.Int), IsActive: true},
+				}),
+			},
+		},
+	}).MustCommit(db)
+
+	attest := func(key *ecdsa.PrivateKey, block *types.Block) *Attestation {
+		att := NewAttestation(crypto.PubkeyToAddress(key.PublicKey), block.Hash(), block.NumberU64(), Checkpoint{}, Checkpoint{})
+		if err := att.Sign(key, params.TestChainConfig.ChainID); err != nil {
+			t.Fatalf("failed to sign attestation: %v", err)
+		}
+		return att
+	}
+	blocks, _ := core.GenerateChain(config, genesis, engine, db, 2, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(miner)
+		if i == 0 {
+			return
+		}
+		parent := b.PrevBlock(i - 1)
+
+		forged := attest(soloKey, parent)
+		forged.Validator = absent
+
+		data, err := EncodeAttestations([]*Attestation{
+			attest(soloKey, parent),
+			attest(delegateKey, parent),
+			attest(soloKey, parent),    // duplicate, counted once
+			attest(absentKey, genesis), // attesting another block
+			forged,                     // not signed by the validator
+		})
+		if err != nil {
+			t.Fatalf("failed to encode attestations: %v", err)
+		}
+		tx, err := types.SignTx(types.NewTransaction(0, AttestationAddress, new(big.Int), 200000, big.NewInt(2*params.InitialBaseFee), data), signer, senderKey)
+		if err != nil {
+			t.Fatalf("failed to sign transaction: %v", err)
+		}
+		b.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(db, nil, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatalf("failed to retrieve head state: %v", err)
+	}
+	// Block 1 has no attesters, its validator reward goes to the reward pool.
+	// Block 2 splits it 300:200 between the two attesting validators.
+	var (
+		milli      = big.NewInt(1e15)
+		validators = new(big.Int).Div(new(big.Int).Mul(HybridBlockReward, big.NewInt(int64(config.Hybrid.ValidatorRewardPercent))), big.NewInt(100))
+	)
+	tip := new(big.Int).Sub(big.NewInt(2*params.InitialBaseFee), blocks[1].BaseFee())
+	wantMiner := new(big.Int).Div(new(big.Int).Mul(HybridBlockReward, big.NewInt(2*int64(config.Hybrid.MinerRewardPercent))), big.NewInt(100))
+	wantMiner.Add(wantMiner, tip.Mul(tip, new(big.Int).SetUint64(blocks[1].GasUsed())))
+	if have := statedb.GetBalance(miner); have.Cmp(wantMiner) != 0 {
+		t.Errorf("miner balance mismatch: have %v, want %v", have, wantMiner)
+	}
+	if have, want := statedb.GetBalance(solo), new(big.Int).Mul(big.NewInt(600), milli); have.Cmp(want) != 0 {
+		t.Errorf("solo validator balance mismatch: have %v, want %v", have, want)
+	}
+	// The delegated validator keeps its 10% commission, the rest accrues to delegators
+	if have, want := statedb.GetBalance(delegate), new(big.Int).Mul(big.NewInt(40), milli); have.Cmp(want) != 0 {
+		t.Errorf("delegated validator balance mismatch: have %v, want %v", have, want)
+	}
+	perShare := statedb.GetState(staking, offsetSlot(mappingSlot(delegate, validatorsSlot), accRewardPerShareOffset)).Big()
+	if want := new(big.Int).Mul(big.NewInt(36), big.NewInt(1e14)); perShare.Cmp(want) != 0 {
+		t.Errorf("reward per share mismatch: have %v, want %v", perShare, want)
+	}
+	if have, want := statedb.GetBalance(staking), new(big.Int).Add(validators, new(big.Int).Mul(big.NewInt(360), milli)); have.Cmp(want) != 0 {
+		t.Errorf("staking contract balance mismatch: have %v, want %v", have, want)
+	}
+	if have := statedb.GetBalance(absent); have.Sign() != 0 {
+		t.Errorf("absent validator rewarded: %v", have)
+	}
+	// Attesters are marked active in the staking contract
+	if have := ReadStakingValidator(statedb, staking, solo).LastActiveBlock; have != 2 {
+		t.Errorf("last active block mismatch: have %d, want 2", have)
+	}
+	if have := ReadStakingValidator(statedb, staking, absent).LastActiveBlock; have != 0 {
+		t.Errorf("absent last active block mismatch: have %d, want 0", have)
+	}
+}
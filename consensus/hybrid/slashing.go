@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	lru "github.com/hashicorp/golang-lru"
 )
@@ -27,18 +28,32 @@ const (
 type SlashableOffense struct {
 	Validator     common.Address
 	Reason        SlashingReason
-	Evidence      []byte // Encoded evidence (e.g., two conflicting attestations)
+	Evidence      hexutil.Bytes // Encoded evidence (e.g., two conflicting attestations)
 	BlockNumber   uint64
 	DetectedBlock uint64
 }
 
+// maxSpanEpochs is the number of epochs around a vote for which surround
+// detection spans are maintained.
+const maxSpanEpochs = 4096
+
+// voteHistory is the FFG voting history of a single validator.
+type voteHistory struct {
+	votes   map[uint64]*Attestation // target epoch -> vote
+	minSpan map[uint64]uint64       // epoch -> min distance to the target of a vote with a later source
+	maxSpan map[uint64]uint64       // epoch -> max distance to the target of a vote with an earlier source
+}
+
 // SlashingDetector detects slashable offenses by validators.
 type SlashingDetector struct {
 	hybrid *Hybrid
 
-	// Track attestations by validator: validator -> blockNumber -> blockHash
+	// Track attestations by validator and block number
 	validatorAttestations *lru.Cache
 
+	// FFG vote history by validator
+	histories map[common.Address]*voteHistory
+
 	// Track detected offenses
 	pendingSlashes []SlashableOffense
 
@@ -58,6 +73,7 @@ func NewSlashingDetector(hybrid *Hybrid) *SlashingDetector {
 	return &SlashingDetector{
 		hybrid:                hybrid,
 		validatorAttestations: attestations,
+		histories:             make(map[common.Address]*voteHistory),
 		pendingSlashes:        make([]SlashableOffense, 0),
 		lastSeen:              make(map[common.Address]uint64),
 		offlineThreshold:      1000, // ~4 hours at 15s blocks
@@ -66,7 +82,8 @@ func NewSlashingDetector(hybrid *Hybrid) *SlashingDetector {
 }
 
 // CheckAttestation checks if an attestation is slashable.
-// Returns a SlashableOffense if slashable, nil otherwise.
+// Returns a SlashableOffense if slashable, nil otherwise. Attestations that
+// are not slashable are recorded in the validator's history.
 func (sd *SlashingDetector) CheckAttestation(attestation *Attestation) *SlashableOffense {
 	sd.mu.Lock()
 	defer sd.mu.Unlock()
@@ -78,61 +95,140 @@ func (sd *SlashingDetector) CheckAttestation(attestation *Attestation) *Slashabl
 	// Update last seen
 	sd.lastSeen[validator] = blockNumber
 
-	// Get validator's attestation history
-	key := attestationKey(validator, blockNumber)
-
+	// Double attestation check: same block number, different hash
+	key := attestationKey{validator, blockNumber}
 	if existing, ok := sd.validatorAttestations.Get(key); ok {
-		existingHash := existing.(common.Hash)
-		// Double attestation check: same block number, different hash
-		if existingHash != blockHash {
-			offense := &SlashableOffense{
-				Validator:     validator,
-				Reason:        SlashDoubleAttestation,
-				BlockNumber:   blockNumber,
-				DetectedBlock: blockNumber,
-			}
-
+		if prev := existing.(*Attestation); prev.BlockHash != blockHash {
 			sd.log.Warn("Double attestation detected",
 				"validator", validator.Hex(),
 				"blockNumber", blockNumber,
-				"hash1", existingHash.Hex(),
+				"hash1", prev.BlockHash.Hex(),
 				"hash2", blockHash.Hex(),
 			)
-
-			sd.pendingSlashes = append(sd.pendingSlashes, *offense)
-			return offense
+			return sd.report(SlashDoubleAttestation, prev, attestation)
 		}
 	}
-
-	// Store this attestation
-	sd.validatorAttestations.Add(key, blockHash)
-
-	// Check for surround voting (simplified check)
+	// Double and surround vote checks on the source/target checkpoints
 	if offense := sd.checkSurroundVoting(attestation); offense != nil {
-		sd.pendingSlashes = append(sd.pendingSlashes, *offense)
 		return offense
 	}
+	sd.validatorAttestations.Add(key, attestation)
+	if sd.recordVote(attestation) {
+		sd.hybrid.writeVote(attestation)
+	}
 
 	return nil
 }
 
-// checkSurroundVoting checks for surround voting offense.
-// Surround voting occurs when an attestation's source/target range
-// surrounds or is surrounded by another attestation from the same validator.
-func (sd *SlashingDetector) checkSurroundVoting(attestation *Attestation) *SlashableOffense {
-	// Simplified implementation: In a full implementation, we'd track
-	// source and target epochs for each attestation and check for
-	// surround voting conditions.
-	//
-	// For now, we just do basic double-voting detection.
-	// Full surround voting detection would require:
-	// 1. Track source epoch for each attestation
-	// 2. Check if new attestation surrounds any previous one
-	// 3. Check if new attestation is surrounded by any previous one
+// report records a slashable offense proven by two conflicting attestations.
+// The lock must be held.
+func (sd *SlashingDetector) report(reason SlashingReason, prev, attestation *Attestation) *SlashableOffense {
+	evidence, err := EncodeEvidence(prev, attestation)
+	if err != nil {
+		sd.log.Error("Failed to encode slashing evidence", "err", err)
+	}
+	offense := &SlashableOffense{
+		Validator:     attestation.Validator,
+		Reason:        reason,
+		Evidence:      evidence,
+		BlockNumber:   attestation.BlockNumber,
+		DetectedBlock: attestation.BlockNumber,
+	}
+	sd.pendingSlashes = append(sd.pendingSlashes, *offense)
+	sd.hybrid.writeOffense(offense)
+	return offense
+}
 
+// checkSurroundVoting checks for double and surround voting offenses on the
+// FFG part of an attestation. A validator may not cast two different votes for
+// the same target epoch, nor a vote whose source/target range surrounds or is
+// surrounded by one of its previous votes. Surrounds are detected with min/max
+// spans: minSpan[e] is the smallest distance from epoch e to the target of a
+// vote with a source after e, maxSpan[e] is the largest distance from epoch e
+// to the target of a vote with a source before e. The lock must be held.
+func (sd *SlashingDetector) checkSurroundVoting(attestation *Attestation) *SlashableOffense {
+	source, target := attestation.Source.Epoch, attestation.Target.Epoch
+	if source >= target {
+		return nil // Head vote only, no FFG link
+	}
+	history := sd.histories[attestation.Validator]
+	if history == nil {
+		return nil
+	}
+	if prev, ok := history.votes[target]; ok {
+		if prev.Source == attestation.Source && prev.Target == attestation.Target {
+			return nil
+		}
+		sd.log.Warn("Double vote detected", "validator", attestation.Validator.Hex(), "target", target,
+			"hash1", prev.Target.Hash.Hex(), "hash2", attestation.Target.Hash.Hex())
+		return sd.report(SlashDoubleAttestation, prev, attestation)
+	}
+	if span, ok := history.minSpan[source]; ok && source+span < target {
+		// The new vote surrounds a previous one
+		for _, prev := range history.votes {
+			if prev.Source.Epoch > source && prev.Target.Epoch < target {
+				sd.log.Warn("Surrounding vote detected", "validator", attestation.Validator.Hex(),
+					"source", source, "target", target, "surrounded", prev.Source.Epoch, "target", prev.Target.Epoch)
+				return sd.report(SlashSurroundVoting, prev, attestation)
+			}
+		}
+	}
+	if span, ok := history.maxSpan[source]; ok && source+span > target {
+		// The new vote is surrounded by a previous one
+		for _, prev := range history.votes {
+			if prev.Source.Epoch < source && prev.Target.Epoch > target {
+				sd.log.Warn("Surrounded vote detected", "validator", attestation.Validator.Hex(),
+					"source", source, "target", target, "surrounding", prev.Source.Epoch, "target", prev.Target.Epoch)
+				return sd.report(SlashSurroundVoting, prev, attestation)
+			}
+		}
+	}
 	return nil
 }
 
+// recordVote adds the FFG vote of an attestation to the validator's history,
+// updating the min and max spans, and reports whether the vote is new. Spans
+// are only maintained for the last maxSpanEpochs epochs around the vote. The
+// lock must be held.
+func (sd *SlashingDetector) recordVote(attestation *Attestation) bool {
+	source, target := attestation.Source.Epoch, attestation.Target.Epoch
+	if source >= target {
+		return false
+	}
+	history := sd.histories[attestation.Validator]
+	if history == nil {
+		history = &voteHistory{
+			votes:   make(map[uint64]*Attestation),
+			minSpan: make(map[uint64]uint64),
+			maxSpan: make(map[uint64]uint64),
+		}
+		sd.histories[attestation.Validator] = history
+	}
+	if _, ok := history.votes[target]; ok {
+		return false
+	}
+	history.votes[target] = attestation
+
+	// Epochs before the source: the vote may be surrounded by later ones. If
+	// an epoch already has a smaller span, so do all epochs before it.
+	for i := uint64(1); i <= maxSpanEpochs && i <= source; i++ {
+		epoch := source - i
+		if span, ok := history.minSpan[epoch]; ok && span <= target-epoch {
+			break
+		}
+		history.minSpan[epoch] = target - epoch
+	}
+	// Epochs between source and target: the vote may surround later ones. If
+	// an epoch already has a larger span, so do all epochs after it.
+	for epoch := source + 1; epoch < target && epoch-source <= maxSpanEpochs; epoch++ {
+		if span, ok := history.maxSpan[epoch]; ok && span >= target-epoch {
+			break
+		}
+		history.maxSpan[epoch] = target - epoch
+	}
+	return true
+}
+
 // CheckOfflineValidators checks for validators that have been offline too long.
 func (sd *SlashingDetector) CheckOfflineValidators(currentBlock uint64) []SlashableOffense {
 	sd.mu.Lock()
@@ -227,9 +323,45 @@ func (sd *SlashingDetector) SetOfflineThreshold(blocks uint64) {
 	sd.offlineThreshold = blocks
 }
 
-// attestationKey creates a unique key for a validator's attestation at a block.
-func attestationKey(validator common.Address, blockNumber uint64) string {
-	return validator.Hex() + "-" + string(rune(blockNumber))
+// restoreAttestations records previously accepted attestations loaded from
+// the database in the double-vote history.
+func (sd *SlashingDetector) restoreAttestations(attestations []*Attestation) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	for _, att := range attestations {
+		sd.validatorAttestations.Add(attestationKey{att.Validator, att.BlockNumber}, att)
+		sd.recordVote(att)
+		if att.BlockNumber > sd.lastSeen[att.Validator] {
+			sd.lastSeen[att.Validator] = att.BlockNumber
+		}
+	}
+}
+
+// restoreVotes records previously cast FFG votes loaded from the database in
+// the surround-vote history.
+func (sd *SlashingDetector) restoreVotes(votes []*Attestation) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	for _, vote := range votes {
+		sd.recordVote(vote)
+	}
+}
+
+// restoreOffenses re-queues previously detected offenses loaded from the
+// database.
+func (sd *SlashingDetector) restoreOffenses(offenses []SlashableOffense) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	sd.pendingSlashes = append(sd.pendingSlashes, offenses...)
+}
+
+// attestationKey identifies a validator's attestation at a block height.
+type attestationKey struct {
+	validator   common.Address
+	blockNumber uint64
 }
 
 // PruneOldData removes old attestation data to save memory.
@@ -245,3 +377,35 @@ func (sd *SlashingDetector) PruneOldData(beforeBlock uint64) {
 		}
 	}
 }
+
+// PruneHistory drops the FFG vote history of epochs more than maxSpanEpochs
+// before the given epoch.
+func (sd *SlashingDetector) PruneHistory(epoch uint64) {
+	if epoch <= maxSpanEpochs {
+		return
+	}
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	limit := epoch - maxSpanEpochs
+	for addr, history := range sd.histories {
+		for target := range history.votes {
+			if target < limit {
+				delete(history.votes, target)
+			}
+		}
+		for e := range history.minSpan {
+			if e < limit {
+				delete(history.minSpan, e)
+			}
+		}
+		for e := range history.maxSpan {
+			if e < limit {
+				delete(history.maxSpan, e)
+			}
+		}
+		if len(history.votes) == 0 {
+			delete(sd.histories, addr)
+		}
+	}
+}
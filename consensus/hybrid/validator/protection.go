@@ -0,0 +1,140 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+
+package validator
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	// ErrDoubleVote is returned if signing an attestation would conflict with
+	// one already signed by the same validator at the same height or for the
+	// same target epoch.
+	ErrDoubleVote = errors.New("conflicting attestation already signed")
+
+	// ErrSurroundVote is returned if signing an attestation could surround or be
+	// surrounded by a checkpoint vote already signed by the same validator.
+	ErrSurroundVote = errors.New("attestation would surround a signed vote")
+
+	// errAlreadySigned is returned if the exact attestation was signed before.
+	errAlreadySigned = errors.New("attestation already signed")
+)
+
+var (
+	// signedAttestationPrefix + validator + number (uint64 big endian) -> block hash
+	signedAttestationPrefix = []byte("sp-att-")
+
+	// signedVotePrefix + validator -> RLP([source, target]) of the latest checkpoint vote
+	signedVotePrefix = []byte("sp-vote-")
+)
+
+// checkpointVote is the source and target of a signed checkpoint vote.
+type checkpointVote struct {
+	Source hybrid.Checkpoint
+	Target hybrid.Checkpoint
+}
+
+// Protection is a local slashing-protection database remembering every block
+// a validator has attested to and its latest checkpoint vote, so that the node
+// never signs two conflicting attestations, even across restarts. Checkpoint
+// votes are protected by only allowing source and target epochs to advance,
+// which rules out both double and surround votes.
+type Protection struct {
+	db   ethdb.KeyValueStore
+	lock sync.Mutex
+}
+
+// NewProtection creates a slashing-protection database on top of the given
+// key-value store.
+func NewProtection(db ethdb.KeyValueStore) *Protection {
+	return &Protection{db: db}
+}
+
+// signedAttestationKey = signedAttestationPrefix + validator + number
+func signedAttestationKey(validator common.Address, number uint64) []byte {
+	key := make([]byte, len(signedAttestationPrefix)+common.AddressLength+8)
+	copy(key, signedAttestationPrefix)
+	copy(key[len(signedAttestationPrefix):], validator.Bytes())
+	binary.BigEndian.PutUint64(key[len(signedAttestationPrefix)+common.AddressLength:], number)
+	return key
+}
+
+// signedVoteKey = signedVotePrefix + validator
+func signedVoteKey(validator common.Address) []byte {
+	key := make([]byte, len(signedVotePrefix)+common.AddressLength)
+	copy(key, signedVotePrefix)
+	copy(key[len(signedVotePrefix):], validator.Bytes())
+	return key
+}
+
+// Signed returns the hash of the block the validator attested to at the given
+// height, or the zero hash if it did not sign anything there.
+func (p *Protection) Signed(validator common.Address, number uint64) common.Hash {
+	data, _ := p.db.Get(signedAttestationKey(validator, number))
+	if len(data) != common.HashLength {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// LastVote returns the source and target checkpoints of the latest checkpoint
+// vote signed by the validator.
+func (p *Protection) LastVote(validator common.Address) (hybrid.Checkpoint, hybrid.Checkpoint, bool) {
+	data, _ := p.db.Get(signedVoteKey(validator))
+	if len(data) == 0 {
+		return hybrid.Checkpoint{}, hybrid.Checkpoint{}, false
+	}
+	var vote checkpointVote
+	if err := rlp.DecodeBytes(data, &vote); err != nil {
+		return hybrid.Checkpoint{}, hybrid.Checkpoint{}, false
+	}
+	return vote.Source, vote.Target, true
+}
+
+// Protect checks whether the validator may sign the given attestation and, if
+// so, records the vote before returning. The record is written before the
+// attestation is signed, so a crash in between can never lead to a slashable
+// vote.
+func (p *Protection) Protect(attestation *hybrid.Attestation) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	validator, number := attestation.Validator, attestation.BlockNumber
+	switch signed := p.Signed(validator, number); signed {
+	case common.Hash{}:
+	case attestation.BlockHash:
+		return errAlreadySigned
+	default:
+		return ErrDoubleVote
+	}
+	source, target := attestation.Source, attestation.Target
+	ffg := source.Epoch < target.Epoch
+	if ffg {
+		if lastSource, lastTarget, ok := p.LastVote(validator); ok && (lastSource != source || lastTarget != target) {
+			if source.Epoch < lastSource.Epoch {
+				return ErrSurroundVote
+			}
+			if target.Epoch <= lastTarget.Epoch {
+				return ErrDoubleVote
+			}
+		}
+	}
+	batch := p.db.NewBatch()
+	batch.Put(signedAttestationKey(validator, number), attestation.BlockHash.Bytes())
+	if ffg {
+		vote, err := rlp.EncodeToBytes(&checkpointVote{Source: source, Target: target})
+		if err != nil {
+			return err
+		}
+		batch.Put(signedVoteKey(validator), vote)
+	}
+	return batch.Write()
+}
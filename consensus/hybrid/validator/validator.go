@@ -0,0 +1,160 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+
+// Package validator implements the built-in hybrid validator client, which
+// attests to every new canonical head with a locally held key.
+package validator
+
+import (
+	"crypto/ecdsa"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// chainHeadChanSize is the size of channel listening to ChainHeadEvent.
+const chainHeadChanSize = 16
+
+// Chain defines the subset of the blockchain the validator client needs to
+// follow the canonical head.
+type Chain interface {
+	Config() *params.ChainConfig
+	CurrentHeader() *types.Header
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// Engine defines the consensus engine methods the validator client needs.
+type Engine interface {
+	// Checkpoints returns the source and target checkpoints to vote for when
+	// attesting to the given block.
+	Checkpoints(header *types.Header) (source hybrid.Checkpoint, target hybrid.Checkpoint, err error)
+}
+
+// SubmitFn hands a signed attestation over to the consensus engine and the
+// gossip layer.
+type SubmitFn func(attestation *hybrid.Attestation) error
+
+// Validator is the duty loop of a single hybrid validator. It signs an
+// attestation for each new canonical head, consulting the slashing-protection
+// database first.
+type Validator struct {
+	key        *ecdsa.PrivateKey
+	address    common.Address
+	chain      Chain
+	engine     Engine
+	protection *Protection
+	submit     SubmitFn
+
+	quit     chan struct{}
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	log      log.Logger
+}
+
+// New creates a validator client signing with the given key.
+func New(key *ecdsa.PrivateKey, chain Chain, engine Engine, protection *Protection, submit SubmitFn) *Validator {
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	return &Validator{
+		key:        key,
+		address:    address,
+		chain:      chain,
+		engine:     engine,
+		protection: protection,
+		submit:     submit,
+		quit:       make(chan struct{}),
+		log:        log.New("validator", address),
+	}
+}
+
+// Address returns the address of the validator.
+func (v *Validator) Address() common.Address {
+	return v.address
+}
+
+// Start attests to the current head and then starts following the chain.
+func (v *Validator) Start() {
+	heads := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	sub := v.chain.SubscribeChainHeadEvent(heads)
+
+	v.attest(v.chain.CurrentHeader())
+
+	v.wg.Add(1)
+	go v.loop(heads, sub)
+
+	v.log.Info("Started hybrid validator")
+}
+
+// Stop terminates the duty loop.
+func (v *Validator) Stop() {
+	v.stopOnce.Do(func() {
+		close(v.quit)
+		v.wg.Wait()
+		v.log.Info("Stopped hybrid validator")
+	})
+}
+
+// loop attests to each new canonical head until stopped.
+func (v *Validator) loop(heads chan core.ChainHeadEvent, sub event.Subscription) {
+	defer v.wg.Done()
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-heads:
+			v.attest(ev.Block.Header())
+		case <-sub.Err():
+			return
+		case <-v.quit:
+			return
+		}
+	}
+}
+
+// attest signs and submits an attestation for the given block, unless the
+// slashing-protection database forbids it.
+func (v *Validator) attest(header *types.Header) {
+	if header == nil || !v.chain.Config().IsHybrid(header.Number) {
+		return
+	}
+	number, hash := header.Number.Uint64(), header.Hash()
+
+	source, target, err := v.engine.Checkpoints(header)
+	if err != nil {
+		v.log.Debug("Failed to determine checkpoints", "number", number, "hash", hash, "err", err)
+		return
+	}
+	// Stick to the source already voted for within an epoch, even if a newer
+	// checkpoint got justified in the meantime
+	if lastSource, lastTarget, ok := v.protection.LastVote(v.address); ok && lastTarget == target {
+		source = lastSource
+	}
+	attestation := hybrid.NewAttestation(v.address, hash, number, source, target)
+
+	switch err := v.protection.Protect(attestation); err {
+	case nil:
+	case errAlreadySigned:
+		return
+	case ErrDoubleVote, ErrSurroundVote:
+		v.log.Warn("Refusing to sign slashable attestation", "number", number, "hash", hash, "source", source.Epoch, "target", target.Epoch, "err", err)
+		return
+	default:
+		v.log.Error("Failed to update slashing protection", "number", number, "hash", hash, "err", err)
+		return
+	}
+	if err := attestation.Sign(v.key, v.chain.Config().ChainID); err != nil {
+		v.log.Error("Failed to sign attestation", "number", number, "hash", hash, "err", err)
+		return
+	}
+	if err := v.submit(attestation); err != nil {
+		v.log.Debug("Attestation not accepted", "number", number, "hash", hash, "err", err)
+		return
+	}
+	v.log.Debug("Submitted attestation", "number", number, "hash", hash, "source", source.Epoch, "target", target.Epoch)
+}
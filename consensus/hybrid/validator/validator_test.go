@@ -0,0 +1,156 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+//
+// The go-altcoinchain library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-altcoinchain library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-altcoinchain library. If not, see <http://www.gnu.org/licenses/>.
+
+package validator
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// genesisEngine is a consensus engine stub voting for the genesis checkpoint
+// only, turning every attestation into a plain head vote.
+type genesisEngine struct {
+	genesis common.Hash
+}
+
+func (e *genesisEngine) Checkpoints(header *types.Header) (hybrid.Checkpoint, hybrid.Checkpoint, error) {
+	checkpoint := hybrid.Checkpoint{Epoch: 0, Hash: e.genesis}
+	return checkpoint, checkpoint, nil
+}
+
+// Tests that the validator signs an attestation for every new canonical head.
+func TestAttestCanonicalHeads(t *testing.T) {
+	config := *params.TestChainConfig
+	config.HybridBlock = big.NewInt(0)
+
+	var (
+		key, _  = crypto.GenerateKey()
+		db      = rawdb.NewMemoryDatabase()
+		gspec   = &core.Genesis{Config: &config, BaseFee: big.NewInt(params.InitialBaseFee)}
+		genesis = gspec.MustCommit(db)
+		engine  = ethash.NewFaker()
+	)
+	blocks, _ := core.GenerateChain(&config, genesis, engine, db, 3, nil)
+
+	chain, err := core.NewBlockChain(db, nil, &config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create blockchain: %v", err)
+	}
+	defer chain.Stop()
+
+	submitted := make(chan *hybrid.Attestation, 16)
+	validator := New(key, chain, &genesisEngine{genesis.Hash()}, NewProtection(rawdb.NewMemoryDatabase()), func(att *hybrid.Attestation) error {
+		submitted <- att
+		return nil
+	})
+	validator.Start()
+	defer validator.Stop()
+
+	for _, block := range blocks {
+		if _, err := chain.InsertChain(types.Blocks{block}); err != nil {
+			t.Fatalf("failed to insert block %d: %v", block.NumberU64(), err)
+		}
+	}
+	for _, want := range append([]*types.Block{genesis}, blocks...) {
+		select {
+		case att := <-submitted:
+			if att.BlockHash != want.Hash() || att.BlockNumber != want.NumberU64() {
+				t.Fatalf("attestation mismatch: have %d/%x, want %d/%x", att.BlockNumber, att.BlockHash, want.NumberU64(), want.Hash())
+			}
+			if signer, err := att.RecoverValidator(config.ChainID); err != nil || signer != validator.Address() {
+				t.Fatalf("signer mismatch: have %x, want %x (err %v)", signer, validator.Address(), err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no attestation for block %d", want.NumberU64())
+		}
+	}
+}
+
+// Tests that the slashing-protection database refuses conflicting votes, also
+// after being reopened.
+func TestProtectionDoubleVote(t *testing.T) {
+	var (
+		db        = rawdb.NewMemoryDatabase()
+		validator = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		first     = common.HexToHash("0x01")
+		second    = common.HexToHash("0x02")
+		source    = hybrid.Checkpoint{Epoch: 0, Hash: common.HexToHash("0x10")}
+		target    = hybrid.Checkpoint{Epoch: 0, Hash: common.HexToHash("0x10")}
+	)
+	if err := NewProtection(db).Protect(hybrid.NewAttestation(validator, first, 10, source, target)); err != nil {
+		t.Fatalf("failed to protect first vote: %v", err)
+	}
+	protection := NewProtection(db)
+	if err := protection.Protect(hybrid.NewAttestation(validator, first, 10, source, target)); err != errAlreadySigned {
+		t.Errorf("repeated vote: have %v, want %v", err, errAlreadySigned)
+	}
+	if err := protection.Protect(hybrid.NewAttestation(validator, second, 10, source, target)); err != ErrDoubleVote {
+		t.Errorf("conflicting vote: have %v, want %v", err, ErrDoubleVote)
+	}
+	if err := protection.Protect(hybrid.NewAttestation(validator, second, 11, source, target)); err != nil {
+		t.Errorf("vote at new height rejected: %v", err)
+	}
+	if have := protection.Signed(validator, 10); have != first {
+		t.Errorf("signed hash mismatch: have %x, want %x", have, first)
+	}
+}
+
+// Tests that the slashing-protection database only lets checkpoint votes
+// advance, refusing double and surround votes.
+func TestProtectionCheckpointVotes(t *testing.T) {
+	var (
+		db        = rawdb.NewMemoryDatabase()
+		validator = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	)
+	checkpoint := func(epoch uint64, hash byte) hybrid.Checkpoint {
+		return hybrid.Checkpoint{Epoch: epoch, Hash: common.Hash{hash}}
+	}
+	tests := []struct {
+		number         uint64
+		source, target hybrid.Checkpoint
+		err            error
+	}{
+		{65, checkpoint(1, 1), checkpoint(2, 2), nil},
+		{66, checkpoint(1, 1), checkpoint(2, 2), nil},              // same vote, new head
+		{67, checkpoint(1, 1), checkpoint(2, 3), ErrDoubleVote},    // different target in same epoch
+		{68, checkpoint(0, 0), checkpoint(3, 3), ErrSurroundVote},  // surrounding the signed vote
+		{69, checkpoint(1, 1), checkpoint(1, 1), nil},              // head vote only
+		{100, checkpoint(2, 2), checkpoint(3, 3), nil},             // advancing
+		{130, checkpoint(3, 3), checkpoint(4, 4), nil},             // advancing
+		{131, checkpoint(2, 2), checkpoint(4, 4), ErrSurroundVote}, // source going backwards
+	}
+	for i, tt := range tests {
+		err := NewProtection(db).Protect(hybrid.NewAttestation(validator, common.Hash{byte(i)}, tt.number, tt.source, tt.target))
+		if err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+	if source, target, ok := NewProtection(db).LastVote(validator); !ok || source != checkpoint(3, 3) || target != checkpoint(4, 4) {
+		t.Errorf("last vote mismatch: have %v -> %v, want %v -> %v", source, target, checkpoint(3, 3), checkpoint(4, 4))
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2024 The Altcoinchain Authors
+// This file is part of the go-altcoinchain library.
+
+package hybrid
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Storage layout of the ValidatorStaking contract (contracts/staking/ValidatorStaking.sol).
+// Constants do not occupy storage, so the state variables are laid out in
+// declaration order starting at slot 0.
+const (
+	validatorsSlot      = 0 // mapping(address => Validator)
+	delegationsSlot     = 1 // mapping(address => mapping(address => Delegation))
+	withdrawalQueueSlot = 2 // mapping(address => WithdrawalRequest[])
+	validatorListSlot   = 3 // address[]
+	validatorIndexSlot  = 4 // mapping(address => uint256)
+	totalStakedSlot     = 5 // uint256
+	totalValidatorsSlot = 6 // uint256
+	rewardPoolSlot      = 7 // uint256
+)
+
+// Field offsets of the Validator struct relative to its base slot.
+const (
+	selfStakeOffset         = 0
+	totalDelegatedOffset    = 1
+	commissionOffset        = 2
+	lastActiveBlockOffset   = 3
+	accRewardPerShareOffset = 4
+	flagsOffset             = 5 // isActive (byte 0) and isSlashed (byte 1) packed together
+)
+
+// maxValidatorList caps the number of validator list entries read from the
+// staking contract, protecting the node against a corrupted length slot.
+const maxValidatorList = 16384
+
+// StorageReader is the subset of the state database needed to read the
+// staking contract storage.
+type StorageReader interface {
+	GetState(addr common.Address, hash common.Hash) common.Hash
+}
+
+// StorageWriter is the subset of the state database needed to modify the
+// staking contract storage.
+type StorageWriter interface {
+	StorageReader
+	SetState(addr common.Address, key, value common.Hash)
+}
+
+// StakingValidator is the raw Validator struct stored in the staking contract.
+type StakingValidator struct {
+	SelfStake       *big.Int
+	TotalDelegated  *big.Int
+	Commission      uint64
+	LastActiveBlock uint64
+	IsActive        bool
+	IsSlashed       bool
+}
+
+// TotalStake returns the self stake plus all delegations of the validator.
+func (v *StakingValidator) TotalStake() *big.Int {
+	return new(big.Int).Add(v.SelfStake, v.TotalDelegated)
+}
+
+// mappingSlot returns the storage slot of a mapping entry keyed by an address.
+func mappingSlot(key common.Address, slot uint64) common.Hash {
+	return crypto.Keccak256Hash(common.LeftPadBytes(key.Bytes(), 32), common.BigToHash(new(big.Int).SetUint64(slot)).Bytes())
+}
+
+// arraySlot returns the storage slot of the index'th element of a dynamic
+// array whose length is stored at the given slot.
+func arraySlot(slot uint64, index uint64) common.Hash {
+	base := crypto.Keccak256Hash(common.BigToHash(new(big.Int).SetUint64(slot)).Bytes()).Big()
+	return common.BigToHash(base.Add(base, new(big.Int).SetUint64(index)))
+}
+
+// offsetSlot returns the slot located offset slots after base.
+func offsetSlot(base common.Hash, offset uint64) common.Hash {
+	return common.BigToHash(new(big.Int).Add(base.Big(), new(big.Int).SetUint64(offset)))
+}
+
+// ReadStakingValidator reads the Validator struct of addr from the staking
+// contract storage.
+func ReadStakingValidator(db StorageReader, contract common.Address, addr common.Address) *StakingValidator {
+	base := mappingSlot(addr, validatorsSlot)
+	flags := db.GetState(contract, offsetSlot(base, flagsOffset))
+
+	return &StakingValidator{
+		SelfStake:       db.GetState(contract, offsetSlot(base, selfStakeOffset)).Big(),
+		TotalDelegated:  db.GetState(contract, offsetSlot(base, totalDelegatedOffset)).Big(),
+		Commission:      db.GetState(contract, offsetSlot(base, commissionOffset)).Big().Uint64(),
+		LastActiveBlock: db.GetState(contract, offsetSlot(base, lastActiveBlockOffset)).Big().Uint64(),
+		IsActive:        flags[common.HashLength-1] != 0,
+		IsSlashed:       flags[common.HashLength-2] != 0,
+	}
+}
+
+// ReadValidatorList returns the addresses registered in the validatorList
+// array of the staking contract, in storage order.
+func ReadValidatorList(db StorageReader, contract common.Address) []common.Address {
+	length := db.GetState(contract, common.BigToHash(big.NewInt(validatorListSlot))).Big()
+	if !length.IsUint64() || length.Uint64() > maxValidatorList {
+		return nil
+	}
+	list := make([]common.Address, 0, length.Uint64())
+	for i := uint64(0); i < length.Uint64(); i++ {
+		list = append(list, common.BytesToAddress(db.GetState(contract, arraySlot(validatorListSlot, i)).Bytes()))
+	}
+	return list
+}
+
+// ReadValidatorSet derives the validator set from the staking contract storage.
+// A validator is active if it is registered, not slashed and its total stake
+// (self stake plus delegations) reaches minStake.
+func ReadValidatorSet(db StorageReader, contract common.Address, minStake *big.Int) map[common.Address]*ValidatorInfo {
+	validators := make(map[common.Address]*ValidatorInfo)
+	for _, addr := range ReadValidatorList(db, contract) {
+		v := ReadStakingValidator(db, contract, addr)
+		stake := v.TotalStake()
+
+		validators[addr] = &ValidatorInfo{
+			Address:         addr,
+			Stake:           stake,
+			Active:          v.IsActive && !v.IsSlashed && stake.Cmp(minStake) >= 0,
+			LastAttestation: v.LastActiveBlock,
+		}
+	}
+	return validators
+}
+
+// Start attaches the engine to the local blockchain and keeps the active
+// validator set in sync with the staking contract state of the canonical head.
+func (h *Hybrid) Start(chain ChainReader) {
+	h.validatorsLock.Lock()
+	h.chain = chain
+	h.validatorsLock.Unlock()
+
+	// Resume from the data persisted before the last shutdown, anchoring
+	// justification at the genesis block
+	if genesis := chain.GetHeaderByNumber(0); genesis != nil {
+		h.finalityTracker.SetAnchor(Checkpoint{Epoch: 0, Hash: genesis.Hash()})
+	}
+	if head := chain.CurrentHeader(); head != nil {
+		h.loadDatabase(head.Number.Uint64())
+	}
+	if block := chain.CurrentFinalizedBlock(); block != nil {
+		h.finalityTracker.MarkFinalized(block.NumberU64(), block.Hash())
+	}
+	heads := make(chan core.ChainHeadEvent, 16)
+	sub := chain.SubscribeChainHeadEvent(heads)
+
+	h.updateHead(chain.CurrentHeader())
+
+	h.wg.Add(1)
+	go h.loop(heads, sub)
+}
+
+// loop reloads the validator set whenever the canonical head changes. Reorgs
+// need no special handling as sets are keyed by block hash.
+func (h *Hybrid) loop(heads chan core.ChainHeadEvent, sub event.Subscription) {
+	defer h.wg.Done()
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-heads:
+			h.updateHead(ev.Block.Header())
+		case <-sub.Err():
+			return
+		case <-h.quit:
+			return
+		}
+	}
+}
+
+// updateHead replaces the current validator set with the one derived from the
+// state of the given head. Locally tracked attestation progress is retained.
+func (h *Hybrid) updateHead(header *types.Header) {
+	if header == nil || !h.chain.Config().IsHybrid(header.Number) {
+		return
+	}
+	set, err := h.loadValidatorSet(header)
+	if err != nil {
+		h.log.Warn("Failed to load validator set", "number", header.Number, "hash", header.Hash(), "err", err)
+		return
+	}
+	h.validatorsLock.Lock()
+	validators := make(map[common.Address]*ValidatorInfo, len(set))
+	for addr, info := range set {
+		v := &ValidatorInfo{
+			Address:         info.Address,
+			Stake:           new(big.Int).Set(info.Stake),
+			Active:          info.Active,
+			LastAttestation: info.LastAttestation,
+		}
+		if prev, ok := h.validators[addr]; ok && prev.LastAttestation > v.LastAttestation {
+			v.LastAttestation = prev.LastAttestation
+		}
+		validators[addr] = v
+	}
+	h.validators = validators
+	h.validatorsLock.Unlock()
+
+	// Prune outside of the validator lock, the finality tracker takes it while
+	// holding its own lock
+	h.prune(header.Number.Uint64())
+
+	h.log.Debug("Updated validator set", "number", header.Number, "hash", header.Hash(), "validators", len(validators))
+}
+
+// loadValidatorSet returns the validator set defined by the staking contract in
+// the post-state of the given block. Results are cached by block hash and must
+// not be modified by the caller.
+func (h *Hybrid) loadValidatorSet(header *types.Header) (map[common.Address]*ValidatorInfo, error) {
+	hash := header.Hash()
+	if cached, ok := h.validatorSets.Get(hash); ok {
+		return cached.(map[common.Address]*ValidatorInfo), nil
+	}
+	statedb, err := h.chain.StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	set := ReadValidatorSet(statedb, h.config.StakingContract, h.config.MinStake)
+	h.validatorSets.Add(hash, set)
+	return set, nil
+}
+
+// validatorSetAt returns the validator set derived from the state of the given
+// block, or ErrUnknownValidatorSet if the block or its state is not available
+// locally. Blocks before the hybrid fork use the current set. The returned map
+// must not be modified.
+func (h *Hybrid) validatorSetAt(hash common.Hash) (map[common.Address]*ValidatorInfo, error) {
+	h.validatorsLock.RLock()
+	chain, current := h.chain, h.validators
+	h.validatorsLock.RUnlock()
+
+	if chain == nil {
+		return current, nil
+	}
+	header := chain.GetHeaderByHash(hash)
+	if header == nil {
+		return nil, ErrUnknownValidatorSet
+	}
+	if !chain.Config().IsHybrid(header.Number) {
+		return current, nil
+	}
+	set, err := h.loadValidatorSet(header)
+	if err != nil {
+		return nil, ErrUnknownValidatorSet
+	}
+	return set, nil
+}
+
+// validatorsAt returns the validator set derived from the state of the given
+// block, falling back to the current set if the block or its state is not
+// available locally. The returned map must not be modified.
+func (h *Hybrid) validatorsAt(hash common.Hash) map[common.Address]*ValidatorInfo {
+	if set, err := h.validatorSetAt(hash); err == nil {
+		return set
+	}
+	h.validatorsLock.RLock()
+	defer h.validatorsLock.RUnlock()
+
+	return h.validators
+}
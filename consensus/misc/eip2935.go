@@ -0,0 +1,39 @@
+// Copyright 2025 The Altcoinchain Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ApplyHistoryStorageFork installs the EIP-2935 historical block hashes
+// contract if it is not yet present in the state.
+//
+// Unlike mainnet, this chain cannot rely on the keyless deployment transaction
+// having been mined, so the contract is placed irregularly on the first Prague
+// block that finds it missing. Subsequent calls are no-ops.
+func ApplyHistoryStorageFork(statedb *state.StateDB) {
+	if statedb.GetCodeSize(params.HistoryStorageAddress) != 0 {
+		return
+	}
+	if !statedb.Exist(params.HistoryStorageAddress) {
+		statedb.CreateAccount(params.HistoryStorageAddress)
+	}
+	statedb.SetNonce(params.HistoryStorageAddress, 1)
+	statedb.SetCode(params.HistoryStorageAddress, params.HistoryStorageCode)
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	errMissingDACommitment    = errors.New("header is missing daCommitment")
+	errUnexpectedDACommitment = errors.New("invalid daCommitment before fork: have non-nil, want <nil>")
+)
+
+// VerifyEip7594Header verifies the presence of the data availability commitment
+// introduced with PeerDAS (EIP-7594). Fusaka headers must carry one, earlier
+// headers must not. The commitment itself depends on the block body, so it is
+// checked against the transactions during body validation.
+func VerifyEip7594Header(config *params.ChainConfig, header *types.Header) error {
+	if !config.IsFusaka(header.Number) {
+		if header.DACommitment != nil {
+			return errUnexpectedDACommitment
+		}
+		return nil
+	}
+	if header.DACommitment == nil {
+		return errMissingDACommitment
+	}
+	return nil
+}
@@ -21,7 +21,8 @@ type ErasureCoding struct {
 	DataShards int
 	// ParityShards is the number of parity (redundancy) shards
 	ParityShards int
-	// ShardSize is the size of each shard in bytes
+	// ShardSize is the minimum size of each shard in bytes. Shards grow beyond
+	// it, up to MaxShardSize, to fit larger data.
 	ShardSize int
 }
 
@@ -29,7 +30,7 @@ type ErasureCoding struct {
 type ErasureConfig struct {
 	DataShards   int // Number of data shards (k)
 	ParityShards int // Number of parity shards (m)
-	ShardSize    int // Size of each shard in bytes
+	ShardSize    int // Minimum size of each shard in bytes
 }
 
 // DefaultErasureConfig returns the default erasure coding configuration
@@ -42,11 +43,15 @@ func DefaultErasureConfig() *ErasureConfig {
 	}
 }
 
+// MaxShardSize caps the size of a single shard, bounding the amount of data a
+// single encoding can carry.
+const MaxShardSize = 4 * 1024 * 1024
+
 var (
-	ErrInsufficientShards  = errors.New("insufficient shards for reconstruction")
-	ErrShardSizeMismatch   = errors.New("shard sizes do not match")
-	ErrInvalidShardCount   = errors.New("invalid shard count")
-	ErrDataTooLarge        = errors.New("data exceeds maximum size")
+	ErrInsufficientShards   = errors.New("insufficient shards for reconstruction")
+	ErrShardSizeMismatch    = errors.New("shard sizes do not match")
+	ErrInvalidShardCount    = errors.New("invalid shard count")
+	ErrDataTooLarge         = errors.New("data exceeds maximum size")
 	ErrReconstructionFailed = errors.New("data reconstruction failed")
 )
 
@@ -76,18 +81,28 @@ type EncodedShard struct {
 	BlockNumber *big.Int    // Block number this shard belongs to
 }
 
-// Encode splits data into shards and generates parity shards
-// Uses a simplified XOR-based encoding (production would use full Reed-Solomon)
+// Encode splits data into shards and generates parity shards. The code is a
+// systematic Reed-Solomon code over GF(2^8): the data shards hold the padded
+// data as is, and every byte of a parity shard is a linear combination of the
+// bytes at the same offset of the data shards, with coefficients taken from a
+// Cauchy matrix. Any DataShards of the resulting shards recover the data.
 func (ec *ErasureCoding) Encode(data []byte) ([]*EncodedShard, error) {
+	if err := ec.validate(); err != nil {
+		return nil, err
+	}
 	if len(data) == 0 {
 		return nil, errors.New("cannot encode empty data")
 	}
 
-	// Pad data to be divisible by DataShards * ShardSize
-	totalDataSize := ec.DataShards * ec.ShardSize
-	if len(data) > totalDataSize {
+	// Grow the shards if the data doesn't fit, and pad it to fill them
+	shardSize := ec.ShardSize
+	if len(data) > ec.DataShards*shardSize {
+		shardSize = (len(data) + ec.DataShards - 1) / ec.DataShards
+	}
+	if shardSize > MaxShardSize {
 		return nil, ErrDataTooLarge
 	}
+	totalDataSize := ec.DataShards * shardSize
 
 	// Pad data if needed
 	paddedData := make([]byte, totalDataSize)
@@ -97,9 +112,9 @@ func (ec *ErasureCoding) Encode(data []byte) ([]*EncodedShard, error) {
 
 	// Create data shards
 	for i := 0; i < ec.DataShards; i++ {
-		start := i * ec.ShardSize
-		end := start + ec.ShardSize
-		shardData := make([]byte, ec.ShardSize)
+		start := i * shardSize
+		end := start + shardSize
+		shardData := make([]byte, shardSize)
 		copy(shardData, paddedData[start:end])
 
 		shards[i] = &EncodedShard{
@@ -110,21 +125,14 @@ func (ec *ErasureCoding) Encode(data []byte) ([]*EncodedShard, error) {
 		}
 	}
 
-	// Generate parity shards using XOR encoding
-	// P1 = D0 XOR D1 XOR D2 XOR D3
-	// P2 = D0 XOR D2 (different combination for diversity)
+	// Generate parity shards from the Cauchy rows of the encoding matrix
 	for p := 0; p < ec.ParityShards; p++ {
 		parityIndex := ec.DataShards + p
-		parityData := make([]byte, ec.ShardSize)
+		parityData := make([]byte, shardSize)
 
-		// Generate parity using XOR of data shards
-		// Different parity shards use different XOR combinations
+		row := ec.encodingRow(parityIndex)
 		for i := 0; i < ec.DataShards; i++ {
-			// Skip some shards for diversity in parity generation
-			if p == 1 && i%2 == 1 {
-				continue // P2 only XORs even-indexed shards
-			}
-			xorBytes(parityData, shards[i].Data)
+			gfMulAdd(parityData, row[i], shards[i].Data)
 		}
 
 		shards[parityIndex] = &EncodedShard{
@@ -141,82 +149,106 @@ func (ec *ErasureCoding) Encode(data []byte) ([]*EncodedShard, error) {
 // Decode reconstructs original data from available shards
 // Requires at least DataShards number of shards (any combination of data/parity)
 func (ec *ErasureCoding) Decode(shards []*EncodedShard) ([]byte, error) {
+	if err := ec.validate(); err != nil {
+		return nil, err
+	}
 	if len(shards) < ec.DataShards {
 		return nil, ErrInsufficientShards
 	}
 
-	// Validate shard sizes
+	// Validate shard sizes, all shards of an encoding have the same size
+	shardSize := -1
 	for _, shard := range shards {
-		if len(shard.Data) != ec.ShardSize {
+		if shard == nil {
+			continue
+		}
+		if shardSize == -1 {
+			shardSize = len(shard.Data)
+		}
+		if len(shard.Data) != shardSize || shardSize < ec.ShardSize || shardSize > MaxShardSize {
 			return nil, ErrShardSizeMismatch
 		}
 	}
 
-	// Build shard map by index
+	// Build shard map by index, dropping duplicates and unknown indices
 	shardMap := make(map[int]*EncodedShard)
 	for _, shard := range shards {
+		if shard == nil || shard.Index < 0 || shard.Index >= ec.TotalShards() {
+			continue
+		}
 		shardMap[shard.Index] = shard
 	}
+	if len(shardMap) < ec.DataShards {
+		return nil, ErrInsufficientShards
+	}
 
-	// Check if all data shards are available
-	allDataPresent := true
+	// Copy the available data shards, tracking the missing ones
+	result := make([]byte, ec.DataShards*shardSize)
+	missingDataShards := make([]int, 0)
 	for i := 0; i < ec.DataShards; i++ {
-		if _, ok := shardMap[i]; !ok {
-			allDataPresent = false
-			break
+		if shard, ok := shardMap[i]; ok {
+			copy(result[i*shardSize:], shard.Data)
+		} else {
+			missingDataShards = append(missingDataShards, i)
 		}
 	}
-
-	// If all data shards present, just concatenate them
-	if allDataPresent {
-		result := make([]byte, ec.DataShards*ec.ShardSize)
-		for i := 0; i < ec.DataShards; i++ {
-			copy(result[i*ec.ShardSize:], shardMap[i].Data)
-		}
+	if len(missingDataShards) == 0 {
 		return result, nil
 	}
 
-	// Need to reconstruct missing data shards using parity
-	result := make([]byte, ec.DataShards*ec.ShardSize)
-
-	// Find which data shards are missing
-	missingDataShards := make([]int, 0)
-	for i := 0; i < ec.DataShards; i++ {
-		if _, ok := shardMap[i]; !ok {
-			missingDataShards = append(missingDataShards, i)
-		} else {
-			// Copy available data shard
-			copy(result[i*ec.ShardSize:], shardMap[i].Data)
+	// Pick DataShards of the available shards, data shards first. The rows of
+	// the encoding matrix producing them form an invertible matrix, and its
+	// inverse maps the picked shards back to the data shards.
+	picked := make([]*EncodedShard, 0, ec.DataShards)
+	for i := 0; i < ec.TotalShards() && len(picked) < ec.DataShards; i++ {
+		if shard, ok := shardMap[i]; ok {
+			picked = append(picked, shard)
 		}
 	}
-
-	// Reconstruct missing shards using XOR with parity
+	sub := make(gfMatrix, ec.DataShards)
+	for i, shard := range picked {
+		sub[i] = ec.encodingRow(shard.Index)
+	}
+	decode, err := sub.invert()
+	if err != nil {
+		return nil, ErrReconstructionFailed
+	}
 	for _, missingIdx := range missingDataShards {
-		// Try to reconstruct using P1 (XOR of all data shards)
-		p1Idx := ec.DataShards // P1 index
-		if p1Shard, ok := shardMap[p1Idx]; ok {
-			// Reconstruct: Missing = P1 XOR (all other data shards)
-			reconstructed := make([]byte, ec.ShardSize)
-			copy(reconstructed, p1Shard.Data)
-
-			for i := 0; i < ec.DataShards; i++ {
-				if i == missingIdx {
-					continue
-				}
-				if shard, ok := shardMap[i]; ok {
-					xorBytes(reconstructed, shard.Data)
-				}
-			}
-
-			copy(result[missingIdx*ec.ShardSize:], reconstructed)
-		} else {
-			return nil, ErrReconstructionFailed
+		reconstructed := result[missingIdx*shardSize : (missingIdx+1)*shardSize]
+		for j, shard := range picked {
+			gfMulAdd(reconstructed, decode[missingIdx][j], shard.Data)
 		}
 	}
 
 	return result, nil
 }
 
+// validate checks that the configured shard counts can be served by a
+// Reed-Solomon code over GF(2^8).
+func (ec *ErasureCoding) validate() error {
+	if ec.DataShards <= 0 || ec.ParityShards < 0 || ec.TotalShards() > gfOrder || ec.ShardSize <= 0 {
+		return ErrInvalidShardCount
+	}
+	return nil
+}
+
+// encodingRow returns the row of the encoding matrix producing the shard with
+// the given index. Data shards use the identity rows, parity shards the rows of
+// the Cauchy matrix 1/(x_p + y_i) with x_p = index and y_i = i. As every square
+// submatrix of a Cauchy matrix is invertible, so is every selection of
+// DataShards rows of the whole encoding matrix.
+func (ec *ErasureCoding) encodingRow(index int) []byte {
+	row := make([]byte, ec.DataShards)
+	if index < ec.DataShards {
+		row[index] = 1
+		return row
+	}
+	for i := range row {
+		row[i] = gfInv(byte(index) ^ byte(i))
+	}
+	return row
+}
+
 // VerifyShard verifies a shard's integrity using its commitment
 func (ec *ErasureCoding) VerifyShard(shard *EncodedShard) bool {
 	if shard == nil || len(shard.Data) == 0 {
@@ -236,7 +268,7 @@ func (ec *ErasureCoding) CanReconstruct(shards []*EncodedShard) bool {
 	seen := make(map[int]bool)
 	validCount := 0
 	for _, shard := range shards {
-		if shard == nil || shard.Index >= ec.TotalShards() {
+		if shard == nil || shard.Index < 0 || shard.Index >= ec.TotalShards() {
 			continue
 		}
 		if !seen[shard.Index] {
@@ -291,17 +323,6 @@ func calculateShardCommitment(data []byte, index int) common.Hash {
 	return hash
 }
 
-// xorBytes XORs src into dst in-place
-func xorBytes(dst, src []byte) {
-	n := len(dst)
-	if len(src) < n {
-		n = len(src)
-	}
-	for i := 0; i < n; i++ {
-		dst[i] ^= src[i]
-	}
-}
-
 // ErasureEncodedData represents fully encoded data with metadata
 type ErasureEncodedData struct {
 	OriginalSize int             // Original data size before padding
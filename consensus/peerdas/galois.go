@@ -0,0 +1,121 @@
+// Copyright 2025 The Altcoinchain Authors
+// This file implements the GF(2^8) arithmetic used by the Reed-Solomon code
+//
+// Elements are bytes, addition is XOR and multiplication is carried out with
+// log/exp tables over the primitive polynomial x^8 + x^4 + x^3 + x^2 + 1.
+
+package peerdas
+
+import "errors"
+
+// gfPolynomial is the primitive polynomial generating the field, with the
+// x^8 term dropped.
+const gfPolynomial = 0x1d
+
+// gfOrder is the number of elements of the field, bounding the total number of
+// shards a Reed-Solomon code over it can produce.
+const gfOrder = 256
+
+var (
+	gfExp [2 * gfOrder]byte // gfExp[i] = g^i, doubled to skip the modulo in gfMul
+	gfLog [gfOrder]byte     // gfLog[g^i] = i, undefined for 0
+)
+
+var errSingularMatrix = errors.New("matrix is singular")
+
+func init() {
+	x := 1
+	for i := 0; i < gfOrder-1; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = byte(i)
+
+		x <<= 1
+		if x >= gfOrder {
+			x = (x - gfOrder) ^ gfPolynomial
+		}
+	}
+	for i := gfOrder - 1; i < len(gfExp); i++ {
+		gfExp[i] = gfExp[i-(gfOrder-1)]
+	}
+}
+
+// gfMul multiplies two field elements.
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+// gfInv returns the multiplicative inverse of a non-zero field element.
+func gfInv(a byte) byte {
+	if a == 0 {
+		panic("peerdas: inverse of zero")
+	}
+	return gfExp[gfOrder-1-int(gfLog[a])]
+}
+
+// gfMulAdd adds c times src to dst in-place.
+func gfMulAdd(dst []byte, c byte, src []byte) {
+	if c == 0 {
+		return
+	}
+	logc := int(gfLog[c])
+	for i, b := range src {
+		if b != 0 {
+			dst[i] ^= gfExp[logc+int(gfLog[b])]
+		}
+	}
+}
+
+// gfMatrix is a dense matrix over GF(2^8), stored row by row.
+type gfMatrix [][]byte
+
+// newGFMatrix returns a zero matrix of the given size.
+func newGFMatrix(rows, cols int) gfMatrix {
+	m := make(gfMatrix, rows)
+	for i := range m {
+		m[i] = make([]byte, cols)
+	}
+	return m
+}
+
+// invert returns the inverse of a square matrix using Gauss-Jordan
+// elimination. The receiver is left untouched.
+func (m gfMatrix) invert() (gfMatrix, error) {
+	n := len(m)
+
+	// Work on [m | I] and reduce the left half to the identity
+	work := newGFMatrix(n, 2*n)
+	for i := range m {
+		copy(work[i], m[i])
+		work[i][n+i] = 1
+	}
+	for col := 0; col < n; col++ {
+		pivot := col
+		for pivot < n && work[pivot][col] == 0 {
+			pivot++
+		}
+		if pivot == n {
+			return nil, errSingularMatrix
+		}
+		work[col], work[pivot] = work[pivot], work[col]
+
+		if c := work[col][col]; c != 1 {
+			inv := gfInv(c)
+			for j := range work[col] {
+				work[col][j] = gfMul(work[col][j], inv)
+			}
+		}
+		for row := 0; row < n; row++ {
+			if row != col && work[row][col] != 0 {
+				gfMulAdd(work[row], work[row][col], work[col])
+			}
+		}
+	}
+	inverse := make(gfMatrix, n)
+	for i := range work {
+		inverse[i] = work[i][n:]
+	}
+	return inverse, nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 The Altcoinchain Authors
+// This file runs the PeerDAS protocol messages over devp2p
+//
+// Every connected peer running the `peerdas` capability is tracked by the
+// Protocol, which uses them as the transport for sample requests, responses,
+// announcements and pushes.
+
+package peerdas
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+const (
+	// protocolLength is the number of message codes used by the protocol.
+	protocolLength = SamplePushMsg + 1
+
+	// maxMessageSize is the maximum cap on the size of a protocol message.
+	maxMessageSize = 10 * 1024 * 1024
+)
+
+var (
+	errPeerAlreadyRegistered = errors.New("peer already registered")
+	errPeerNotRegistered     = errors.New("peer not registered")
+	errMsgTooLarge           = errors.New("message too long")
+	errInvalidMsgCode        = errors.New("invalid message code")
+)
+
+// peer is a remote node connected on the `peerdas` protocol.
+type peer struct {
+	id string // Unique ID for the peer, cached
+
+	*p2p.Peer                   // The embedded P2P package peer
+	rw        p2p.MsgReadWriter // Input/output streams for peerdas
+	version   uint              // Protocol version negotiated
+}
+
+// PeerInfo represents a short summary of the `peerdas` sub-protocol metadata
+// known about a connected peer.
+type PeerInfo struct {
+	Version uint `json:"version"` // PeerDAS protocol version negotiated
+}
+
+// MakeProtocols constructs the P2P protocol definitions for `peerdas`, one for
+// every supported version, wiring the network callbacks of proto to the peers
+// connected through them.
+func MakeProtocols(proto *Protocol) []p2p.Protocol {
+	proto.SetNetworkCallbacks(proto.send, proto.peerIDs)
+
+	protocols := make([]p2p.Protocol, len(ProtocolVersions))
+	for i, version := range ProtocolVersions {
+		version := version // Closure
+
+		protocols[i] = p2p.Protocol{
+			Name:    ProtocolName,
+			Version: version,
+			Length:  protocolLength,
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				return proto.runPeer(&peer{id: p.ID().String(), Peer: p, rw: rw, version: version})
+			},
+			PeerInfo: func(id enode.ID) interface{} {
+				proto.peersLock.RLock()
+				defer proto.peersLock.RUnlock()
+
+				if p, ok := proto.peers[id.String()]; ok {
+					return &PeerInfo{Version: p.version}
+				}
+				return nil
+			},
+		}
+	}
+	return protocols
+}
+
+// runPeer registers a peer for the lifetime of its connection and handles its
+// inbound messages. When this function terminates, the peer is disconnected.
+func (p *Protocol) runPeer(peer *peer) error {
+	p.peersLock.Lock()
+	if _, ok := p.peers[peer.id]; ok {
+		p.peersLock.Unlock()
+		return errPeerAlreadyRegistered
+	}
+	p.peers[peer.id] = peer
+	p.peersLock.Unlock()
+
+	defer func() {
+		p.peersLock.Lock()
+		delete(p.peers, peer.id)
+		p.peersLock.Unlock()
+
+		p.dropRequests(peer.id)
+	}()
+	for {
+		if err := p.handleMessage(peer); err != nil {
+			peer.Log().Debug("Message handling failed in `peerdas`", "err", err)
+			return err
+		}
+	}
+}
+
+// handleMessage is invoked whenever an inbound message is received from a
+// remote peer. The remote connection is torn down upon returning any error.
+func (p *Protocol) handleMessage(peer *peer) error {
+	msg, err := peer.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > maxMessageSize {
+		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
+	}
+	defer msg.Discard()
+
+	if msg.Code >= protocolLength {
+		return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
+	}
+	data, err := io.ReadAll(msg.Payload)
+	if err != nil {
+		return err
+	}
+	var packet interface{}
+	if peer.version == PEERDAS1 {
+		packet, err = decodeMessageV1(uint8(msg.Code), data)
+	} else {
+		packet, err = DecodeMessage(uint8(msg.Code), data)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: message %v: %v", ErrInvalidMessage, msg, err)
+	}
+	switch packet := packet.(type) {
+	case *SampleRequest:
+		return p.HandleSampleRequest(peer.id, packet)
+	case *SampleResponse:
+		return p.HandleSampleResponse(peer.id, packet)
+	case *SampleAnnouncement:
+		return p.HandleSampleAnnouncement(peer.id, packet)
+	case *SamplePush:
+		return p.HandleSamplePush(peer.id, packet)
+	default:
+		return fmt.Errorf("%w: %T", ErrInvalidMessage, packet)
+	}
+}
+
+// send delivers a protocol message to a connected peer.
+func (p *Protocol) send(peerID string, msgCode uint8, data interface{}) error {
+	p.peersLock.RLock()
+	peer := p.peers[peerID]
+	p.peersLock.RUnlock()
+
+	if peer == nil {
+		return errPeerNotRegistered
+	}
+	if peer.version == PEERDAS1 {
+		data = toV1(data)
+	}
+	return p2p.Send(peer.rw, uint64(msgCode), data)
+}
+
+// peerIDs returns the IDs of the connected peers.
+func (p *Protocol) peerIDs() []string {
+	p.peersLock.RLock()
+	defer p.peersLock.RUnlock()
+
+	ids := make([]string, 0, len(p.peers))
+	for id := range p.peers {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// PeerCount returns the number of connected `peerdas` peers.
+func (p *Protocol) PeerCount() int {
+	p.peersLock.RLock()
+	defer p.peersLock.RUnlock()
+
+	return len(p.peers)
+}
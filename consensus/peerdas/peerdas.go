@@ -11,11 +11,12 @@ package peerdas
 import (
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/params"
-	"github.com/ethereum/go-ethereum/trie"
 )
 
 var (
@@ -56,53 +57,103 @@ func (p *PeerDAS) IsActive(blockNumber *big.Int) bool {
 	return p.config != nil && p.config.IsFusaka(blockNumber)
 }
 
-// DataSample represents a sample of data for availability verification
+// DataSample represents a sample of data for availability verification. The
+// samples of a blob are the cells of the extended blob (EIP-7594), carrying the
+// KZG commitment to the blob and the proof of the cell against it, so a sample
+// verifies without the rest of the data. The samples of block data are shards,
+// carrying the Merkle branch of their commitment to the data root of the block.
 type DataSample struct {
 	BlockNumber *big.Int
-	DataHash    common.Hash
+	DataHash    common.Hash // Hash of the sampled data, the versioned hash for blobs
 	SampleIndex uint64
 	SampleData  []byte
-	MerkleProof [][]byte    // Merkle proof for this sample
 	Commitment  common.Hash // Commitment hash for this sample
+
+	KZGCommitment *kzg4844.Commitment `rlp:"nil,optional"` // Commitment to the sampled blob
+	KZGProof      *kzg4844.Proof      `rlp:"nil,optional"` // Proof of the cell against KZGCommitment
+	Proof         []common.Hash       `rlp:"optional"`     // Merkle branch of Commitment to the data root
 }
 
-// SampleCommitment represents the commitment to all samples for a block
-type SampleCommitment struct {
-	BlockNumber *big.Int
-	DataHash    common.Hash
-	MerkleRoot  common.Hash // Root of Merkle tree over all samples
-	SampleCount uint64      // Total number of samples
+// isCell reports whether the sample is a cell of a blob.
+func (s *DataSample) isCell() bool {
+	return s.KZGCommitment != nil || s.KZGProof != nil
 }
 
-// VerifySample verifies a data availability sample
-// Verifies the Merkle proof and sample integrity
-func (p *PeerDAS) VerifySample(sample *DataSample, commitment *SampleCommitment) error {
+// VerifySample verifies a data availability sample. Cells of blobs are verified
+// against the blob commitment with their KZG proof, other samples only for
+// integrity, DAValidator.VerifySampleProof ties them to a block.
+func (p *PeerDAS) VerifySample(sample *DataSample) error {
 	if !p.IsActive(sample.BlockNumber) {
 		return ErrPeerDASNotActive
 	}
-
-	if commitment == nil {
-		return errors.New("sample commitment required for verification")
+	if err := p.verifyErasureCoding(sample); err != nil {
+		return err
 	}
-
-	// Verify sample hash matches expected
-	expectedHash := p.calculateSampleHash(sample.SampleData, sample.SampleIndex)
-	if sample.Commitment != expectedHash {
-		return ErrInvalidSample
+	if sample.isCell() {
+		return p.verifyCells([]*DataSample{sample})
 	}
+	return nil
+}
 
-	// Verify Merkle proof if provided
-	if len(sample.MerkleProof) > 0 {
-		if err := p.verifyMerkleProof(sample, commitment); err != nil {
-			return ErrSampleVerificationFailed
+// FilterValidSamples returns the samples passing verification. The cells among
+// them are verified in a single batch, and only one by one if the batch fails to
+// single out the invalid ones.
+func (p *PeerDAS) FilterValidSamples(samples []*DataSample) []*DataSample {
+	var (
+		valid = make([]*DataSample, 0, len(samples))
+		cells []*DataSample
+	)
+	for _, sample := range samples {
+		if sample == nil || !p.IsActive(sample.BlockNumber) || p.verifyErasureCoding(sample) != nil {
+			continue
+		}
+		if sample.isCell() {
+			cells = append(cells, sample)
+		} else {
+			valid = append(valid, sample)
 		}
 	}
-
-	// Verify erasure coding integrity if this is an encoded shard
-	if err := p.verifyErasureCoding(sample); err != nil {
-		return err
+	if err := p.verifyCells(cells); err == nil {
+		return append(valid, cells...)
 	}
+	for _, cell := range cells {
+		if p.verifyCells([]*DataSample{cell}) == nil {
+			valid = append(valid, cell)
+		}
+	}
+	return valid
+}
 
+// verifyCells verifies blob cell samples against the commitments they carry in
+// a single batch, checking that the commitments are the ones of the blobs the
+// samples are keyed by.
+func (p *PeerDAS) verifyCells(samples []*DataSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	var (
+		hasher      = sha256.New()
+		commitments = make([]kzg4844.Commitment, len(samples))
+		indices     = make([]uint64, len(samples))
+		cells       = make([]kzg4844.Cell, len(samples))
+		proofs      = make([]kzg4844.Proof, len(samples))
+	)
+	for i, sample := range samples {
+		if sample.KZGCommitment == nil || sample.KZGProof == nil {
+			return ErrInvalidSample
+		}
+		if sample.SampleIndex >= kzg4844.CellsPerExtBlob || len(sample.SampleData) != kzg4844.BytesPerCell {
+			return ErrInvalidSample
+		}
+		if common.Hash(kzg4844.CalcBlobHashV1(hasher, sample.KZGCommitment)) != sample.DataHash {
+			return ErrInvalidSample
+		}
+		commitments[i], indices[i], proofs[i] = *sample.KZGCommitment, sample.SampleIndex, *sample.KZGProof
+		copy(cells[i][:], sample.SampleData)
+	}
+	if err := kzg4844.VerifyCellProofBatch(commitments, indices, cells, proofs); err != nil {
+		return fmt.Errorf("%w: %v", ErrSampleVerificationFailed, err)
+	}
 	return nil
 }
 
@@ -118,37 +169,9 @@ func (p *PeerDAS) calculateSampleHash(sampleData []byte, sampleIndex uint64) com
 	return hash
 }
 
-// verifyMerkleProof verifies the Merkle proof for a sample
-func (p *PeerDAS) verifyMerkleProof(sample *DataSample, commitment *SampleCommitment) error {
-	// Build a proof database from the Merkle proof
-	proofDB := make(map[string][]byte)
-	for i, proof := range sample.MerkleProof {
-		key := common.BytesToHash(proof).Hex()
-		proofDB[key] = proof
-		_ = i // Use index if needed for proof ordering
-	}
-
-	// Create a simple proof reader
-	proofReader := &simpleProofDB{data: proofDB}
-
-	// Calculate the sample key/index
-	sampleKey := p.sampleIndexToKey(sample.SampleIndex)
-
-	// Verify the proof against the commitment root
-	value, err := trie.VerifyProof(commitment.MerkleRoot, sampleKey, proofReader)
-	if err != nil {
-		return err
-	}
-
-	// Verify the value matches the sample commitment
-	if common.BytesToHash(value) != sample.Commitment {
-		return ErrInvalidSample
-	}
-
-	return nil
-}
-
-// verifyErasureCoding verifies erasure coding integrity for a sample
+// verifyErasureCoding verifies erasure coding integrity for a sample, checking
+// that its commitment is the one of its data and index. Whether the sample
+// belongs to a block is only known against the data root of the block.
 func (p *PeerDAS) verifyErasureCoding(sample *DataSample) error {
 	if sample == nil || len(sample.SampleData) == 0 {
 		return ErrInvalidSample
@@ -214,30 +237,6 @@ func (p *PeerDAS) ReconstructData(samples []*DataSample, metadata *ErasureEncode
 	return p.erasureCoding.DecodeFromBlock(metadata, shards)
 }
 
-// sampleIndexToKey converts a sample index to a key for Merkle tree
-func (p *PeerDAS) sampleIndexToKey(index uint64) []byte {
-	var key [8]byte
-	big.NewInt(int64(index)).FillBytes(key[:])
-	return key[:]
-}
-
-// simpleProofDB implements ethdb.KeyValueReader for Merkle proof verification
-type simpleProofDB struct {
-	data map[string][]byte
-}
-
-func (db *simpleProofDB) Get(key []byte) ([]byte, error) {
-	if val, ok := db.data[common.BytesToHash(key).Hex()]; ok {
-		return val, nil
-	}
-	return nil, errors.New("proof node not found")
-}
-
-func (db *simpleProofDB) Has(key []byte) (bool, error) {
-	_, ok := db.data[common.BytesToHash(key).Hex()]
-	return ok, nil
-}
-
 // RequestSample requests a data availability sample from peers
 // This is a placeholder for the network protocol implementation
 func (p *PeerDAS) RequestSample(blockNumber *big.Int, dataHash common.Hash, sampleIndex uint64) (*DataSample, error) {
@@ -254,135 +253,34 @@ func (p *PeerDAS) RequestSample(blockNumber *big.Int, dataHash common.Hash, samp
 	return nil, errors.New("PeerDAS network protocol not yet implemented")
 }
 
-// SampleData splits data into samples for availability verification
-// Creates samples and builds a Merkle tree for verification
-func (p *PeerDAS) SampleData(data []byte, blockNumber *big.Int, dataHash common.Hash) ([]*DataSample, *SampleCommitment, error) {
+// SampleBlob splits a blob into samples, the cells of the extended blob, each
+// carrying its proof out of the cell proofs of the blob. The samples are keyed
+// by the versioned hash of the blob.
+func (p *PeerDAS) SampleBlob(blockNumber *big.Int, blob *kzg4844.Blob, commitment kzg4844.Commitment, proofs []kzg4844.Proof) ([]*DataSample, error) {
 	if !p.IsActive(blockNumber) {
-		return nil, nil, ErrPeerDASNotActive
-	}
-
-	if len(data) == 0 {
-		return nil, nil, errors.New("data cannot be empty")
-	}
-
-	// Calculate sample size (simple chunking - can be enhanced with erasure coding)
-	sampleSize := uint64(1024) // 1KB samples
-	if uint64(len(data)) < sampleSize {
-		sampleSize = uint64(len(data))
-	}
-
-	// Split data into samples
-	numSamples := (uint64(len(data)) + sampleSize - 1) / sampleSize
-	samples := make([]*DataSample, 0, numSamples)
-	sampleHashes := make([][]byte, numSamples)
-
-	for i := uint64(0); i < numSamples; i++ {
-		start := i * sampleSize
-		end := start + sampleSize
-		if end > uint64(len(data)) {
-			end = uint64(len(data))
-		}
-
-		sampleData := data[start:end]
-		commitment := p.calculateSampleHash(sampleData, i)
-
-		sample := &DataSample{
-			BlockNumber: new(big.Int).Set(blockNumber),
-			DataHash:    dataHash,
-			SampleIndex: i,
-			SampleData:  sampleData,
-			Commitment:  commitment,
-		}
-
-		samples = append(samples, sample)
-		sampleHashes[i] = commitment[:]
-	}
-
-	// Build Merkle tree root from sample commitments
-	merkleRoot := p.buildMerkleRoot(sampleHashes)
-
-	commitment := &SampleCommitment{
-		BlockNumber: new(big.Int).Set(blockNumber),
-		DataHash:    dataHash,
-		MerkleRoot:  merkleRoot,
-		SampleCount: numSamples,
-	}
-
-	// Generate Merkle proofs for each sample (simplified - full implementation would use trie)
-	// TODO: Enhance with proper trie-based Merkle proof generation
-	for _, sample := range samples {
-		// Basic proof generation (can be enhanced)
-		sample.MerkleProof = p.generateSimpleProof(sampleHashes, sample.SampleIndex)
-	}
-
-	return samples, commitment, nil
-}
-
-// buildMerkleRoot builds a Merkle tree root from sample hashes
-func (p *PeerDAS) buildMerkleRoot(hashes [][]byte) common.Hash {
-	if len(hashes) == 0 {
-		return common.Hash{}
+		return nil, ErrPeerDASNotActive
 	}
-	if len(hashes) == 1 {
-		return common.BytesToHash(hashes[0])
+	if len(proofs) != kzg4844.CellsPerExtBlob {
+		return nil, fmt.Errorf("invalid number of %d cell proofs, want %d", len(proofs), kzg4844.CellsPerExtBlob)
 	}
-
-	// Simple binary Merkle tree construction
-	level := hashes
-	for len(level) > 1 {
-		nextLevel := make([][]byte, 0, (len(level)+1)/2)
-		for i := 0; i < len(level); i += 2 {
-			if i+1 < len(level) {
-				// Pair of nodes
-				combined := append(level[i], level[i+1]...)
-				hash := sha256.Sum256(combined)
-				nextLevel = append(nextLevel, hash[:])
-			} else {
-				// Odd node, hash with itself
-				combined := append(level[i], level[i]...)
-				hash := sha256.Sum256(combined)
-				nextLevel = append(nextLevel, hash[:])
-			}
-		}
-		level = nextLevel
-	}
-
-	return common.BytesToHash(level[0])
-}
-
-// generateSimpleProof generates a simple Merkle proof for a sample
-// This is a simplified implementation - full version would use trie-based proofs
-func (p *PeerDAS) generateSimpleProof(hashes [][]byte, index uint64) [][]byte {
-	if index >= uint64(len(hashes)) {
-		return nil
+	cells, err := kzg4844.ComputeCells(blob)
+	if err != nil {
+		return nil, err
 	}
-
-	// Simple proof: just return sibling hashes up the tree
-	proof := make([][]byte, 0)
-	currentIndex := index
-	currentLevel := hashes
-
-	for len(currentLevel) > 1 {
-		siblingIndex := currentIndex ^ 1 // XOR to get sibling
-		if siblingIndex < uint64(len(currentLevel)) {
-			proof = append(proof, currentLevel[siblingIndex])
+	dataHash := common.Hash(kzg4844.CalcBlobHashV1(sha256.New(), &commitment))
+
+	samples := make([]*DataSample, len(cells))
+	for i := range cells {
+		proof := proofs[i]
+		samples[i] = &DataSample{
+			BlockNumber:   new(big.Int).Set(blockNumber),
+			DataHash:      dataHash,
+			SampleIndex:   uint64(i),
+			SampleData:    cells[i][:],
+			Commitment:    p.calculateSampleHash(cells[i][:], uint64(i)),
+			KZGCommitment: &commitment,
+			KZGProof:      &proof,
 		}
-		currentIndex = currentIndex / 2
-		// Build next level
-		nextLevel := make([][]byte, 0, (len(currentLevel)+1)/2)
-		for i := 0; i < len(currentLevel); i += 2 {
-			if i+1 < len(currentLevel) {
-				combined := append(currentLevel[i], currentLevel[i+1]...)
-				hash := sha256.Sum256(combined)
-				nextLevel = append(nextLevel, hash[:])
-			} else {
-				combined := append(currentLevel[i], currentLevel[i]...)
-				hash := sha256.Sum256(combined)
-				nextLevel = append(nextLevel, hash[:])
-			}
-		}
-		currentLevel = nextLevel
 	}
-
-	return proof
+	return samples, nil
 }
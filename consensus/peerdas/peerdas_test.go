@@ -4,11 +4,22 @@
 package peerdas
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
 	"math/big"
+	"math/rand"
+	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 // Test configuration with FUSAKA active
@@ -123,37 +134,200 @@ func TestErasureDecode_AllDataShards(t *testing.T) {
 	}
 }
 
-func TestSampleData(t *testing.T) {
-	p := NewPeerDAS(testConfig())
+// Tests that data survives the loss of any ParityShards shards, dropping random
+// subsets of shards for a range of code parameters.
+func TestErasureDecode_RandomErasures(t *testing.T) {
+	configs := []*ErasureConfig{
+		DefaultErasureConfig(),
+		{DataShards: 1, ParityShards: 3, ShardSize: 16},
+		{DataShards: 5, ParityShards: 5, ShardSize: 33},
+		{DataShards: 10, ParityShards: 4, ShardSize: 64},
+		{DataShards: 64, ParityShards: 64, ShardSize: 8},
+		{DataShards: 200, ParityShards: 56, ShardSize: 4},
+	}
+	rng := rand.New(rand.NewSource(1))
+	for _, config := range configs {
+		ec := NewErasureCoding(config)
+		for round := 0; round < 25; round++ {
+			data := make([]byte, 1+rng.Intn(ec.DataShards*ec.ShardSize))
+			rng.Read(data)
+
+			encoded, err := ec.EncodeForBlock(data, big.NewInt(int64(round)))
+			if err != nil {
+				t.Fatalf("config %+v: encode failed: %v", config, err)
+			}
+			// Keep a random subset of DataShards to TotalShards shards, shuffled
+			perm := rng.Perm(ec.TotalShards())
+			keep := ec.DataShards + rng.Intn(ec.ParityShards+1)
+			available := make([]*EncodedShard, 0, keep)
+			for _, idx := range perm[:keep] {
+				available = append(available, encoded.Shards[idx])
+			}
+			if !ec.CanReconstruct(available) {
+				t.Fatalf("config %+v: reconstruction of %v deemed impossible", config, perm[:keep])
+			}
+			decoded, err := ec.Decode(available)
+			if err != nil {
+				t.Fatalf("config %+v: decode of %v failed: %v", config, perm[:keep], err)
+			}
+			if !bytes.Equal(decoded[:len(data)], data) {
+				t.Fatalf("config %+v: decode of %v mismatch", config, perm[:keep])
+			}
+			if trailing := decoded[len(data):]; !bytes.Equal(trailing, make([]byte, len(trailing))) {
+				t.Fatalf("config %+v: decode of %v has non-zero padding", config, perm[:keep])
+			}
+			decoded, err = ec.DecodeFromBlock(encoded, available)
+			if err != nil {
+				t.Fatalf("config %+v: block decode of %v failed: %v", config, perm[:keep], err)
+			}
+			if !bytes.Equal(decoded, data) {
+				t.Fatalf("config %+v: block decode of %v mismatch", config, perm[:keep])
+			}
+			// One shard short of DataShards must be refused
+			if _, err := ec.Decode(available[:ec.DataShards-1]); err != ErrInsufficientShards {
+				t.Fatalf("config %+v: decode of too few shards: have %v, want %v", config, err, ErrInsufficientShards)
+			}
+		}
+	}
+}
 
-	data := []byte("test data for sampling verification in PeerDAS")
-	blockNum := big.NewInt(100)
-	dataHash := common.BytesToHash([]byte("datahash"))
+// Tests that duplicated shards do not count towards the reconstruction threshold.
+func TestErasureDecode_DuplicateShards(t *testing.T) {
+	ec := NewErasureCoding(DefaultErasureConfig())
 
-	samples, commitment, err := p.SampleData(data, blockNum, dataHash)
+	shards, err := ec.Encode([]byte("duplicate shards"))
 	if err != nil {
-		t.Fatalf("SampleData failed: %v", err)
+		t.Fatalf("Encode failed: %v", err)
 	}
-
-	if commitment == nil {
-		t.Fatal("commitment is nil")
+	available := []*EncodedShard{shards[1], shards[1], shards[4], shards[5]}
+	if ec.CanReconstruct(available) {
+		t.Error("reconstruction from duplicated shards deemed possible")
+	}
+	if _, err := ec.Decode(available); err != ErrInsufficientShards {
+		t.Errorf("decode error mismatch: have %v, want %v", err, ErrInsufficientShards)
 	}
+}
+
+// Tests that data exceeding the minimum shard capacity is encoded into larger
+// shards, and that shards of mixed sizes are rejected.
+func TestErasureLargeData(t *testing.T) {
+	ec := NewErasureCoding(DefaultErasureConfig())
 
-	if len(samples) == 0 {
-		t.Fatal("no samples created")
+	data := make([]byte, 10*1024+1)
+	for i := range data {
+		data[i] = byte(i * 7)
 	}
+	shards, err := ec.Encode(data)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if size := len(shards[0].Data); size != 2561 {
+		t.Fatalf("shard size mismatch: have %d, want %d", size, 2561)
+	}
+	decoded, err := ec.Decode([]*EncodedShard{shards[0], shards[2], shards[4], shards[5]})
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !bytes.Equal(decoded[:len(data)], data) {
+		t.Fatal("decoded data mismatch")
+	}
+	small, err := ec.Encode([]byte("small data"))
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if _, err := ec.Decode([]*EncodedShard{shards[0], shards[1], shards[2], small[3]}); err != ErrShardSizeMismatch {
+		t.Errorf("decode error mismatch: have %v, want %v", err, ErrShardSizeMismatch)
+	}
+}
 
-	if commitment.SampleCount != uint64(len(samples)) {
-		t.Errorf("sample count mismatch: commitment says %d, got %d samples",
-			commitment.SampleCount, len(samples))
+// Tests that codes exceeding the size of the field are rejected.
+func TestErasureInvalidConfig(t *testing.T) {
+	ec := NewErasureCoding(&ErasureConfig{DataShards: 200, ParityShards: 57, ShardSize: 4})
+	if _, err := ec.Encode([]byte{1}); err != ErrInvalidShardCount {
+		t.Errorf("encode error mismatch: have %v, want %v", err, ErrInvalidShardCount)
 	}
+}
 
-	// Verify each sample
-	for _, sample := range samples {
-		err := p.VerifySample(sample, commitment)
-		if err != nil {
-			t.Errorf("VerifySample failed for sample %d: %v", sample.SampleIndex, err)
+var (
+	testBlobOnce   sync.Once
+	testBlob       *kzg4844.Blob
+	testCommitment kzg4844.Commitment
+	testCellProofs []kzg4844.Proof
+)
+
+// testBlobSidecar returns a blob along with its commitment and cell proofs. The blob is constant to keep computing
+// the proofs cheap.
+func testBlobSidecar(t testing.TB) (*kzg4844.Blob, kzg4844.Commitment, []kzg4844.Proof) {
+	testBlobOnce.Do(func() {
+		testBlob = new(kzg4844.Blob)
+		for i := 0; i < kzg4844.FieldElementsPerBlob; i++ {
+			testBlob[(i+1)*kzg4844.BytesPerFieldElement-1] = 0x42
 		}
+		testCommitment, _ = kzg4844.BlobToCommitment(testBlob)
+		testCellProofs, _ = kzg4844.ComputeCellProofs(testBlob)
+	})
+	if len(testCellProofs) != kzg4844.CellsPerExtBlob {
+		t.Fatal("failed to compute cell proofs")
+	}
+	return testBlob, testCommitment, testCellProofs
+}
+
+// Tests that blobs are sampled into cells verifying against the blob commitment,
+// and that invalid cells are singled out.
+func TestSampleBlob(t *testing.T) {
+	p := NewPeerDAS(testConfig())
+	blob, commitment, proofs := testBlobSidecar(t)
+
+	samples, err := p.SampleBlob(big.NewInt(100), blob, commitment, proofs)
+	if err != nil {
+		t.Fatalf("SampleBlob failed: %v", err)
+	}
+	if len(samples) != kzg4844.CellsPerExtBlob {
+		t.Fatalf("sample count mismatch: have %d, want %d", len(samples), kzg4844.CellsPerExtBlob)
+	}
+	if want := common.Hash(kzg4844.CalcBlobHashV1(sha256.New(), &commitment)); samples[0].DataHash != want {
+		t.Fatalf("samples keyed by %x, want versioned hash %x", samples[0].DataHash, want)
+	}
+	if err := p.VerifySample(samples[kzg4844.CellsPerExtBlob-1]); err != nil {
+		t.Fatalf("VerifySample failed: %v", err)
+	}
+	if valid := p.FilterValidSamples(samples); len(valid) != len(samples) {
+		t.Fatalf("valid sample count mismatch: have %d, want %d", len(valid), len(samples))
+	}
+	// The KZG fields must survive the wire encoding
+	enc, err := rlp.EncodeToBytes(samples[1])
+	if err != nil {
+		t.Fatalf("failed to encode sample: %v", err)
+	}
+	var dec DataSample
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("failed to decode sample: %v", err)
+	}
+	if dec.KZGCommitment == nil || *dec.KZGCommitment != commitment || dec.KZGProof == nil || *dec.KZGProof != proofs[1] {
+		t.Fatal("KZG fields lost in encoding")
+	}
+	// Forge a cell, keeping its hash commitment consistent, and claim a cell of
+	// a different blob
+	forged := *samples[2]
+	forged.SampleData = common.CopyBytes(forged.SampleData)
+	forged.SampleData[0] ^= 0x01
+	forged.Commitment = p.calculateSampleHash(forged.SampleData, forged.SampleIndex)
+
+	foreign := *samples[3]
+	foreign.DataHash = common.Hash{0x01}
+
+	if err := p.VerifySample(&forged); !errors.Is(err, ErrSampleVerificationFailed) {
+		t.Fatalf("forged sample verification mismatch: have %v, want %v", err, ErrSampleVerificationFailed)
+	}
+	if err := p.VerifySample(&foreign); err != ErrInvalidSample {
+		t.Fatalf("foreign sample verification mismatch: have %v, want %v", err, ErrInvalidSample)
+	}
+	valid := p.FilterValidSamples([]*DataSample{samples[0], &forged, samples[4], &foreign})
+	if len(valid) != 2 || valid[0] != samples[0] || valid[1] != samples[4] {
+		t.Fatalf("invalid samples not filtered: %v", valid)
+	}
+	if _, err := p.SampleBlob(big.NewInt(100), blob, commitment, proofs[1:]); err == nil {
+		t.Fatal("blob sampled with missing cell proofs")
 	}
 }
 
@@ -194,6 +368,92 @@ func TestSampleCache(t *testing.T) {
 	}
 }
 
+// Tests that the custody assignment is deterministic, distinct and bounded.
+func TestCustodyColumns(t *testing.T) {
+	id := enode.ID{0x01, 0x02, 0x03}
+
+	columns := CustodyColumns(id, CustodyRequirement)
+	if len(columns) != CustodyRequirement {
+		t.Fatalf("custody count mismatch: have %d, want %d", len(columns), CustodyRequirement)
+	}
+	for i, column := range columns {
+		if column >= NumberOfColumns {
+			t.Errorf("column %d out of range: %d", i, column)
+		}
+		if i > 0 && columns[i-1] >= column {
+			t.Errorf("columns not strictly ascending: %v", columns)
+		}
+	}
+	if again := CustodyColumns(id, CustodyRequirement); !reflect.DeepEqual(again, columns) {
+		t.Errorf("custody assignment not deterministic: have %v, want %v", again, columns)
+	}
+	if other := CustodyColumns(enode.ID{0x04}, CustodyRequirement); reflect.DeepEqual(other, columns) {
+		t.Errorf("distinct nodes assigned the same columns: %v", columns)
+	}
+	if all := CustodyColumns(id, 2*NumberOfColumns); len(all) != NumberOfColumns {
+		t.Errorf("full custody count mismatch: have %d, want %d", len(all), NumberOfColumns)
+	}
+}
+
+// Tests that custodied samples are persisted, served after a restart and pruned
+// once out of the retention window.
+func TestSampleStore(t *testing.T) {
+	var (
+		p       = NewPeerDAS(testConfig())
+		db      = rawdb.NewMemoryDatabase()
+		id      = enode.ID{0x01}
+		custody = CustodyColumns(id, CustodyRequirement)
+		hash    = common.Hash{0x02}
+	)
+	var other uint64 // Column not custodied by the node
+	for _, column := range custody {
+		if column == other {
+			other++
+		}
+	}
+	newSample := func(number int64, index uint64) *DataSample {
+		data := []byte{byte(number), byte(index)}
+		return &DataSample{
+			BlockNumber: big.NewInt(number),
+			DataHash:    hash,
+			SampleIndex: index,
+			SampleData:  data,
+			Commitment:  p.calculateSampleHash(data, index),
+		}
+	}
+	proto := NewProtocol(p)
+	proto.SetStore(NewSampleStore(db, id, CustodyRequirement, 16))
+	proto.StoreSamples([]*DataSample{newSample(10, custody[0]), newSample(10, other)})
+
+	// Restart with an empty cache, only the custodied sample is served
+	var responses []*SampleResponse
+	proto = NewProtocol(p)
+	proto.SetStore(NewSampleStore(db, id, CustodyRequirement, 16))
+	proto.SetNetworkCallbacks(func(peerID string, msgCode uint8, data interface{}) error {
+		responses = append(responses, data.(*SampleResponse))
+		return nil
+	}, nil)
+
+	request := &SampleRequest{RequestID: 1, BlockNumber: big.NewInt(10), BlockHash: hash, Indices: []uint64{custody[0], other}}
+	if err := proto.HandleSampleRequest("peer", request); err != nil {
+		t.Fatalf("failed to handle sample request: %v", err)
+	}
+	if len(responses) != 1 || len(responses[0].Samples) != 1 {
+		t.Fatalf("response mismatch: have %v", responses)
+	}
+	if sample := responses[0].Samples[0]; sample.SampleIndex != custody[0] || p.VerifySample(sample) != nil {
+		t.Fatalf("served sample mismatch: index %d", sample.SampleIndex)
+	}
+	// Moving past the retention window prunes the samples of old blocks
+	proto.StoreSamples([]*DataSample{newSample(27, custody[0])})
+	if blob := rawdb.ReadDASSample(db, 10, hash, custody[0]); len(blob) != 0 {
+		t.Errorf("expired sample still stored: %x", blob)
+	}
+	if _, ok := proto.store.Get(27, hash, custody[0]); !ok {
+		t.Errorf("recent sample missing from the store")
+	}
+}
+
 func TestDACommitmentValidation(t *testing.T) {
 	p := NewPeerDAS(testConfig())
 	proto := NewProtocol(p)
@@ -267,6 +527,123 @@ func TestCreateAndValidateCommitment(t *testing.T) {
 	}
 }
 
+// Tests that blocks are only accepted by the DA validator if their commitment is
+// known and their samples can be obtained.
+func TestVerifyBlock(t *testing.T) {
+	p := NewPeerDAS(testConfig())
+	v := NewDAValidator(p, NewProtocol(p))
+
+	var (
+		txs    = types.Transactions{types.NewTransaction(0, common.Address{0x01}, big.NewInt(1), 21000, big.NewInt(1), nil)}
+		header = &types.Header{Number: big.NewInt(10)}
+	)
+	if err := v.VerifyBlock(types.NewBlockWithHeader(header).WithBody(txs, nil)); err != ErrMissingCommitment {
+		t.Fatalf("verification error mismatch: have %v, want %v", err, ErrMissingCommitment)
+	}
+	header.DACommitment = &common.Hash{0x01}
+	if err := v.VerifyBlock(types.NewBlockWithHeader(header).WithBody(txs, nil)); err != ErrCommitmentMismatch {
+		t.Fatalf("verification error mismatch: have %v, want %v", err, ErrCommitmentMismatch)
+	}
+	commitment, encoded, err := v.CommitBlock(header.Number, txs)
+	if err != nil {
+		t.Fatalf("failed to create commitment: %v", err)
+	}
+	hash := HashDACommitment(commitment)
+	header.DACommitment = &hash
+
+	// Without any peer nor local samples, the data is unavailable
+	block := types.NewBlockWithHeader(header).WithBody(txs, nil)
+	if err := v.VerifyBlock(block); err != ErrNoPeers {
+		t.Fatalf("verification error mismatch: have %v, want %v", err, ErrNoPeers)
+	}
+	v.StoreBlockSamples(encoded)
+	if err := v.VerifyBlock(block); err != nil {
+		t.Fatalf("failed to verify block with available data: %v", err)
+	}
+}
+
+// Tests that block data samples are only accepted with a Merkle branch to the
+// data root of the block they are sampled for.
+func TestSampleProof(t *testing.T) {
+	for count := 1; count <= 9; count++ {
+		leaves := make([]common.Hash, count)
+		hashes := make([][]byte, count)
+		for i := range leaves {
+			leaves[i] = common.Hash{byte(count), byte(i)}
+			hashes[i] = common.CopyBytes(leaves[i][:])
+		}
+		root := buildMerkleRoot(hashes)
+		for i := range leaves {
+			proof := merkleProof(leaves, i)
+			if !verifyMerkleProof(root, leaves[i], uint64(i), uint64(count), proof) {
+				t.Errorf("count %d, leaf %d: valid proof rejected", count, i)
+			}
+			if verifyMerkleProof(root, leaves[(i+1)%count], uint64(i), uint64(count), proof) && count > 1 {
+				t.Errorf("count %d, leaf %d: proof accepted for another leaf", count, i)
+			}
+			if len(proof) > 0 && verifyMerkleProof(root, leaves[i], uint64(i), uint64(count), proof[:len(proof)-1]) {
+				t.Errorf("count %d, leaf %d: truncated proof accepted", count, i)
+			}
+		}
+		if verifyMerkleProof(root, leaves[0], uint64(count), uint64(count), merkleProof(leaves, 0)) {
+			t.Errorf("count %d: out of range index accepted", count)
+		}
+	}
+	// Samples of other data keyed by the same hash are refused
+	p := NewPeerDAS(testConfig())
+	proto := NewProtocol(p)
+	v := NewDAValidator(p, proto)
+
+	commitment, encoded, err := v.CreateCommitment(big.NewInt(100), common.Hash{}, []byte("committed block data"))
+	if err != nil {
+		t.Fatalf("failed to create commitment: %v", err)
+	}
+	_, forged, err := v.CreateCommitment(big.NewInt(100), common.Hash{}, []byte("different block data"))
+	if err != nil {
+		t.Fatalf("failed to create commitment: %v", err)
+	}
+	forged.DataHash = encoded.DataHash
+	v.StoreBlockSamples(forged)
+
+	if _, err := v.ValidateDataAvailability(commitment); err != ErrDataUnavailable {
+		t.Fatalf("validation error mismatch: have %v, want %v", err, ErrDataUnavailable)
+	}
+	for _, sample := range proto.GetCachedSamples(encoded.DataHash) {
+		if err := v.VerifySampleProof(sample, commitment); err != ErrCommitmentMismatch {
+			t.Errorf("sample %d: verification error mismatch: have %v, want %v", sample.SampleIndex, err, ErrCommitmentMismatch)
+		}
+	}
+	v.StoreBlockSamples(encoded)
+	if _, err := v.ValidateDataAvailability(commitment); err != nil {
+		t.Fatalf("failed to validate available data: %v", err)
+	}
+}
+
+// Tests that the blobs of included sidecars are sampled into cells, and that
+// sidecars without cell proofs are refused.
+func TestStoreSidecarSamples(t *testing.T) {
+	p := NewPeerDAS(testConfig())
+	proto := NewProtocol(p)
+	v := NewDAValidator(p, proto)
+
+	blob, commitment, proofs := testBlobSidecar(t)
+	sidecar := &types.BlobTxSidecar{
+		Blobs:       []kzg4844.Blob{*blob},
+		Commitments: []kzg4844.Commitment{commitment},
+		Proofs:      []kzg4844.Proof{{}},
+	}
+	if err := v.StoreSidecarSamples(big.NewInt(100), sidecar); err != ErrMissingCellProofs {
+		t.Fatalf("sampling error mismatch: have %v, want %v", err, ErrMissingCellProofs)
+	}
+	sidecar.Version, sidecar.Proofs = types.BlobSidecarVersion1, proofs
+	if err := v.StoreSidecarSamples(big.NewInt(100), sidecar); err != nil {
+		t.Fatalf("failed to sample sidecar: %v", err)
+	}
+	if cached := proto.GetCachedSamples(sidecar.BlobHashes()[0]); len(cached) != kzg4844.CellsPerExtBlob {
+		t.Fatalf("cached sample count mismatch: have %d, want %d", len(cached), kzg4844.CellsPerExtBlob)
+	}
+}
+
 func TestHashDACommitment(t *testing.T) {
 	commitment := &DACommitment{
 		BlockNumber:    big.NewInt(100),
@@ -328,17 +705,62 @@ func BenchmarkErasureDecode(b *testing.B) {
 
 func BenchmarkSampleVerification(b *testing.B) {
 	p := NewPeerDAS(testConfig())
-	data := make([]byte, 4096)
-	for i := range data {
-		data[i] = byte(i % 256)
-	}
-
-	samples, commitment, _ := p.SampleData(data, big.NewInt(100), common.BytesToHash(data))
+	blob, commitment, proofs := testBlobSidecar(b)
+	samples, _ := p.SampleBlob(big.NewInt(100), blob, commitment, proofs)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		for _, sample := range samples {
-			p.VerifySample(sample, commitment)
+		p.FilterValidSamples(samples)
+	}
+}
+
+// Tests that samples sent to version 1 peers are encoded without the KZG
+// fields and decode back from it.
+func TestProtocolV1Encoding(t *testing.T) {
+	sample := &DataSample{
+		BlockNumber:   big.NewInt(10),
+		DataHash:      common.Hash{0x01},
+		SampleIndex:   3,
+		SampleData:    []byte{0xde, 0xad},
+		Commitment:    common.Hash{0x02},
+		KZGCommitment: &kzg4844.Commitment{0x03},
+		KZGProof:      &kzg4844.Proof{0x04},
+	}
+	want := &DataSample{
+		BlockNumber: sample.BlockNumber,
+		DataHash:    sample.DataHash,
+		SampleIndex: sample.SampleIndex,
+		SampleData:  sample.SampleData,
+		Commitment:  sample.Commitment,
+	}
+	messages := []struct {
+		code uint8
+		msg  interface{}
+		want interface{}
+	}{
+		{SampleResponseMsg, &SampleResponse{RequestID: 7, Samples: []*DataSample{sample}}, &SampleResponse{RequestID: 7, Samples: []*DataSample{want}}},
+		{SamplePushMsg, &SamplePush{BlockNumber: big.NewInt(10), Samples: []*DataSample{sample}}, &SamplePush{BlockNumber: big.NewInt(10), Samples: []*DataSample{want}}},
+		{SampleRequestMsg, &SampleRequest{RequestID: 7, BlockNumber: big.NewInt(10), Indices: []uint64{1}}, &SampleRequest{RequestID: 7, BlockNumber: big.NewInt(10), Indices: []uint64{1}}},
+	}
+	for i, tt := range messages {
+		blob, err := rlp.EncodeToBytes(toV1(tt.msg))
+		if err != nil {
+			t.Fatalf("message %d: failed to encode: %v", i, err)
+		}
+		have, err := decodeMessageV1(tt.code, blob)
+		if err != nil {
+			t.Fatalf("message %d: failed to decode: %v", i, err)
+		}
+		if !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("message %d: mismatch: have %+v, want %+v", i, have, tt.want)
 		}
 	}
+	// Version 2 messages are not understood as version 1 ones
+	blob, _ := rlp.EncodeToBytes(&SamplePush{BlockNumber: big.NewInt(10), Samples: []*DataSample{sample}})
+	if _, err := decodeMessageV1(SamplePushMsg, blob); err == nil {
+		t.Error("decoded version 2 push as version 1")
+	}
+	if protos := MakeProtocols(NewProtocol(NewPeerDAS(testConfig()))); len(protos) != 2 || protos[0].Version != PEERDAS2 || protos[1].Version != PEERDAS1 {
+		t.Errorf("protocol versions mismatch: %v", protos)
+	}
 }
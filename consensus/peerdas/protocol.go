@@ -21,8 +21,12 @@ const (
 	// Protocol name for PeerDAS
 	ProtocolName = "peerdas"
 
-	// Protocol version
-	ProtocolVersion = 1
+	// Protocol versions
+	PEERDAS1 = 1
+	PEERDAS2 = 2
+
+	// ProtocolVersion is the primary version of the protocol
+	ProtocolVersion = PEERDAS2
 
 	// Message codes
 	SampleRequestMsg  = 0x00
@@ -35,18 +39,24 @@ const (
 	MaxSampleRetries     = 3
 
 	// Limits
-	MaxSamplesPerRequest  = 16
-	MaxPendingRequests    = 64
-	MaxSampleCacheSize    = 1024
-	MaxSampleAge          = 1 * time.Hour
+	MaxSamplesPerRequest = 16
+	MaxPendingRequests   = 64
+	MaxSampleCacheSize   = 1024
+	MaxSampleAge         = 1 * time.Hour
 )
 
+// ProtocolVersions are the supported versions of the `peerdas` protocol (first
+// is primary). Version 1 predates KZG cell proofs, its samples carry no KZG
+// commitment and proof.
+var ProtocolVersions = []uint{PEERDAS2, PEERDAS1}
+
 var (
-	ErrRequestTimeout    = errors.New("sample request timed out")
-	ErrPeerDisconnected  = errors.New("peer disconnected")
-	ErrSampleNotFound    = errors.New("sample not found")
-	ErrTooManyRequests   = errors.New("too many pending requests")
-	ErrInvalidMessage    = errors.New("invalid protocol message")
+	ErrRequestTimeout   = errors.New("sample request timed out")
+	ErrPeerDisconnected = errors.New("peer disconnected")
+	ErrSampleNotFound   = errors.New("sample not found")
+	ErrTooManyRequests  = errors.New("too many pending requests")
+	ErrInvalidMessage   = errors.New("invalid protocol message")
+	ErrNoPeers          = errors.New("no peers to request samples from")
 )
 
 // SampleRequest represents a request for data availability samples
@@ -86,6 +96,8 @@ type PendingRequest struct {
 	Retries   int
 	Response  chan *SampleResponse
 	PeerID    string
+
+	dropped chan struct{} // Closed if the peer disconnects before responding
 }
 
 // SampleCache caches samples for quick retrieval
@@ -98,8 +110,8 @@ type SampleCache struct {
 
 // CachedSample represents a cached data sample
 type CachedSample struct {
-	Sample    *DataSample
-	CachedAt  time.Time
+	Sample      *DataSample
+	CachedAt    time.Time
 	AccessCount int
 }
 
@@ -218,10 +230,15 @@ func (c *SampleCache) evictOldest() {
 type Protocol struct {
 	peerdas         *PeerDAS
 	cache           *SampleCache
-	pendingRequests sync.Map // requestID -> *PendingRequest
+	store           *SampleStore // Persistent store of custodied samples, optional
+	pendingRequests sync.Map     // requestID -> *PendingRequest
+	pendingCount    int
 	nextRequestID   uint64
 	mu              sync.Mutex
 
+	peers     map[string]*peer // Connected `peerdas` peers
+	peersLock sync.RWMutex
+
 	// Callbacks for network integration
 	sendMessage func(peerID string, msgCode uint8, data interface{}) error
 	getPeers    func() []string
@@ -233,6 +250,7 @@ func NewProtocol(p *PeerDAS) *Protocol {
 		peerdas:       p,
 		cache:         NewSampleCache(),
 		nextRequestID: 1,
+		peers:         make(map[string]*peer),
 	}
 }
 
@@ -245,6 +263,34 @@ func (p *Protocol) SetNetworkCallbacks(
 	p.getPeers = getPeers
 }
 
+// SetStore sets the persistent store custodied samples are kept in and served
+// from, in addition to the in-memory cache.
+func (p *Protocol) SetStore(store *SampleStore) {
+	p.store = store
+}
+
+// lookup retrieves a sample from the cache, or from the persistent store if it
+// is not cached anymore.
+func (p *Protocol) lookup(blockNumber *big.Int, blockHash common.Hash, index uint64) (*DataSample, bool) {
+	if sample, ok := p.cache.Get(blockHash, index); ok {
+		return sample, true
+	}
+	if p.store == nil || blockNumber == nil || !blockNumber.IsUint64() {
+		return nil, false
+	}
+	return p.store.Get(blockNumber.Uint64(), blockHash, index)
+}
+
+// keep caches verified samples, persisting the ones of custodied columns.
+func (p *Protocol) keep(samples []*DataSample) {
+	for _, sample := range samples {
+		p.cache.Put(sample)
+	}
+	if p.store != nil {
+		p.store.Put(samples)
+	}
+}
+
 // RequestSamples requests specific samples from the network
 func (p *Protocol) RequestSamples(blockNumber *big.Int, blockHash common.Hash, indices []uint64) ([]*DataSample, error) {
 	if len(indices) == 0 {
@@ -254,25 +300,88 @@ func (p *Protocol) RequestSamples(blockNumber *big.Int, blockHash common.Hash, i
 		return nil, errors.New("too many samples requested")
 	}
 
-	// Check cache first
+	// Check local samples first
 	samples := make([]*DataSample, 0, len(indices))
 	missingIndices := make([]uint64, 0)
 
 	for _, idx := range indices {
-		if sample, ok := p.cache.Get(blockHash, idx); ok {
+		if sample, ok := p.lookup(blockNumber, blockHash, idx); ok {
 			samples = append(samples, sample)
 		} else {
 			missingIndices = append(missingIndices, idx)
 		}
 	}
 
-	// If all found in cache, return
+	// If all found locally, return
 	if len(missingIndices) == 0 {
 		return samples, nil
 	}
 
-	// Create request
+	// Ask the connected peers in turn until all samples arrived, accepting
+	// partial responses along the way
+	if p.sendMessage == nil || p.getPeers == nil {
+		return nil, ErrNoPeers
+	}
+	peers := p.getPeers()
+	if len(peers) == 0 {
+		return nil, ErrNoPeers
+	}
+	var lastErr error
+	for attempt := 0; attempt < MaxSampleRetries && attempt < len(peers) && len(missingIndices) > 0; attempt++ {
+		response, err := p.requestFrom(peers[attempt], blockNumber, blockHash, missingIndices)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		wanted := make(map[uint64]bool, len(missingIndices))
+		for _, idx := range missingIndices {
+			wanted[idx] = true
+		}
+		// Only keep valid samples of the block that were asked for
+		requested := make([]*DataSample, 0, len(response.Samples))
+		for _, sample := range response.Samples {
+			if sample != nil && wanted[sample.SampleIndex] && sample.DataHash == blockHash {
+				requested = append(requested, sample)
+			}
+		}
+		fetched := make([]*DataSample, 0, len(requested))
+		for _, sample := range p.peerdas.FilterValidSamples(requested) {
+			if !wanted[sample.SampleIndex] {
+				continue // Duplicate in the response
+			}
+			delete(wanted, sample.SampleIndex)
+			fetched = append(fetched, sample)
+		}
+		p.keep(fetched)
+		samples = append(samples, fetched...)
+		missingIndices = missingIndices[:0]
+		for _, idx := range indices {
+			if wanted[idx] {
+				missingIndices = append(missingIndices, idx)
+			}
+		}
+		if len(missingIndices) > 0 && response.Error != "" {
+			lastErr = errors.New(response.Error)
+		}
+	}
+	if len(missingIndices) > 0 {
+		if lastErr == nil {
+			lastErr = ErrSampleNotFound
+		}
+		return nil, lastErr
+	}
+	return samples, nil
+}
+
+// requestFrom sends a sample request to a single peer and waits for the
+// matching response, for at most SampleRequestTimeout.
+func (p *Protocol) requestFrom(peerID string, blockNumber *big.Int, blockHash common.Hash, indices []uint64) (*SampleResponse, error) {
 	p.mu.Lock()
+	if p.pendingCount >= MaxPendingRequests {
+		p.mu.Unlock()
+		return nil, ErrTooManyRequests
+	}
+	p.pendingCount++
 	requestID := p.nextRequestID
 	p.nextRequestID++
 	p.mu.Unlock()
@@ -281,59 +390,62 @@ func (p *Protocol) RequestSamples(blockNumber *big.Int, blockHash common.Hash, i
 		RequestID:   requestID,
 		BlockNumber: blockNumber,
 		BlockHash:   blockHash,
-		Indices:     missingIndices,
+		Indices:     append([]uint64(nil), indices...),
 	}
-
-	// Create pending request with response channel
-	responseChan := make(chan *SampleResponse, 1)
 	pending := &PendingRequest{
 		Request:   request,
 		Timestamp: time.Now(),
-		Retries:   0,
-		Response:  responseChan,
+		Response:  make(chan *SampleResponse, 1),
+		PeerID:    peerID,
+		dropped:   make(chan struct{}),
 	}
-
 	p.pendingRequests.Store(requestID, pending)
-	defer p.pendingRequests.Delete(requestID)
-
-	// Send to available peers
-	if p.sendMessage != nil && p.getPeers != nil {
-		peers := p.getPeers()
-		if len(peers) > 0 {
-			// Send to first available peer (could be enhanced to try multiple)
-			peer := peers[0]
-			pending.PeerID = peer
-			if err := p.sendMessage(peer, SampleRequestMsg, request); err != nil {
-				return nil, err
-			}
-		}
+	defer func() {
+		p.pendingRequests.Delete(requestID)
+
+		p.mu.Lock()
+		p.pendingCount--
+		p.mu.Unlock()
+	}()
+
+	if err := p.sendMessage(peerID, SampleRequestMsg, request); err != nil {
+		return nil, err
 	}
+	timeout := time.NewTimer(SampleRequestTimeout)
+	defer timeout.Stop()
 
-	// Wait for response with timeout
 	select {
-	case response := <-responseChan:
-		if response.Error != "" {
-			return nil, errors.New(response.Error)
-		}
-		// Cache received samples
-		for _, sample := range response.Samples {
-			p.cache.Put(sample)
-			samples = append(samples, sample)
-		}
-		return samples, nil
-
-	case <-time.After(SampleRequestTimeout):
+	case response := <-pending.Response:
+		return response, nil
+	case <-pending.dropped:
+		return nil, ErrPeerDisconnected
+	case <-timeout.C:
 		return nil, ErrRequestTimeout
 	}
 }
 
-// HandleSampleRequest processes an incoming sample request
+// dropRequests fails all requests pending on a disconnected peer.
+func (p *Protocol) dropRequests(peerID string) {
+	p.pendingRequests.Range(func(key, value interface{}) bool {
+		if pr := value.(*PendingRequest); pr.PeerID == peerID && pr.dropped != nil {
+			close(pr.dropped)
+			p.pendingRequests.Delete(key)
+		}
+		return true
+	})
+}
+
+// HandleSampleRequest processes an incoming sample request, serving the samples
+// from the cache or the persistent store.
 func (p *Protocol) HandleSampleRequest(peerID string, request *SampleRequest) error {
+	if len(request.Indices) > MaxSamplesPerRequest {
+		return ErrTooManyRequests
+	}
 	samples := make([]*DataSample, 0, len(request.Indices))
 	var errMsg string
 
 	for _, idx := range request.Indices {
-		if sample, ok := p.cache.Get(request.BlockHash, idx); ok {
+		if sample, ok := p.lookup(request.BlockNumber, request.BlockHash, idx); ok {
 			samples = append(samples, sample)
 		}
 	}
@@ -359,6 +471,9 @@ func (p *Protocol) HandleSampleRequest(peerID string, request *SampleRequest) er
 func (p *Protocol) HandleSampleResponse(peerID string, response *SampleResponse) error {
 	if pending, ok := p.pendingRequests.Load(response.RequestID); ok {
 		pr := pending.(*PendingRequest)
+		if pr.PeerID != "" && pr.PeerID != peerID {
+			return nil // Response to a request sent elsewhere, ignore
+		}
 		select {
 		case pr.Response <- response:
 		default:
@@ -378,12 +493,8 @@ func (p *Protocol) HandleSampleAnnouncement(peerID string, announcement *SampleA
 
 // HandleSamplePush processes pushed samples from a peer
 func (p *Protocol) HandleSamplePush(peerID string, push *SamplePush) error {
-	// Cache the pushed samples
-	for _, sample := range push.Samples {
-		if err := p.peerdas.VerifySample(sample, nil); err == nil {
-			p.cache.Put(sample)
-		}
-	}
+	// Keep the valid pushed samples
+	p.keep(p.peerdas.FilterValidSamples(push.Samples))
 	return nil
 }
 
@@ -423,11 +534,10 @@ func (p *Protocol) PushSamples(peerID string, samples []*DataSample) error {
 	return p.sendMessage(peerID, SamplePushMsg, push)
 }
 
-// StoreSamples stores samples in the local cache
+// StoreSamples stores samples in the local cache, persisting the ones of
+// custodied columns
 func (p *Protocol) StoreSamples(samples []*DataSample) {
-	for _, sample := range samples {
-		p.cache.Put(sample)
-	}
+	p.keep(samples)
 }
 
 // GetCachedSamples returns cached samples for a block
@@ -475,3 +585,113 @@ func DecodeMessage(msgCode uint8, data []byte) (interface{}, error) {
 		return nil, ErrInvalidMessage
 	}
 }
+
+// dataSampleV1 is the encoding of a DataSample in version 1 of the protocol.
+type dataSampleV1 struct {
+	BlockNumber *big.Int
+	DataHash    common.Hash
+	SampleIndex uint64
+	SampleData  []byte
+	MerkleProof [][]byte // Merkle branch of Commitment to the data root
+	Commitment  common.Hash
+}
+
+// sampleResponseV1 is the encoding of a SampleResponse in version 1.
+type sampleResponseV1 struct {
+	RequestID uint64
+	Samples   []*dataSampleV1
+	Error     string
+}
+
+// samplePushV1 is the encoding of a SamplePush in version 1.
+type samplePushV1 struct {
+	BlockNumber *big.Int
+	BlockHash   common.Hash
+	Samples     []*dataSampleV1
+}
+
+// samplesToV1 converts samples to their version 1 encoding, dropping the KZG
+// commitment and proof of cells.
+func samplesToV1(samples []*DataSample) []*dataSampleV1 {
+	converted := make([]*dataSampleV1, 0, len(samples))
+	for _, s := range samples {
+		if s == nil {
+			continue
+		}
+		proof := make([][]byte, len(s.Proof))
+		for i := range s.Proof {
+			proof[i] = s.Proof[i].Bytes()
+		}
+		converted = append(converted, &dataSampleV1{
+			BlockNumber: s.BlockNumber,
+			DataHash:    s.DataHash,
+			SampleIndex: s.SampleIndex,
+			SampleData:  s.SampleData,
+			MerkleProof: proof,
+			Commitment:  s.Commitment,
+		})
+	}
+	return converted
+}
+
+// samplesFromV1 converts samples from their version 1 encoding.
+func samplesFromV1(samples []*dataSampleV1) []*DataSample {
+	converted := make([]*DataSample, 0, len(samples))
+	for _, s := range samples {
+		if s == nil {
+			continue
+		}
+		var proof []common.Hash
+		for _, node := range s.MerkleProof {
+			if len(node) != common.HashLength {
+				proof = nil // Not a branch to the data root, unprovable
+				break
+			}
+			proof = append(proof, common.BytesToHash(node))
+		}
+		converted = append(converted, &DataSample{
+			BlockNumber: s.BlockNumber,
+			DataHash:    s.DataHash,
+			SampleIndex: s.SampleIndex,
+			SampleData:  s.SampleData,
+			Commitment:  s.Commitment,
+			Proof:       proof,
+		})
+	}
+	return converted
+}
+
+// toV1 converts a protocol message to its version 1 encoding. Messages not
+// carrying samples are encoded the same in both versions.
+func toV1(data interface{}) interface{} {
+	switch msg := data.(type) {
+	case *SampleResponse:
+		return &sampleResponseV1{RequestID: msg.RequestID, Samples: samplesToV1(msg.Samples), Error: msg.Error}
+	case *SamplePush:
+		return &samplePushV1{BlockNumber: msg.BlockNumber, BlockHash: msg.BlockHash, Samples: samplesToV1(msg.Samples)}
+	default:
+		return data
+	}
+}
+
+// decodeMessageV1 decodes a protocol message received from a version 1 peer.
+func decodeMessageV1(msgCode uint8, data []byte) (interface{}, error) {
+	switch msgCode {
+	case SampleResponseMsg:
+		var resp sampleResponseV1
+		if err := rlp.DecodeBytes(data, &resp); err != nil {
+			return nil, err
+		}
+		return &SampleResponse{RequestID: resp.RequestID, Samples: samplesFromV1(resp.Samples), Error: resp.Error}, nil
+
+	case SamplePushMsg:
+		var push samplePushV1
+		if err := rlp.DecodeBytes(data, &push); err != nil {
+			return nil, err
+		}
+		return &SamplePush{BlockNumber: push.BlockNumber, BlockHash: push.BlockHash, Samples: samplesFromV1(push.Samples)}, nil
+
+	default:
+		return DecodeMessage(msgCode, data)
+	}
+}
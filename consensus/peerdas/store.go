@@ -0,0 +1,197 @@
+// Copyright 2025 The Altcoinchain Authors
+// This file implements the persistent custody store for EIP-7594 (PeerDAS)
+//
+// Samples are split into columns, and every node keeps the columns assigned to
+// it by its node ID on disk for a retention window, so it can keep serving them
+// to the network across restarts.
+
+package peerdas
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// NumberOfColumns is the number of columns samples are custodied by, one per
+	// cell of an extended blob.
+	NumberOfColumns = 128
+
+	// CustodyRequirement is the minimum number of columns a node custodies.
+	CustodyRequirement = 4
+
+	// SampleRetention is the number of blocks custodied samples are kept for.
+	SampleRetention = 4096
+)
+
+// CustodyColumns returns the columns assigned to the node with the given ID, in
+// ascending order. The assignment follows get_custody_groups of EIP-7594: the
+// node ID is hashed with an increasing counter until enough distinct columns are
+// drawn.
+func CustodyColumns(nodeID enode.ID, count uint64) []uint64 {
+	if count > NumberOfColumns {
+		count = NumberOfColumns
+	}
+	var (
+		columns = make([]uint64, 0, count)
+		seen    = make(map[uint64]bool, count)
+		current = nodeID
+	)
+	for uint64(len(columns)) < count {
+		// Hash the current ID as a little endian uint256
+		var enc [32]byte
+		for i := range current {
+			enc[i] = current[len(current)-1-i]
+		}
+		hash := sha256.Sum256(enc[:])
+
+		column := binary.LittleEndian.Uint64(hash[:8]) % NumberOfColumns
+		if !seen[column] {
+			seen[column] = true
+			columns = append(columns, column)
+		}
+		// Increment the ID, wrapping around at 2^256
+		for i := len(current) - 1; i >= 0; i-- {
+			current[i]++
+			if current[i] != 0 {
+				break
+			}
+		}
+	}
+	sort.Slice(columns, func(i, j int) bool { return columns[i] < columns[j] })
+	return columns
+}
+
+// sampleColumn returns the column a sample belongs to.
+func sampleColumn(sample *DataSample) uint64 {
+	return sample.SampleIndex % NumberOfColumns
+}
+
+// SampleStore persists the samples of the columns custodied by the local node,
+// keyed by block number, the hash the samples are requested by and column.
+// Samples of blocks older than the retention window are pruned as the chain
+// progresses.
+type SampleStore struct {
+	db        ethdb.KeyValueStore
+	custody   map[uint64]bool // Columns custodied by the local node
+	retention uint64          // Number of blocks to keep samples for
+
+	head uint64 // Highest block number samples were stored for
+	tail uint64 // Lowest block number that may still have samples stored
+	lock sync.Mutex
+}
+
+// NewSampleStore creates a store custodying count columns assigned by the given
+// node ID, keeping samples of the last retention blocks.
+func NewSampleStore(db ethdb.KeyValueStore, nodeID enode.ID, count uint64, retention uint64) *SampleStore {
+	if count < CustodyRequirement {
+		count = CustodyRequirement
+	}
+	custody := make(map[uint64]bool)
+	for _, column := range CustodyColumns(nodeID, count) {
+		custody[column] = true
+	}
+	return &SampleStore{
+		db:        db,
+		custody:   custody,
+		retention: retention,
+	}
+}
+
+// Custodies reports whether the local node custodies the given column.
+func (s *SampleStore) Custodies(column uint64) bool {
+	return s.custody[column%NumberOfColumns]
+}
+
+// Put persists the samples belonging to custodied columns, pruning the ones
+// that fell out of the retention window.
+func (s *SampleStore) Put(samples []*DataSample) {
+	var (
+		batch = s.db.NewBatch()
+		head  uint64
+	)
+	for _, sample := range samples {
+		if sample == nil || sample.BlockNumber == nil || !sample.BlockNumber.IsUint64() {
+			continue
+		}
+		column := sampleColumn(sample)
+		if !s.custody[column] {
+			continue
+		}
+		enc, err := rlp.EncodeToBytes(sample)
+		if err != nil {
+			log.Warn("Failed to encode PeerDAS sample", "err", err)
+			continue
+		}
+		number := sample.BlockNumber.Uint64()
+		rawdb.WriteDASSample(batch, number, sample.DataHash, column, enc)
+		if number > head {
+			head = number
+		}
+	}
+	if batch.ValueSize() == 0 {
+		return
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to store PeerDAS samples", "err", err)
+	}
+	s.prune(head)
+}
+
+// Get retrieves a custodied sample, if it is stored and still within the
+// retention window.
+func (s *SampleStore) Get(number uint64, hash common.Hash, index uint64) (*DataSample, bool) {
+	column := index % NumberOfColumns
+	if !s.custody[column] || s.expired(number) {
+		return nil, false
+	}
+	enc := rawdb.ReadDASSample(s.db, number, hash, column)
+	if len(enc) == 0 {
+		return nil, false
+	}
+	sample := new(DataSample)
+	if err := rlp.DecodeBytes(enc, sample); err != nil {
+		log.Error("Invalid PeerDAS sample RLP", "number", number, "hash", hash, "column", column, "err", err)
+		return nil, false
+	}
+	if sample.SampleIndex != index {
+		return nil, false
+	}
+	return sample, true
+}
+
+// expired reports whether samples of the given block are out of the retention
+// window.
+func (s *SampleStore) expired(number uint64) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.head > s.retention && number < s.head-s.retention
+}
+
+// prune moves the retention window up to the given head, deleting the samples
+// of the blocks that fell out of it.
+func (s *SampleStore) prune(head uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if head <= s.head {
+		return
+	}
+	s.head = head
+	if head <= s.retention || head-s.retention <= s.tail {
+		return
+	}
+	limit := head - s.retention
+	rawdb.PruneDASSamples(s.db, s.tail, limit)
+	s.tail = limit
+}
@@ -12,14 +12,24 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
+// maxCommitmentAge is the number of blocks a registered DA commitment is kept
+// around for validating the block it belongs to.
+const maxCommitmentAge = 1024
+
 var (
 	ErrDataUnavailable        = errors.New("block data not available")
 	ErrInsufficientSamples    = errors.New("insufficient samples for DA verification")
 	ErrCommitmentMismatch     = errors.New("data availability commitment mismatch")
 	ErrValidationNotSupported = errors.New("PeerDAS validation not supported before FUSAKA")
+	ErrMissingCommitment      = errors.New("missing data availability commitment")
+	ErrMissingCellProofs      = errors.New("blob sidecar without cell proofs")
 )
 
 // DACommitment represents a data availability commitment included in block header
@@ -34,10 +44,10 @@ type DACommitment struct {
 
 // BlockDAProof contains the data availability proof for a block
 type BlockDAProof struct {
-	Commitment   *DACommitment      // The commitment being proven
-	Samples      []*DataSample      // Sampled shards
-	SampleCount  uint64             // Number of samples taken
-	IsComplete   bool               // Whether all required samples verified
+	Commitment   *DACommitment       // The commitment being proven
+	Samples      []*DataSample       // Sampled shards
+	SampleCount  uint64              // Number of samples taken
+	IsComplete   bool                // Whether all required samples verified
 	RecoveryData *ErasureEncodedData // For reconstruction if needed
 }
 
@@ -65,8 +75,8 @@ func NewDAValidator(p *PeerDAS, proto *Protocol) *DAValidator {
 	return &DAValidator{
 		peerdas:        p,
 		protocol:       proto,
-		minSampleRatio: 0.5,          // Need 50% of samples
-		sampleCount:    4,            // Sample 4 shards by default
+		minSampleRatio: 0.5, // Need 50% of samples
+		sampleCount:    4,   // Sample 4 shards by default
 	}
 }
 
@@ -134,10 +144,10 @@ func (v *DAValidator) ValidateDataAvailability(commitment *DACommitment) (*Block
 	// Select random sample indices
 	indices := v.selectSampleIndices(commitment.ShardCount)
 
-	// Request samples from network
+	// Request samples from network, samples are keyed by the hash of the data
 	samples, err := v.protocol.RequestSamples(
 		commitment.BlockNumber,
-		commitment.BlockHash,
+		commitment.BlobHash,
 		indices,
 	)
 	if err != nil {
@@ -150,19 +160,14 @@ func (v *DAValidator) ValidateDataAvailability(commitment *DACommitment) (*Block
 		return nil, ErrInsufficientSamples
 	}
 
-	// Verify each sample
-	sampleCommitment := &SampleCommitment{
-		BlockNumber: commitment.BlockNumber,
-		DataHash:    commitment.BlobHash,
-		MerkleRoot:  commitment.DataRoot,
-		SampleCount: commitment.ShardCount,
-	}
-
+	// Verify the samples, keeping only the ones proven to be part of the data
 	verifiedSamples := make([]*DataSample, 0, len(samples))
-	for _, sample := range samples {
-		if err := v.peerdas.VerifySample(sample, sampleCommitment); err == nil {
-			verifiedSamples = append(verifiedSamples, sample)
+	for _, sample := range v.peerdas.FilterValidSamples(samples) {
+		if err := v.VerifySampleProof(sample, commitment); err != nil {
+			log.Debug("Rejected data availability sample", "block", commitment.BlockNumber, "index", sample.SampleIndex, "err", err)
+			continue
 		}
+		verifiedSamples = append(verifiedSamples, sample)
 	}
 
 	// Determine if validation passed
@@ -182,6 +187,109 @@ func (v *DAValidator) ValidateDataAvailability(commitment *DACommitment) (*Block
 	return proof, nil
 }
 
+// VerifySampleProof checks that a block data sample is the shard the DA
+// commitment of a block commits to at the sample index, verifying the Merkle
+// branch of the sample commitment against the data root.
+func (v *DAValidator) VerifySampleProof(sample *DataSample, commitment *DACommitment) error {
+	if sample.isCell() || sample.DataHash != commitment.BlobHash {
+		return ErrInvalidSample
+	}
+	if err := v.peerdas.verifyErasureCoding(sample); err != nil {
+		return err
+	}
+	if !verifyMerkleProof(commitment.DataRoot, sample.Commitment, sample.SampleIndex, commitment.ShardCount, sample.Proof) {
+		return ErrCommitmentMismatch
+	}
+	return nil
+}
+
+// BlockData returns the data a block commits to in its header, the RLP encoding
+// of its transaction list.
+func BlockData(txs types.Transactions) ([]byte, error) {
+	return rlp.EncodeToBytes(txs)
+}
+
+// CommitBlock creates the DA commitment of a block from its transactions. The
+// block hash of the commitment is left empty, as the block hash depends on the
+// header carrying the commitment.
+func (v *DAValidator) CommitBlock(blockNumber *big.Int, txs types.Transactions) (*DACommitment, *ErasureEncodedData, error) {
+	data, err := BlockData(txs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return v.CreateCommitment(blockNumber, common.Hash{}, data)
+}
+
+// CalcBlockCommitment returns the header DA commitment of a post-Fusaka block
+// with the given transactions, encoded with the default erasure configuration.
+func CalcBlockCommitment(config *params.ChainConfig, blockNumber *big.Int, txs types.Transactions) (common.Hash, error) {
+	commitment, _, err := NewDAValidator(NewPeerDAS(config), nil).CommitBlock(blockNumber, txs)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return HashDACommitment(commitment), nil
+}
+
+// VerifyBlock checks that the data committed to in the header of a post-Fusaka
+// block can be sampled from the network. The commitment is recomputed from the
+// block's transactions, blocks without a matching one are refused. Successful
+// validations are remembered, so a block is only sampled once.
+//
+// The committed data is the transaction list the importing node already holds,
+// so sampling it doesn't establish anything about the block itself. It is a
+// liveness check of the network, telling whether peers keep serving the samples
+// of recent blocks for nodes that lack the data.
+//
+// VerifyBlock implements core.DataAvailabilityVerifier.
+func (v *DAValidator) VerifyBlock(block *types.Block) error {
+	if !v.peerdas.IsActive(block.Number()) {
+		return nil
+	}
+	v.prune(block.Number())
+
+	hash := block.Hash()
+	if entry, ok := v.pendingValidations.Load(hash); ok && entry.(*pendingValidation).complete {
+		return nil
+	}
+	expected := block.Header().DACommitment
+	if expected == nil {
+		return ErrMissingCommitment
+	}
+	commitment, _, err := v.CommitBlock(block.Number(), block.Transactions())
+	if err != nil {
+		return err
+	}
+	if HashDACommitment(commitment) != *expected {
+		return ErrCommitmentMismatch
+	}
+	commitment.BlockHash = hash
+
+	proof, err := v.ValidateDataAvailability(commitment)
+	if err != nil {
+		return err
+	}
+	v.pendingValidations.Store(hash, &pendingValidation{
+		commitment: commitment,
+		samples:    proof.Samples,
+		complete:   true,
+	})
+	return nil
+}
+
+// prune drops the commitments of blocks too far behind the given block number.
+func (v *DAValidator) prune(number *big.Int) {
+	if number.Uint64() <= maxCommitmentAge {
+		return
+	}
+	limit := new(big.Int).SetUint64(number.Uint64() - maxCommitmentAge)
+	v.pendingValidations.Range(func(key, value interface{}) bool {
+		if value.(*pendingValidation).commitment.BlockNumber.Cmp(limit) < 0 {
+			v.pendingValidations.Delete(key)
+		}
+		return true
+	})
+}
+
 // ValidateWithFullData validates DA using full data (for block producers)
 func (v *DAValidator) ValidateWithFullData(commitment *DACommitment, encoded *ErasureEncodedData) error {
 	if !v.peerdas.IsActive(commitment.BlockNumber) {
@@ -248,7 +356,11 @@ func (v *DAValidator) StoreBlockSamples(encoded *ErasureEncodedData) {
 		return
 	}
 
-	// Convert encoded shards to samples
+	// Convert encoded shards to samples, proving each against the data root
+	leaves := make([]common.Hash, len(encoded.Shards))
+	for i, shard := range encoded.Shards {
+		leaves[i] = shard.Commitment
+	}
 	samples := make([]*DataSample, len(encoded.Shards))
 	for i, shard := range encoded.Shards {
 		samples[i] = &DataSample{
@@ -257,12 +369,35 @@ func (v *DAValidator) StoreBlockSamples(encoded *ErasureEncodedData) {
 			SampleIndex: uint64(shard.Index),
 			SampleData:  shard.Data,
 			Commitment:  shard.Commitment,
+			Proof:       merkleProof(leaves, i),
 		}
 	}
 
 	v.protocol.StoreSamples(samples)
 }
 
+// StoreSidecarSamples samples the blobs of a blob transaction included in the
+// given block and makes the samples available to the network. Each blob is
+// sampled into its cells, keyed by its versioned hash. The sidecar must carry
+// cell proofs.
+func (v *DAValidator) StoreSidecarSamples(blockNumber *big.Int, sidecar *types.BlobTxSidecar) error {
+	if v.protocol == nil || !v.peerdas.IsActive(blockNumber) {
+		return nil
+	}
+	for i := range sidecar.Blobs {
+		proofs := sidecar.CellProofs(i)
+		if proofs == nil {
+			return ErrMissingCellProofs
+		}
+		samples, err := v.peerdas.SampleBlob(blockNumber, &sidecar.Blobs[i], sidecar.Commitments[i], proofs)
+		if err != nil {
+			return err
+		}
+		v.protocol.StoreSamples(samples)
+	}
+	return nil
+}
+
 // AnnounceBlockSamples announces available samples to the network
 func (v *DAValidator) AnnounceBlockSamples(commitment *DACommitment, encoded *ErasureEncodedData) error {
 	if v.protocol == nil {
@@ -326,20 +461,74 @@ func buildMerkleRoot(hashes [][]byte) common.Hash {
 	return common.BytesToHash(level[0])
 }
 
+// merkleProof returns the Merkle branch of the leaf at the given index up to the
+// root buildMerkleRoot computes over the leaves, a sibling per level.
+func merkleProof(leaves []common.Hash, index int) []common.Hash {
+	var proof []common.Hash
+	for level := leaves; len(level) > 1; index /= 2 {
+		sibling := index ^ 1
+		if sibling >= len(level) {
+			sibling = index // Odd node out, paired with itself
+		}
+		proof = append(proof, level[sibling])
+
+		next := make([]common.Hash, (len(level)+1)/2)
+		for i := range next {
+			left, right := level[2*i], level[2*i]
+			if 2*i+1 < len(level) {
+				right = level[2*i+1]
+			}
+			next[i] = crypto.Keccak256Hash(left[:], right[:])
+		}
+		level = next
+	}
+	return proof
+}
+
+// verifyMerkleProof checks that leaf is the leaf at the given index of a tree of
+// count leaves with the given root, as built by buildMerkleRoot.
+func verifyMerkleProof(root, leaf common.Hash, index, count uint64, proof []common.Hash) bool {
+	if index >= count {
+		return false
+	}
+	hash := leaf
+	for size := count; size > 1; size = (size + 1) / 2 {
+		if len(proof) == 0 {
+			return false
+		}
+		sibling := proof[0]
+		proof = proof[1:]
+
+		switch {
+		case index%2 == 1:
+			hash = crypto.Keccak256Hash(sibling[:], hash[:])
+		case index+1 == size:
+			if sibling != hash {
+				return false // Odd node out must be paired with itself
+			}
+			hash = crypto.Keccak256Hash(hash[:], hash[:])
+		default:
+			hash = crypto.Keccak256Hash(hash[:], sibling[:])
+		}
+		index /= 2
+	}
+	return len(proof) == 0 && hash == root
+}
+
 // BlockHeaderExtension extends block header with DA commitment (for integration)
 type BlockHeaderExtension struct {
 	DACommitmentHash common.Hash // Hash of DACommitment for header inclusion
 }
 
-// HashDACommitment creates a hash of the DA commitment for header inclusion
+// HashDACommitment creates a hash of the DA commitment for header inclusion. The
+// block hash is left out, as it depends on the header carrying the hash.
 func HashDACommitment(c *DACommitment) common.Hash {
 	if c == nil {
 		return common.Hash{}
 	}
 
 	// Combine all commitment fields
-	data := append(c.BlockHash.Bytes(), c.DataRoot.Bytes()...)
-	data = append(data, c.BlobHash.Bytes()...)
+	data := append(c.DataRoot.Bytes(), c.BlobHash.Bytes()...)
 	data = append(data, big.NewInt(int64(c.ShardCount)).Bytes()...)
 	data = append(data, big.NewInt(int64(c.DataShardCount)).Bytes()...)
 
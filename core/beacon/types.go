@@ -58,6 +58,7 @@ type ExecutableDataV1 struct {
 	BaseFeePerGas *big.Int       `json:"baseFeePerGas" gencodec:"required"`
 	BlockHash     common.Hash    `json:"blockHash"     gencodec:"required"`
 	Transactions  [][]byte       `json:"transactions"  gencodec:"required"`
+	DACommitment  *common.Hash   `json:"daCommitment,omitempty"`
 }
 
 // JSON type overrides for executableData.
@@ -156,21 +157,22 @@ func ExecutableDataToBlock(params ExecutableDataV1) (*types.Block, error) {
 		return nil, fmt.Errorf("invalid baseFeePerGas: %v", params.BaseFeePerGas)
 	}
 	header := &types.Header{
-		ParentHash:  params.ParentHash,
-		UncleHash:   types.EmptyUncleHash,
-		Coinbase:    params.FeeRecipient,
-		Root:        params.StateRoot,
-		TxHash:      types.DeriveSha(types.Transactions(txs), trie.NewStackTrie(nil)),
-		ReceiptHash: params.ReceiptsRoot,
-		Bloom:       types.BytesToBloom(params.LogsBloom),
-		Difficulty:  common.Big0,
-		Number:      new(big.Int).SetUint64(params.Number),
-		GasLimit:    params.GasLimit,
-		GasUsed:     params.GasUsed,
-		Time:        params.Timestamp,
-		BaseFee:     params.BaseFeePerGas,
-		Extra:       params.ExtraData,
-		MixDigest:   params.Random,
+		ParentHash:   params.ParentHash,
+		UncleHash:    types.EmptyUncleHash,
+		Coinbase:     params.FeeRecipient,
+		Root:         params.StateRoot,
+		TxHash:       types.DeriveSha(types.Transactions(txs), trie.NewStackTrie(nil)),
+		ReceiptHash:  params.ReceiptsRoot,
+		Bloom:        types.BytesToBloom(params.LogsBloom),
+		Difficulty:   common.Big0,
+		Number:       new(big.Int).SetUint64(params.Number),
+		GasLimit:     params.GasLimit,
+		GasUsed:      params.GasUsed,
+		Time:         params.Timestamp,
+		BaseFee:      params.BaseFeePerGas,
+		Extra:        params.ExtraData,
+		MixDigest:    params.Random,
+		DACommitment: params.DACommitment,
 	}
 	block := types.NewBlockWithHeader(header).WithBody(txs, nil /* uncles */)
 	if block.Hash() != params.BlockHash {
@@ -197,5 +199,6 @@ func BlockToExecutableData(block *types.Block) *ExecutableDataV1 {
 		Transactions:  encodeTransactions(block.Transactions()),
 		Random:        block.MixDigest(),
 		ExtraData:     block.Extra(),
+		DACommitment:  block.Header().DACommitment,
 	}
 }
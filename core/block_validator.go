@@ -17,10 +17,12 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/peerdas"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
@@ -55,17 +57,59 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	if v.bc.HasBlockAndState(block.Hash(), block.NumberU64()) {
 		return ErrKnownBlock
 	}
-	// Header validity is known at this point, check the uncles and transactions
+	// Header validity is known at this point, reject oversized blocks before
+	// doing any work on the body (EIP-7934)
 	header := block.Header()
-	if err := v.engine.VerifyUncles(v.bc, block); err != nil {
-		return err
+	fusaka := v.config.IsFusaka(header.Number)
+	if size := uint64(block.Size()); fusaka && size > params.MaxRLPBlockSizeFUSAKA {
+		return fmt.Errorf("%w: size %d, limit %d", ErrBlockTooLarge, size, params.MaxRLPBlockSizeFUSAKA)
 	}
+	// Check the uncles and transactions. The engine may verify transactions of
+	// the body too, so only once they are known to belong to the header.
 	if hash := types.CalcUncleHash(block.Uncles()); hash != header.UncleHash {
 		return fmt.Errorf("uncle root hash mismatch: have %x, want %x", hash, header.UncleHash)
 	}
 	if hash := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); hash != header.TxHash {
 		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, header.TxHash)
 	}
+	if err := v.engine.VerifyUncles(v.bc, block); err != nil {
+		return err
+	}
+	// Blob transactions may be present after the Cancun fork.
+	var blobs int
+	for i, tx := range block.Transactions() {
+		// No transaction may exceed the per-transaction gas cap after Fusaka (EIP-7825)
+		if fusaka && tx.Gas() > params.MaxTransactionGasFUSAKA {
+			return fmt.Errorf("%w: transaction %d, gas: %d, cap: %d", ErrTransactionGasLimit, i, tx.Gas(), params.MaxTransactionGasFUSAKA)
+		}
+		// Count the number of blobs to validate against the header's blobGasUsed
+		blobs += len(tx.BlobHashes())
+
+		// If the tx is a blob tx, it must NOT have a sidecar attached to be valid in a block.
+		if tx.BlobTxSidecar() != nil {
+			return fmt.Errorf("unexpected blob sidecar in transaction at index %d", i)
+		}
+		// The individual checks for blob validity (version-check + not empty)
+		// happens in state transition.
+	}
+	// Check blob gas usage.
+	if header.BlobGasUsed != nil {
+		if want := *header.BlobGasUsed / params.BlobTxBlobGasPerBlob; uint64(blobs) != want {
+			return fmt.Errorf("blob gas used mismatch (header %v, calculated %v)", *header.BlobGasUsed, blobs*params.BlobTxBlobGasPerBlob)
+		}
+	} else if blobs > 0 {
+		return errors.New("data blobs present in block body")
+	}
+	// FUSAKA: check the data availability commitment against the transactions (EIP-7594)
+	if fusaka {
+		commitment, err := peerdas.CalcBlockCommitment(v.config, header.Number, block.Transactions())
+		if err != nil {
+			return err
+		}
+		if header.DACommitment == nil || *header.DACommitment != commitment {
+			return fmt.Errorf("data availability commitment mismatch: have %v, want %x", header.DACommitment, commitment)
+		}
+	}
 	if !v.bc.HasBlockAndState(block.ParentHash(), block.NumberU64()-1) {
 		if !v.bc.HasBlock(block.ParentHash(), block.NumberU64()-1) {
 			return consensus.ErrUnknownAncestor
@@ -100,12 +144,9 @@ func (v *BlockValidator) ValidateState(block *types.Block, statedb *state.StateD
 	if root := statedb.IntermediateRoot(v.config.IsEIP158(header.Number)); header.Root != root {
 		return fmt.Errorf("invalid merkle root (remote: %x local: %x)", header.Root, root)
 	}
-	// FUSAKA: PeerDAS validation (if active)
-	// TODO: Add PeerDAS sample verification when full integration is implemented
-	// For now, this is a placeholder that ensures FUSAKA fork is recognized
+	// FUSAKA: sample the data committed to by the block (EIP-7594)
 	if v.config.IsFusaka(header.Number) {
-		// PeerDAS validation will be added here when network protocol is integrated
-		// This ensures the fork activation is recognized during block validation
+		return v.validateDataAvailability(block)
 	}
 	return nil
 }
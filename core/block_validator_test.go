@@ -18,8 +18,10 @@ package core
 
 import (
 	"encoding/json"
+	"errors"
 	"math/big"
 	"runtime"
+	"sync"
 	"testing"
 	"time"
 
@@ -372,6 +374,305 @@ func testHeaderConcurrentAbortion(t *testing.T, threads int) {
 	}
 }
 
+// testDAVerifier is a data availability verifier whose verdict can be toggled.
+type testDAVerifier struct {
+	lock     sync.Mutex
+	err      error
+	verified chan common.Hash
+}
+
+func (v *testDAVerifier) VerifyBlock(block *types.Block) error {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.verified <- block.Hash()
+	return v.err
+}
+
+func (v *testDAVerifier) setErr(err error) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.err = err
+}
+
+// Tests that post-Fusaka blocks with unavailable data are deferred under the
+// strict policy, but imported under the warn-only one.
+func TestDataAvailabilityPolicy(t *testing.T) {
+	config := *params.TestChainConfig
+	config.CancunBlock = big.NewInt(2)
+	config.FusakaBlock = big.NewInt(2)
+
+	var (
+		gendb     = rawdb.NewMemoryDatabase()
+		gspec     = &Genesis{Config: &config, Timestamp: uint64(time.Now().Add(-time.Hour).Unix())}
+		genesis   = gspec.MustCommit(gendb)
+		blocks, _ = GenerateChain(&config, genesis, ethash.NewFaker(), gendb, 3, nil)
+		errDA     = errors.New("samples unavailable")
+	)
+	for _, policy := range []DataAvailabilityPolicy{DAPolicyStrict, DAPolicyWarn} {
+		db := rawdb.NewMemoryDatabase()
+		gspec.MustCommit(db)
+
+		chain, _ := NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil, nil)
+		verifier := &testDAVerifier{err: errDA, verified: make(chan common.Hash, len(blocks))}
+		chain.SetDataAvailability(verifier, policy)
+
+		n, err := chain.InsertChain(blocks)
+		switch policy {
+		case DAPolicyStrict:
+			// The first Fusaka block must be deferred, not marked bad
+			if n != 1 || !errors.Is(err, ErrDataUnavailable) {
+				t.Fatalf("%v: insert result mismatch: have %d/%v, want 1/%v", policy, n, err, ErrDataUnavailable)
+			}
+			if head := chain.CurrentBlock().NumberU64(); head != 1 {
+				t.Fatalf("%v: head mismatch: have %d, want 1", policy, head)
+			}
+			if !chain.futureBlocks.Contains(blocks[1].Hash()) {
+				t.Fatalf("%v: block with unavailable data not deferred", policy)
+			}
+			if bad := rawdb.ReadAllBadBlocks(db); len(bad) != 0 {
+				t.Fatalf("%v: block with unavailable data marked bad", policy)
+			}
+			// Once the data becomes available, the deferred block is imported
+			verifier.setErr(nil)
+			chain.procFutureBlocks()
+			if head := chain.CurrentBlock().NumberU64(); head != 2 {
+				t.Fatalf("%v: head mismatch after retry: have %d, want 2", policy, head)
+			}
+		case DAPolicyWarn:
+			if err != nil {
+				t.Fatalf("%v: failed to insert blocks: %v", policy, err)
+			}
+			// Both Fusaka blocks are sampled in the background
+			for i := 0; i < 2; i++ {
+				select {
+				case <-verifier.verified:
+				case <-time.After(time.Second):
+					t.Fatalf("%v: block %d not sampled", policy, i)
+				}
+			}
+		}
+		chain.Stop()
+	}
+}
+
+// Tests that blocks with unavailable data are only retried a limited number of
+// times under the strict policy, and that old blocks imported during sync are
+// not sampled at all.
+func TestDataAvailabilityRetries(t *testing.T) {
+	config := *params.TestChainConfig
+	config.CancunBlock = big.NewInt(1)
+	config.FusakaBlock = big.NewInt(1)
+
+	for _, recent := range []bool{true, false} {
+		var (
+			gendb = rawdb.NewMemoryDatabase()
+			gspec = &Genesis{Config: &config}
+		)
+		if recent {
+			gspec.Timestamp = uint64(time.Now().Add(-time.Hour).Unix())
+		}
+		var (
+			genesis   = gspec.MustCommit(gendb)
+			blocks, _ = GenerateChain(&config, genesis, ethash.NewFaker(), gendb, 1, nil)
+			db        = rawdb.NewMemoryDatabase()
+		)
+		gspec.MustCommit(db)
+
+		chain, _ := NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil, nil)
+		verifier := &testDAVerifier{err: errors.New("samples unavailable"), verified: make(chan common.Hash, maxDAAttempts)}
+		chain.SetDataAvailability(verifier, DAPolicyStrict)
+
+		_, err := chain.InsertChain(blocks)
+		if !recent {
+			// Blocks too old to be sampled are imported as is
+			if err != nil {
+				t.Fatalf("failed to import old block: %v", err)
+			}
+			if n := len(verifier.verified); n != 0 {
+				t.Fatalf("old block sampled %d times", n)
+			}
+			chain.Stop()
+			continue
+		}
+		if !errors.Is(err, ErrDataUnavailable) {
+			t.Fatalf("insert error mismatch: have %v, want %v", err, ErrDataUnavailable)
+		}
+		for i := 1; i < maxDAAttempts; i++ {
+			if !chain.futureBlocks.Contains(blocks[0].Hash()) {
+				t.Fatalf("attempt %d: block not deferred", i)
+			}
+			chain.procFutureBlocks()
+		}
+		// The block is rejected after the last attempt and not retried anymore
+		if n := len(verifier.verified); n != maxDAAttempts {
+			t.Fatalf("sampling attempts mismatch: have %d, want %d", n, maxDAAttempts)
+		}
+		if chain.futureBlocks.Contains(blocks[0].Hash()) {
+			t.Fatalf("block still deferred after %d attempts", maxDAAttempts)
+		}
+		if bad := rawdb.ReadAllBadBlocks(db); len(bad) != 1 || bad[0].Hash() != blocks[0].Hash() {
+			t.Fatalf("rejected block not marked bad")
+		}
+		if head := chain.CurrentBlock().NumberU64(); head != 0 {
+			t.Fatalf("head mismatch: have %d, want 0", head)
+		}
+		chain.Stop()
+	}
+}
+
+// Tests that post-Fusaka blocks are only accepted with a data availability
+// commitment matching their transactions.
+func TestDACommitmentValidation(t *testing.T) {
+	config := *params.TestChainConfig
+	config.CancunBlock = big.NewInt(2)
+	config.FusakaBlock = big.NewInt(2)
+
+	var (
+		gendb     = rawdb.NewMemoryDatabase()
+		gspec     = &Genesis{Config: &config}
+		genesis   = gspec.MustCommit(gendb)
+		blocks, _ = GenerateChain(&config, genesis, ethash.NewFaker(), gendb, 2, nil)
+	)
+	if blocks[0].Header().DACommitment != nil {
+		t.Fatalf("pre-Fusaka block carries a commitment")
+	}
+	if blocks[1].Header().DACommitment == nil {
+		t.Fatalf("Fusaka block carries no commitment")
+	}
+	missing := blocks[1].Header()
+	missing.DACommitment = nil
+
+	invalid := blocks[1].Header()
+	invalid.DACommitment = &common.Hash{0x01}
+
+	for i, header := range []*types.Header{missing, invalid} {
+		db := rawdb.NewMemoryDatabase()
+		gspec.MustCommit(db)
+
+		chain, _ := NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil, nil)
+		if _, err := chain.InsertChain(blocks[:1]); err != nil {
+			t.Fatalf("test %d: failed to insert pre-Fusaka block: %v", i, err)
+		}
+		block := types.NewBlockWithHeader(header).WithBody(blocks[1].Transactions(), nil)
+		if _, err := chain.InsertChain(types.Blocks{block}); err == nil {
+			t.Fatalf("test %d: block with invalid commitment imported", i)
+		}
+		if _, err := chain.InsertChain(blocks[1:]); err != nil {
+			t.Fatalf("test %d: failed to insert valid Fusaka block: %v", i, err)
+		}
+		chain.Stop()
+	}
+}
+
+// Tests that transactions above the per-transaction gas cap are accepted before
+// Fusaka, but rejected both during block validation and state transition after.
+func TestTransactionGasCapTransition(t *testing.T) {
+	config := *params.TestChainConfig
+	config.CancunBlock = big.NewInt(2)
+	config.FusakaBlock = big.NewInt(2)
+
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		signer  = types.LatestSigner(&config)
+		gendb   = rawdb.NewMemoryDatabase()
+		gspec   = &Genesis{
+			Config:   &config,
+			GasLimit: 30_000_000,
+			Alloc:    GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+		}
+		genesis = gspec.MustCommit(gendb)
+	)
+	makeTx := func(nonce uint64, gas uint64) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0xaa}, big.NewInt(0), gas, big.NewInt(params.GWei), nil), signer, key)
+		return tx
+	}
+	// Pre-Fusaka a transaction may use (almost) the whole block, after it only
+	// transactions up to the cap are valid
+	blocks, _ := GenerateChain(&config, genesis, ethash.NewFaker(), gendb, 2, func(i int, b *BlockGen) {
+		if i == 0 {
+			b.AddTx(makeTx(0, 20_000_000))
+		} else {
+			b.AddTx(makeTx(1, params.MaxTransactionGasFUSAKA))
+		}
+	})
+	bad, _ := GenerateChain(&config, blocks[0], ethash.NewFaker(), gendb, 1, func(i int, b *BlockGen) {
+		b.AddUncheckedTx(makeTx(1, params.MaxTransactionGasFUSAKA+1))
+	})
+	db := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(db)
+
+	chain, _ := NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks[:1]); err != nil {
+		t.Fatalf("failed to insert pre-Fusaka block: %v", err)
+	}
+	if _, err := chain.InsertChain(bad); !errors.Is(err, ErrTransactionGasLimit) {
+		t.Fatalf("block with over-capped transaction error mismatch: have %v, want %v", err, ErrTransactionGasLimit)
+	}
+	// The state transition must reject the over-capped transaction too
+	statedb, _ := chain.State()
+	header := blocks[1].Header()
+	gp := new(GasPool).AddGas(header.GasLimit)
+	if _, err := ApplyTransaction(&config, chain, nil, gp, statedb, header, makeTx(1, params.MaxTransactionGasFUSAKA+1), new(uint64), vm.Config{}); !errors.Is(err, ErrTransactionGasLimit) {
+		t.Fatalf("over-capped transaction error mismatch: have %v, want %v", err, ErrTransactionGasLimit)
+	}
+	if _, err := chain.InsertChain(blocks[1:]); err != nil {
+		t.Fatalf("failed to insert Fusaka block: %v", err)
+	}
+}
+
+// Tests that blocks above the RLP size limit are accepted before Fusaka, but
+// rejected during block validation after.
+func TestBlockSizeLimitTransition(t *testing.T) {
+	config := *params.TestChainConfig
+	config.CancunBlock = big.NewInt(2)
+	config.FusakaBlock = big.NewInt(2)
+
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		signer  = types.LatestSigner(&config)
+		gendb   = rawdb.NewMemoryDatabase()
+		gspec   = &Genesis{
+			Config:   &config,
+			GasLimit: 60_000_000,
+			Alloc:    GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+		}
+		genesis = gspec.MustCommit(gendb)
+	)
+	// Zero calldata is the cheapest way to bloat a block, three of these fit
+	// into the gas limit but exceed the size limit together
+	data := make([]byte, params.MaxRLPBlockSizeFUSAKA/3+1024)
+	gas := params.TxGas + uint64(len(data))*params.TxDataZeroGas
+	fill := func(b *BlockGen) {
+		for i := 0; i < 3; i++ {
+			tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(address), common.Address{0xaa}, big.NewInt(0), gas, big.NewInt(params.GWei), data), signer, key)
+			b.AddTx(tx)
+		}
+	}
+	blocks, _ := GenerateChain(&config, genesis, ethash.NewFaker(), gendb, 1, func(i int, b *BlockGen) { fill(b) })
+	bad, _ := GenerateChain(&config, blocks[0], ethash.NewFaker(), gendb, 1, func(i int, b *BlockGen) { fill(b) })
+	if size := uint64(blocks[0].Size()); size <= params.MaxRLPBlockSizeFUSAKA {
+		t.Fatalf("test block too small: have %d, want > %d", size, params.MaxRLPBlockSizeFUSAKA)
+	}
+	db := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(db)
+
+	chain, _ := NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert oversized pre-Fusaka block: %v", err)
+	}
+	if _, err := chain.InsertChain(bad); !errors.Is(err, ErrBlockTooLarge) {
+		t.Fatalf("oversized Fusaka block error mismatch: have %v, want %v", err, ErrBlockTooLarge)
+	}
+}
+
 func TestCalcGasLimit(t *testing.T) {
 	for i, tc := range []struct {
 		pGasLimit uint64
@@ -213,6 +213,11 @@ type BlockChain struct {
 	processor  Processor // Block transaction processor interface
 	forker     *ForkChoice
 	vmConfig   vm.Config
+
+	daVerifier DataAvailabilityVerifier // Sampler of post-Fusaka block data, if any
+	daPolicy   DataAvailabilityPolicy   // Reaction to blocks with unavailable data
+	daQueue    chan *types.Block        // Blocks waiting to be sampled in the background
+	daAttempts *lru.Cache               // Failed sampling attempts of deferred blocks
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -1691,8 +1696,15 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool)
 		// Validate the state using the default validator
 		substart = time.Now()
 		if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
-			bc.reportBlock(block, receipts, err)
 			atomic.StoreUint32(&followupInterrupt, 1)
+
+			// Blocks whose data isn't available yet are not bad, retry them later
+			if errors.Is(err, ErrDataUnavailable) {
+				log.Debug("Deferring block with unavailable data", "number", block.Number(), "hash", block.Hash(), "err", err)
+				bc.futureBlocks.Add(block.Hash(), block)
+				return it.index, err
+			}
+			bc.reportBlock(block, receipts, err)
 			return it.index, err
 		}
 		proctime := time.Since(start)
@@ -0,0 +1,170 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// DataAvailabilityVerifier checks that the data a post-Fusaka block commits to
+// can be sampled from the network. As the importing node holds the data of the
+// block, this is a liveness check of the network serving its samples rather
+// than a validity check of the block.
+type DataAvailabilityVerifier interface {
+	// VerifyBlock samples the data committed to by the given block, returning an
+	// error if the block carries no commitment or its samples can't be obtained.
+	VerifyBlock(block *types.Block) error
+}
+
+// DataAvailabilityPolicy selects how block import reacts to post-Fusaka blocks
+// whose data is unavailable.
+type DataAvailabilityPolicy uint32
+
+const (
+	// DAPolicyWarn samples blocks in the background and only logs the ones
+	// whose data is unavailable, importing them regardless.
+	DAPolicyWarn DataAvailabilityPolicy = iota
+
+	// DAPolicyStrict samples blocks during import and defers the ones whose
+	// data is unavailable until their samples can be obtained, rejecting them
+	// after maxDAAttempts failed attempts. Deferred blocks are retried from the
+	// future block queue, their import returns ErrDataUnavailable.
+	DAPolicyStrict
+)
+
+// String implements the stringer interface.
+func (policy DataAvailabilityPolicy) String() string {
+	switch policy {
+	case DAPolicyWarn:
+		return "warn"
+	case DAPolicyStrict:
+		return "strict"
+	default:
+		return "unknown"
+	}
+}
+
+func (policy DataAvailabilityPolicy) MarshalText() ([]byte, error) {
+	switch policy {
+	case DAPolicyWarn:
+		return []byte("warn"), nil
+	case DAPolicyStrict:
+		return []byte("strict"), nil
+	default:
+		return nil, fmt.Errorf("unknown data availability policy %d", policy)
+	}
+}
+
+func (policy *DataAvailabilityPolicy) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "warn":
+		*policy = DAPolicyWarn
+	case "strict":
+		*policy = DAPolicyStrict
+	default:
+		return fmt.Errorf(`unknown data availability policy %q, want "warn" or "strict"`, text)
+	}
+	return nil
+}
+
+const (
+	// daQueueSize is the number of blocks waiting to be sampled in the background
+	// under the warn policy. Blocks arriving with a full queue are not sampled.
+	daQueueSize = 64
+
+	// daSampleAge is the age past which blocks are not sampled anymore. Older
+	// blocks are synced from history rather than propagated at the head, and
+	// their samples may already have fallen out of the retention window of the
+	// network.
+	daSampleAge = 12 * time.Hour
+
+	// maxDAAttempts is the number of times a block with unavailable data is
+	// sampled under the strict policy before being rejected.
+	maxDAAttempts = 12
+)
+
+// SetDataAvailability installs the verifier sampling post-Fusaka blocks during
+// import, along with the policy applied to blocks failing it. It must be called
+// before any block is imported.
+func (bc *BlockChain) SetDataAvailability(verifier DataAvailabilityVerifier, policy DataAvailabilityPolicy) {
+	bc.daVerifier = verifier
+	bc.daPolicy = policy
+	bc.daAttempts, _ = lru.New(maxFutureBlocks)
+
+	if policy == DAPolicyWarn {
+		bc.daQueue = make(chan *types.Block, daQueueSize)
+		bc.wg.Add(1)
+		go bc.daLoop()
+	}
+}
+
+// daLoop samples the blocks queued under the warn policy one by one, logging
+// the ones whose data is unavailable.
+func (bc *BlockChain) daLoop() {
+	defer bc.wg.Done()
+
+	for {
+		select {
+		case block := <-bc.daQueue:
+			if err := bc.daVerifier.VerifyBlock(block); err != nil {
+				log.Warn("Imported block with unavailable data", "number", block.Number(), "hash", block.Hash(), "err", err)
+			}
+		case <-bc.quit:
+			return
+		}
+	}
+}
+
+// validateDataAvailability applies the configured data availability policy to
+// a post-Fusaka block. Blocks older than daSampleAge, as imported during sync,
+// are not sampled.
+func (v *BlockValidator) validateDataAvailability(block *types.Block) error {
+	bc := v.bc
+	if bc.daVerifier == nil || time.Since(time.Unix(int64(block.Time()), 0)) > daSampleAge {
+		return nil
+	}
+	if bc.daPolicy == DAPolicyWarn {
+		select {
+		case bc.daQueue <- block:
+		default:
+			log.Debug("Skipping data availability check, queue full", "number", block.Number(), "hash", block.Hash())
+		}
+		return nil
+	}
+	hash := block.Hash()
+	if err := bc.daVerifier.VerifyBlock(block); err != nil {
+		attempts := 1
+		if prev, ok := bc.daAttempts.Get(hash); ok {
+			attempts += prev.(int)
+		}
+		if attempts >= maxDAAttempts {
+			// Give up on the block, it's rejected instead of retried again
+			bc.daAttempts.Remove(hash)
+			bc.futureBlocks.Remove(hash)
+			return fmt.Errorf("block data unavailable after %d attempts: %v", attempts, err)
+		}
+		bc.daAttempts.Add(hash, attempts)
+		return fmt.Errorf("%w: %v", ErrDataUnavailable, err)
+	}
+	bc.daAttempts.Remove(hash)
+	return nil
+}
@@ -40,6 +40,16 @@ type ChainReader interface {
 	GetTd(common.Hash, uint64) *big.Int
 }
 
+// finalityReader is implemented by chains tracking a finalized block, which
+// must never be reorged out of the canonical chain.
+type finalityReader interface {
+	// CurrentFinalizedBlock retrieves the current finalized block.
+	CurrentFinalizedBlock() *types.Block
+
+	// GetAncestor retrieves the Nth ancestor of a given block.
+	GetAncestor(hash common.Hash, number, ancestor uint64, maxNonCanonical *uint64) (common.Hash, uint64)
+}
+
 // ForkChoice is the fork chooser based on the highest total difficulty of the
 // chain(the fork choice used in the eth1) and the external fork choice (the fork
 // choice used in the eth2). This main goal of this ForkChoice is not only for
@@ -82,6 +92,12 @@ func (f *ForkChoice) ReorgNeeded(current *types.Header, header *types.Header) (b
 	if localTD == nil || externTd == nil {
 		return false, errors.New("missing td")
 	}
+	// Never switch to a chain that doesn't contain the finalized block, no
+	// matter how much work it carries.
+	if !f.extendsFinalized(header) {
+		log.Debug("Rejected reorg below finalized block", "number", header.Number, "hash", header.Hash())
+		return false, nil
+	}
 	// Accept the new header as the chain head if the transition
 	// is already triggered. We assume all the headers after the
 	// transition come from the trusted consensus layer.
@@ -106,3 +122,23 @@ func (f *ForkChoice) ReorgNeeded(current *types.Header, header *types.Header) (b
 	}
 	return reorg, nil
 }
+
+// extendsFinalized reports whether the given header descends from the finalized
+// block of the local chain, or whether there is no finalized block to respect.
+func (f *ForkChoice) extendsFinalized(header *types.Header) bool {
+	chain, ok := f.chain.(finalityReader)
+	if !ok {
+		return true
+	}
+	finalized := chain.CurrentFinalizedBlock()
+	if finalized == nil {
+		return true
+	}
+	number := header.Number.Uint64()
+	if number < finalized.NumberU64() {
+		return false
+	}
+	maxNonCanonical := uint64(math.MaxUint64)
+	hash, _ := chain.GetAncestor(header.Hash(), number, number-finalized.NumberU64(), &maxNonCanonical)
+	return hash == finalized.Hash()
+}
@@ -412,6 +412,10 @@ func (g *Genesis) ToBlock() *types.Block {
 			head.BaseFee = new(big.Int).SetUint64(params.InitialBaseFee)
 		}
 	}
+	if g.Config != nil && g.Config.IsCancun(common.Big0) {
+		head.ExcessBlobGas = new(uint64)
+		head.BlobGasUsed = new(uint64)
+	}
 	return types.NewBlock(head, nil, nil, nil, trie.NewStackTrie(nil))
 }
 
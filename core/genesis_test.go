@@ -17,7 +17,10 @@
 package core
 
 import (
+	"encoding/json"
 	"math/big"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -239,3 +242,37 @@ func TestReadWriteGenesisAlloc(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the genesis files shipped with the repository are accepted by the
+// fork ordering checks applied when initializing a chain from them.
+func TestShippedGenesisForkOrder(t *testing.T) {
+	var files []string
+	for _, pattern := range []string{"../*genesis*.json", "../altcoinchain-*.json", "../testnet/*genesis*.json"} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatalf("invalid pattern %q: %v", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		t.Fatal("no genesis files found")
+	}
+	for _, file := range files {
+		blob, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("%s: failed to read: %v", file, err)
+		}
+		genesis := new(Genesis)
+		if err := json.Unmarshal(blob, genesis); err != nil {
+			t.Errorf("%s: failed to decode: %v", file, err)
+			continue
+		}
+		if genesis.Config == nil {
+			t.Errorf("%s: missing chain config", file)
+			continue
+		}
+		if err := genesis.Config.CheckConfigForkOrder(); err != nil {
+			t.Errorf("%s: %v", file, err)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ReadHybridAttestations retrieves the RLP encoded set of attestations collected
+// for the given block by the hybrid consensus engine.
+func ReadHybridAttestations(db ethdb.KeyValueReader, number uint64, hash common.Hash) rlp.RawValue {
+	data, _ := db.Get(hybridAttestationsKey(number, hash))
+	return data
+}
+
+// WriteHybridAttestations stores the RLP encoded attestation set of a block.
+func WriteHybridAttestations(db ethdb.KeyValueWriter, number uint64, hash common.Hash, attestations rlp.RawValue) {
+	if err := db.Put(hybridAttestationsKey(number, hash), attestations); err != nil {
+		log.Crit("Failed to store hybrid attestations", "err", err)
+	}
+}
+
+// DeleteHybridAttestations removes the attestation set of a block.
+func DeleteHybridAttestations(db ethdb.KeyValueWriter, number uint64, hash common.Hash) {
+	if err := db.Delete(hybridAttestationsKey(number, hash)); err != nil {
+		log.Crit("Failed to delete hybrid attestations", "err", err)
+	}
+}
+
+// ReadAllHybridAttestations retrieves the RLP encoded attestation sets of all
+// blocks at or above the given number, in ascending block order.
+func ReadAllHybridAttestations(db ethdb.Iteratee, from uint64) []rlp.RawValue {
+	it := db.NewIterator(hybridAttestationsPrefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	var sets []rlp.RawValue
+	for it.Next() {
+		if len(it.Key()) != len(hybridAttestationsPrefix)+8+common.HashLength {
+			continue
+		}
+		sets = append(sets, common.CopyBytes(it.Value()))
+	}
+	return sets
+}
+
+// ReadHybridFinalized retrieves the hash of the block finalized by the hybrid
+// consensus engine at the given height.
+func ReadHybridFinalized(db ethdb.KeyValueReader, number uint64) common.Hash {
+	data, _ := db.Get(hybridFinalizedKey(number))
+	if len(data) != common.HashLength {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteHybridFinalized stores the hash of a block finalized by the hybrid
+// consensus engine.
+func WriteHybridFinalized(db ethdb.KeyValueWriter, number uint64, hash common.Hash) {
+	if err := db.Put(hybridFinalizedKey(number), hash.Bytes()); err != nil {
+		log.Crit("Failed to store hybrid finalized block", "err", err)
+	}
+}
+
+// DeleteHybridFinalized removes the finalized marker at the given height.
+func DeleteHybridFinalized(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Delete(hybridFinalizedKey(number)); err != nil {
+		log.Crit("Failed to delete hybrid finalized block", "err", err)
+	}
+}
+
+// ReadAllHybridFinalized retrieves the chain of finalized blocks at or above
+// the given number, in ascending order.
+func ReadAllHybridFinalized(db ethdb.Iteratee, from uint64) ([]uint64, []common.Hash) {
+	it := db.NewIterator(hybridFinalizedPrefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	var (
+		numbers []uint64
+		hashes  []common.Hash
+	)
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(hybridFinalizedPrefix)+8 || len(it.Value()) != common.HashLength {
+			continue
+		}
+		numbers = append(numbers, binary.BigEndian.Uint64(key[len(hybridFinalizedPrefix):]))
+		hashes = append(hashes, common.BytesToHash(it.Value()))
+	}
+	return numbers, hashes
+}
+
+// ReadHybridJustified retrieves the epoch and block hash of the latest checkpoint
+// justified by the hybrid consensus engine.
+func ReadHybridJustified(db ethdb.KeyValueReader) (uint64, common.Hash, bool) {
+	data, _ := db.Get(hybridJustifiedKey)
+	if len(data) != 8+common.HashLength {
+		return 0, common.Hash{}, false
+	}
+	return binary.BigEndian.Uint64(data[:8]), common.BytesToHash(data[8:]), true
+}
+
+// WriteHybridJustified stores the latest justified checkpoint.
+func WriteHybridJustified(db ethdb.KeyValueWriter, epoch uint64, hash common.Hash) {
+	if err := db.Put(hybridJustifiedKey, append(encodeBlockNumber(epoch), hash.Bytes()...)); err != nil {
+		log.Crit("Failed to store hybrid justified checkpoint", "err", err)
+	}
+}
+
+// ReadHybridOffense retrieves the RLP encoded slashable offense committed by a
+// validator at the given height.
+func ReadHybridOffense(db ethdb.KeyValueReader, number uint64, validator common.Address) rlp.RawValue {
+	data, _ := db.Get(hybridOffenseKey(number, validator))
+	return data
+}
+
+// WriteHybridOffense stores the RLP encoded slashable offense of a validator.
+func WriteHybridOffense(db ethdb.KeyValueWriter, number uint64, validator common.Address, offense rlp.RawValue) {
+	if err := db.Put(hybridOffenseKey(number, validator), offense); err != nil {
+		log.Crit("Failed to store hybrid offense", "err", err)
+	}
+}
+
+// DeleteHybridOffense removes the slashable offense of a validator.
+func DeleteHybridOffense(db ethdb.KeyValueWriter, number uint64, validator common.Address) {
+	if err := db.Delete(hybridOffenseKey(number, validator)); err != nil {
+		log.Crit("Failed to delete hybrid offense", "err", err)
+	}
+}
+
+// ReadAllHybridOffenses retrieves the RLP encoded slashable offenses committed
+// at or above the given number, in ascending block order.
+func ReadAllHybridOffenses(db ethdb.Iteratee, from uint64) []rlp.RawValue {
+	it := db.NewIterator(hybridOffensePrefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	var offenses []rlp.RawValue
+	for it.Next() {
+		if len(it.Key()) != len(hybridOffensePrefix)+8+common.AddressLength {
+			continue
+		}
+		offenses = append(offenses, common.CopyBytes(it.Value()))
+	}
+	return offenses
+}
+
+// WriteHybridVote stores the RLP encoded FFG vote cast by a validator for the
+// given target epoch.
+func WriteHybridVote(db ethdb.KeyValueWriter, epoch uint64, validator common.Address, vote rlp.RawValue) {
+	if err := db.Put(hybridVoteKey(epoch, validator), vote); err != nil {
+		log.Crit("Failed to store hybrid vote", "err", err)
+	}
+}
+
+// ReadAllHybridVotes retrieves the RLP encoded FFG votes of all validators for
+// target epochs at or above the given one, in ascending epoch order.
+func ReadAllHybridVotes(db ethdb.Iteratee, from uint64) []rlp.RawValue {
+	it := db.NewIterator(hybridVotePrefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	var votes []rlp.RawValue
+	for it.Next() {
+		if len(it.Key()) != len(hybridVotePrefix)+8+common.AddressLength {
+			continue
+		}
+		votes = append(votes, common.CopyBytes(it.Value()))
+	}
+	return votes
+}
+
+// PruneHybridVotes deletes the FFG votes of all validators for target epochs
+// below the given one.
+func PruneHybridVotes(db ethdb.KeyValueStore, before uint64) {
+	var (
+		batch = db.NewBatch()
+		end   = append(append([]byte{}, hybridVotePrefix...), encodeBlockNumber(before)...)
+	)
+	it := db.NewIterator(hybridVotePrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if bytes.Compare(it.Key(), end) >= 0 {
+			break
+		}
+		if len(it.Key()) != len(hybridVotePrefix)+8+common.AddressLength {
+			continue
+		}
+		batch.Delete(it.Key())
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to prune hybrid votes", "err", err)
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to prune hybrid votes", "err", err)
+	}
+}
+
+// PruneHybridData deletes all attestation sets, finalized markers and offenses
+// recorded for blocks below the given number. Keys of other data sharing the
+// single byte prefixes, e.g. trie nodes, are left untouched.
+func PruneHybridData(db ethdb.KeyValueStore, before uint64) {
+	batch := db.NewBatch()
+	for _, table := range []struct {
+		prefix []byte
+		length int
+	}{
+		{hybridAttestationsPrefix, len(hybridAttestationsPrefix) + 8 + common.HashLength},
+		{hybridFinalizedPrefix, len(hybridFinalizedPrefix) + 8},
+		{hybridOffensePrefix, len(hybridOffensePrefix) + 8 + common.AddressLength},
+	} {
+		end := append(append([]byte{}, table.prefix...), encodeBlockNumber(before)...)
+
+		it := db.NewIterator(table.prefix, nil)
+		for it.Next() {
+			if bytes.Compare(it.Key(), end) >= 0 {
+				break
+			}
+			if len(it.Key()) != table.length {
+				continue
+			}
+			batch.Delete(it.Key())
+		}
+		it.Release()
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to prune hybrid data", "err", err)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that hybrid consensus data can be stored, iterated and pruned.
+func TestHybridDataStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	validator := common.Address{0x01}
+	for i := uint64(1); i <= 4; i++ {
+		hash := common.Hash{byte(i)}
+		WriteHybridAttestations(db, i, hash, []byte{byte(i)})
+		WriteHybridFinalized(db, i, hash)
+		WriteHybridOffense(db, i, validator, []byte{byte(i)})
+	}
+	if have := ReadHybridFinalized(db, 2); have != (common.Hash{0x02}) {
+		t.Fatalf("finalized hash mismatch: have %x, want %x", have, common.Hash{0x02})
+	}
+	if have := ReadAllHybridAttestations(db, 3); len(have) != 2 || have[0][0] != 3 || have[1][0] != 4 {
+		t.Fatalf("attestation iteration mismatch: have %x", have)
+	}
+	// Trie nodes are raw hashes and may share the single byte prefixes
+	var foreign [][]byte
+	for _, prefix := range [][]byte{hybridAttestationsPrefix, hybridFinalizedPrefix, hybridOffensePrefix} {
+		key := append(append([]byte{}, prefix...), make([]byte, common.HashLength-len(prefix))...)
+		if err := db.Put(key, []byte{0x01}); err != nil {
+			t.Fatalf("failed to write foreign key: %v", err)
+		}
+		foreign = append(foreign, key)
+	}
+	PruneHybridData(db, 3)
+
+	for _, key := range foreign {
+		if ok, _ := db.Has(key); !ok {
+			t.Errorf("foreign key %x sharing a hybrid prefix pruned", key)
+		}
+	}
+
+	if blob := ReadHybridAttestations(db, 2, common.Hash{0x02}); len(blob) != 0 {
+		t.Errorf("pruned attestations still present: %x", blob)
+	}
+	if blob := ReadHybridOffense(db, 2, validator); len(blob) != 0 {
+		t.Errorf("pruned offense still present: %x", blob)
+	}
+	numbers, hashes := ReadAllHybridFinalized(db, 0)
+	if len(numbers) != 2 || numbers[0] != 3 || hashes[1] != (common.Hash{0x04}) {
+		t.Errorf("finalized chain mismatch after pruning: have %v %x", numbers, hashes)
+	}
+	if have := ReadAllHybridOffenses(db, 0); len(have) != 2 {
+		t.Errorf("offense count mismatch after pruning: have %d, want %d", len(have), 2)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ReadDASSample retrieves the RLP encoded PeerDAS sample of the given column of
+// the data identified by number and hash.
+func ReadDASSample(db ethdb.KeyValueReader, number uint64, hash common.Hash, column uint64) rlp.RawValue {
+	data, _ := db.Get(dasSampleKey(number, hash, column))
+	return data
+}
+
+// WriteDASSample stores the RLP encoded PeerDAS sample of a column.
+func WriteDASSample(db ethdb.KeyValueWriter, number uint64, hash common.Hash, column uint64, sample rlp.RawValue) {
+	if err := db.Put(dasSampleKey(number, hash, column), sample); err != nil {
+		log.Crit("Failed to store PeerDAS sample", "err", err)
+	}
+}
+
+// DeleteDASSample removes the PeerDAS sample of a column.
+func DeleteDASSample(db ethdb.KeyValueWriter, number uint64, hash common.Hash, column uint64) {
+	if err := db.Delete(dasSampleKey(number, hash, column)); err != nil {
+		log.Crit("Failed to delete PeerDAS sample", "err", err)
+	}
+}
+
+// ReadDASSamples retrieves the RLP encoded PeerDAS samples stored for the data
+// identified by number and hash, in ascending column order.
+func ReadDASSamples(db ethdb.Iteratee, number uint64, hash common.Hash) []rlp.RawValue {
+	prefix := append(append(append([]byte{}, dasSamplePrefix...), encodeBlockNumber(number)...), hash.Bytes()...)
+
+	it := db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	var samples []rlp.RawValue
+	for it.Next() {
+		if len(it.Key()) != len(prefix)+8 {
+			continue
+		}
+		samples = append(samples, common.CopyBytes(it.Value()))
+	}
+	return samples
+}
+
+// PruneDASSamples deletes all PeerDAS samples stored for blocks in the range
+// [from, before). Keys of other data sharing the prefix are left untouched.
+func PruneDASSamples(db ethdb.KeyValueStore, from uint64, before uint64) {
+	var (
+		batch = db.NewBatch()
+		end   = append(append([]byte{}, dasSamplePrefix...), encodeBlockNumber(before)...)
+	)
+	it := db.NewIterator(dasSamplePrefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	for it.Next() {
+		if bytes.Compare(it.Key(), end) >= 0 {
+			break
+		}
+		if len(it.Key()) != len(dasSamplePrefix)+8+common.HashLength+8 {
+			continue
+		}
+		batch.Delete(it.Key())
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to prune PeerDAS samples", "err", err)
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to prune PeerDAS samples", "err", err)
+	}
+}
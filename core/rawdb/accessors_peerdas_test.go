@@ -0,0 +1,65 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that PeerDAS samples can be stored, iterated and pruned.
+func TestDASSampleStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	for i := uint64(1); i <= 4; i++ {
+		hash := common.Hash{byte(i)}
+		for column := uint64(0); column < 3; column++ {
+			WriteDASSample(db, i, hash, column, []byte{byte(i), byte(column)})
+		}
+	}
+	if blob := ReadDASSample(db, 2, common.Hash{0x02}, 1); len(blob) != 2 || blob[0] != 2 || blob[1] != 1 {
+		t.Fatalf("sample mismatch: have %x", blob)
+	}
+	if blob := ReadDASSample(db, 2, common.Hash{0x03}, 1); len(blob) != 0 {
+		t.Fatalf("sample of unknown hash found: %x", blob)
+	}
+	if have := ReadDASSamples(db, 3, common.Hash{0x03}); len(have) != 3 || have[2][1] != 2 {
+		t.Fatalf("sample iteration mismatch: have %x", have)
+	}
+	DeleteDASSample(db, 3, common.Hash{0x03}, 0)
+	if have := ReadDASSamples(db, 3, common.Hash{0x03}); len(have) != 2 {
+		t.Fatalf("sample count mismatch after deletion: have %d, want %d", len(have), 2)
+	}
+	// Keys of other data sharing the prefix must survive pruning
+	foreign := append(append([]byte{}, dasSamplePrefix...), encodeBlockNumber(1)...)
+	if err := db.Put(foreign, []byte{0x01}); err != nil {
+		t.Fatalf("failed to write foreign key: %v", err)
+	}
+	PruneDASSamples(db, 0, 3)
+
+	if ok, _ := db.Has(foreign); !ok {
+		t.Errorf("foreign key sharing the sample prefix pruned")
+	}
+
+	if blob := ReadDASSample(db, 2, common.Hash{0x02}, 1); len(blob) != 0 {
+		t.Errorf("pruned sample still present: %x", blob)
+	}
+	if have := ReadDASSamples(db, 4, common.Hash{0x04}); len(have) != 3 {
+		t.Errorf("sample count mismatch after pruning: have %d, want %d", len(have), 3)
+	}
+}
@@ -378,6 +378,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		bloomBits       stat
 		beaconHeaders   stat
 		cliqueSnaps     stat
+		hybridData      stat
+		dasSamples      stat
 
 		// Ancient store statistics
 		ancientHeadersSize  common.StorageSize
@@ -441,6 +443,13 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			beaconHeaders.Add(size)
 		case bytes.HasPrefix(key, []byte("clique-")) && len(key) == 7+common.HashLength:
 			cliqueSnaps.Add(size)
+		case bytes.HasPrefix(key, hybridAttestationsPrefix) && len(key) == (len(hybridAttestationsPrefix)+8+common.HashLength),
+			bytes.HasPrefix(key, hybridFinalizedPrefix) && len(key) == (len(hybridFinalizedPrefix)+8),
+			bytes.HasPrefix(key, hybridOffensePrefix) && len(key) == (len(hybridOffensePrefix)+8+common.AddressLength),
+			bytes.HasPrefix(key, hybridVotePrefix) && len(key) == (len(hybridVotePrefix)+8+common.AddressLength):
+			hybridData.Add(size)
+		case bytes.HasPrefix(key, dasSamplePrefix) && len(key) == (len(dasSamplePrefix)+8+common.HashLength+8):
+			dasSamples.Add(size)
 		case bytes.HasPrefix(key, []byte("cht-")) ||
 			bytes.HasPrefix(key, []byte("chtIndexV2-")) ||
 			bytes.HasPrefix(key, []byte("chtRootV2-")): // Canonical hash trie
@@ -456,6 +465,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				lastPivotKey, fastTrieProgressKey, snapshotDisabledKey, SnapshotRootKey, snapshotJournalKey,
 				snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
 				uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
+				hybridJustifiedKey,
 			} {
 				if bytes.Equal(key, meta) {
 					metadata.Add(size)
@@ -503,6 +513,8 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Storage snapshot", storageSnaps.Size(), storageSnaps.Count()},
 		{"Key-Value store", "Beacon sync headers", beaconHeaders.Size(), beaconHeaders.Count()},
 		{"Key-Value store", "Clique snapshots", cliqueSnaps.Size(), cliqueSnaps.Count()},
+		{"Key-Value store", "Hybrid consensus data", hybridData.Size(), hybridData.Count()},
+		{"Key-Value store", "PeerDAS samples", dasSamples.Size(), dasSamples.Count()},
 		{"Key-Value store", "Singleton metadata", metadata.Size(), metadata.Count()},
 		{"Ancient store", "Headers", ancientHeadersSize.String(), ancients.String()},
 		{"Ancient store", "Bodies", ancientBodiesSize.String(), ancients.String()},
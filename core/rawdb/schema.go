@@ -84,6 +84,9 @@ var (
 	// transitionStatusKey tracks the eth2 transition status.
 	transitionStatusKey = []byte("eth2-transition")
 
+	// hybridJustifiedKey tracks the latest checkpoint justified by hybrid consensus.
+	hybridJustifiedKey = []byte("HybridJustified")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -100,10 +103,17 @@ var (
 	CodePrefix            = []byte("c") // CodePrefix + code hash -> account code
 	skeletonHeaderPrefix  = []byte("S") // skeletonHeaderPrefix + num (uint64 big endian) -> header
 
+	hybridAttestationsPrefix = []byte("A") // hybridAttestationsPrefix + num (uint64 big endian) + hash -> attestation set
+	hybridFinalizedPrefix    = []byte("F") // hybridFinalizedPrefix + num (uint64 big endian) -> finalized block hash
+	hybridOffensePrefix      = []byte("O") // hybridOffensePrefix + num (uint64 big endian) + validator -> slashable offense
+
 	PreimagePrefix = []byte("secure-key-")       // PreimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-")  // config prefix for the db
 	genesisPrefix  = []byte("ethereum-genesis-") // genesis state prefix for the db
 
+	dasSamplePrefix  = []byte("das-")      // dasSamplePrefix + num (uint64 big endian) + hash + column (uint64 big endian) -> PeerDAS sample
+	hybridVotePrefix = []byte("ffg-vote-") // hybridVotePrefix + target epoch (uint64 big endian) + validator -> FFG vote
+
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 
@@ -196,6 +206,31 @@ func skeletonHeaderKey(number uint64) []byte {
 	return append(skeletonHeaderPrefix, encodeBlockNumber(number)...)
 }
 
+// hybridAttestationsKey = hybridAttestationsPrefix + num (uint64 big endian) + hash
+func hybridAttestationsKey(number uint64, hash common.Hash) []byte {
+	return append(append(hybridAttestationsPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// hybridFinalizedKey = hybridFinalizedPrefix + num (uint64 big endian)
+func hybridFinalizedKey(number uint64) []byte {
+	return append(hybridFinalizedPrefix, encodeBlockNumber(number)...)
+}
+
+// hybridOffenseKey = hybridOffensePrefix + num (uint64 big endian) + validator
+func hybridOffenseKey(number uint64, validator common.Address) []byte {
+	return append(append(hybridOffensePrefix, encodeBlockNumber(number)...), validator.Bytes()...)
+}
+
+// hybridVoteKey = hybridVotePrefix + target epoch (uint64 big endian) + validator
+func hybridVoteKey(epoch uint64, validator common.Address) []byte {
+	return append(append(hybridVotePrefix, encodeBlockNumber(epoch)...), validator.Bytes()...)
+}
+
+// dasSampleKey = dasSamplePrefix + num (uint64 big endian) + hash + column (uint64 big endian)
+func dasSampleKey(number uint64, hash common.Hash, column uint64) []byte {
+	return append(append(append(dasSamplePrefix, encodeBlockNumber(number)...), hash.Bytes()...), encodeBlockNumber(column)...)
+}
+
 // preimageKey = PreimagePrefix + hash
 func preimageKey(hash common.Hash) []byte {
 	return append(PreimagePrefix, hash.Bytes()...)
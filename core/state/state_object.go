@@ -92,6 +92,10 @@ type stateObject struct {
 	dirtyCode bool // true if the code was updated
 	suicided  bool
 	deleted   bool
+
+	// Flag whether the object was created in the current transaction. It's
+	// used to restrict SELFDESTRUCT after Cancun (EIP-6780).
+	created bool
 }
 
 // empty returns whether the account is considered empty.
@@ -442,6 +446,7 @@ func (s *stateObject) deepCopy(db *StateDB) *stateObject {
 	stateObject.suicided = s.suicided
 	stateObject.dirtyCode = s.dirtyCode
 	stateObject.deleted = s.deleted
+	stateObject.created = s.created
 	return stateObject
 }
 
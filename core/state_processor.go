@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -130,6 +131,10 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	}
 	blockContext := NewEVMBlockContext(header, p.bc, nil)
 	vmenv := vm.NewEVM(blockContext, vm.TxContext{}, statedb, p.config, cfg)
+	// Record the parent hash in the history storage contract (EIP-2935)
+	if p.config.IsPrague(blockNumber) {
+		ProcessParentBlockHash(block.ParentHash(), vmenv, statedb)
+	}
 	// Iterate over and process the individual transactions
 	for i, tx := range block.Transactions() {
 		msg, err := tx.AsMessage(types.MakeSigner(p.config, header.Number), header.BaseFee)
@@ -213,3 +218,17 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	vmenv := vm.NewEVM(blockContext, vm.TxContext{}, statedb, config, cfg)
 	return applyTransaction(msg, config, author, gp, statedb, header.Number, header.Hash(), tx, usedGas, vmenv)
 }
+
+// ProcessParentBlockHash stores the parent block hash in the EIP-2935 history
+// storage contract, by calling it from the system address ahead of any of the
+// block's transactions. The contract is installed first if it is missing.
+func ProcessParentBlockHash(prevHash common.Hash, vmenv *vm.EVM, statedb *state.StateDB) {
+	misc.ApplyHistoryStorageFork(statedb)
+
+	vmenv.Reset(vm.TxContext{Origin: params.SystemAddress, GasPrice: new(big.Int)}, statedb)
+	statedb.AddAddressToAccessList(params.HistoryStorageAddress)
+	if _, _, err := vmenv.Call(vm.AccountRef(params.SystemAddress), params.HistoryStorageAddress, prevHash.Bytes(), params.SystemCallGas, new(big.Int)); err != nil {
+		log.Error("Failed to store parent block hash", "parent", prevHash, "err", err)
+	}
+	statedb.Finalise(true)
+}
@@ -17,7 +17,9 @@
 package core
 
 import (
+	"bytes"
 	"crypto/ecdsa"
+	"errors"
 	"math/big"
 	"testing"
 
@@ -27,6 +29,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -340,3 +343,160 @@ func GenerateBadBlock(parent *types.Block, engine consensus.Engine, txs types.Tr
 	// Assemble and return the final block for sealing
 	return types.NewBlock(header, txs, nil, receipts, trie.NewStackTrie(nil))
 }
+
+// Tests that set code transactions install delegations which are followed by
+// calls into the authority, and that delegations can be cleared again.
+func TestSetCodeTransactions(t *testing.T) {
+	config := *params.TestChainConfig
+	config.ShanghaiBlock = big.NewInt(0)
+	config.CancunBlock = big.NewInt(0)
+	config.PragueBlock = big.NewInt(1)
+
+	var (
+		key1, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		key2, _  = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		addr1    = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2    = crypto.PubkeyToAddress(key2.PublicKey)
+		delegate = common.Address{0xcc}
+		signer   = types.LatestSigner(&config)
+		gendb    = rawdb.NewMemoryDatabase()
+		funds    = big.NewInt(params.Ether)
+		gspec    = &Genesis{
+			Config: &config,
+			Alloc: GenesisAlloc{
+				addr1: {Balance: funds},
+				addr2: {Balance: funds},
+				// PUSH1 0x42 PUSH1 0x00 SSTORE STOP
+				delegate: {Code: common.FromHex("604260005500"), Balance: common.Big0},
+			},
+		}
+		genesis = gspec.MustCommit(gendb)
+	)
+	makeTx := func(nonce uint64, auths ...types.SetCodeAuthorization) *types.Transaction {
+		return types.MustSignNewTx(key1, signer, &types.SetCodeTx{
+			ChainID:   config.ChainID,
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(params.GWei),
+			GasFeeCap: big.NewInt(10 * params.GWei),
+			Gas:       100000,
+			To:        addr2,
+			Value:     new(big.Int),
+			AuthList:  auths,
+		})
+	}
+	makeAuth := func(nonce uint64, target common.Address) types.SetCodeAuthorization {
+		auth, err := types.SignSetCode(key2, types.SetCodeAuthorization{
+			ChainID: config.ChainID,
+			Address: target,
+			Nonce:   nonce,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return auth
+	}
+	blocks, _ := GenerateChain(&config, genesis, ethash.NewFaker(), gendb, 3, func(i int, b *BlockGen) {
+		switch i {
+		case 1:
+			b.AddTx(makeTx(0, makeAuth(0, delegate)))
+		case 2:
+			b.AddTx(makeTx(1, makeAuth(1, common.Address{})))
+		}
+	})
+	// Set code transactions must be rejected before Prague
+	statedb, _ := state.New(genesis.Root(), state.NewDatabase(gendb), nil)
+	gp := new(GasPool).AddGas(genesis.GasLimit())
+	if _, err := ApplyTransaction(&config, nil, &addr1, gp, statedb, genesis.Header(), makeTx(0, makeAuth(0, delegate)), new(uint64), vm.Config{}); !errors.Is(err, ErrTxTypeNotSupported) {
+		t.Fatalf("pre-Prague set code transaction error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	db := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(db)
+
+	chain, _ := NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks[:2]); err != nil {
+		t.Fatalf("failed to insert delegating block: %v", err)
+	}
+	statedb, _ = chain.State()
+	if have, want := statedb.GetCode(addr2), types.AddressToDelegation(delegate); !bytes.Equal(have, want) {
+		t.Fatalf("delegation mismatch: have %x, want %x", have, want)
+	}
+	if have := statedb.GetNonce(addr2); have != 1 {
+		t.Fatalf("authority nonce mismatch: have %d, want 1", have)
+	}
+	if have, want := statedb.GetState(addr2, common.Hash{}), common.BigToHash(big.NewInt(0x42)); have != want {
+		t.Fatalf("delegated storage mismatch: have %x, want %x", have, want)
+	}
+	if _, err := chain.InsertChain(blocks[2:]); err != nil {
+		t.Fatalf("failed to insert clearing block: %v", err)
+	}
+	statedb, _ = chain.State()
+	if have := statedb.GetCode(addr2); len(have) != 0 {
+		t.Fatalf("delegation not cleared: have %x", have)
+	}
+	if have := statedb.GetNonce(addr2); have != 2 {
+		t.Fatalf("authority nonce mismatch: have %d, want 2", have)
+	}
+}
+
+// Tests that the EIP-2935 history storage contract is installed at the Prague
+// fork and records every parent hash, which contracts can then read back.
+func TestHistoryStorage(t *testing.T) {
+	config := *params.TestChainConfig
+	config.ShanghaiBlock = big.NewInt(0)
+	config.CancunBlock = big.NewInt(0)
+	config.PragueBlock = big.NewInt(2)
+
+	var (
+		gendb   = rawdb.NewMemoryDatabase()
+		gspec   = &Genesis{Config: &config}
+		genesis = gspec.MustCommit(gendb)
+	)
+	blocks, _ := GenerateChain(&config, genesis, ethash.NewFaker(), gendb, 4, nil)
+
+	db := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(db)
+
+	chain, _ := NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks[:1]); err != nil {
+		t.Fatalf("failed to insert pre-Prague block: %v", err)
+	}
+	statedb, _ := chain.State()
+	if code := statedb.GetCode(params.HistoryStorageAddress); len(code) != 0 {
+		t.Fatalf("history contract installed before Prague: %x", code)
+	}
+	if _, err := chain.InsertChain(blocks[1:]); err != nil {
+		t.Fatalf("failed to insert Prague blocks: %v", err)
+	}
+	statedb, _ = chain.State()
+	if have := statedb.GetCode(params.HistoryStorageAddress); !bytes.Equal(have, params.HistoryStorageCode) {
+		t.Fatalf("history contract code mismatch: have %x", have)
+	}
+	// Blocks 2..4 record the hashes of blocks 1..3, block 1 was pre-fork
+	for number := uint64(0); number < 4; number++ {
+		want := common.Hash{}
+		if number > 0 {
+			want = blocks[number-1].Hash()
+		}
+		slot := common.BigToHash(new(big.Int).SetUint64(number % params.HistoryServeWindow))
+		if have := statedb.GetState(params.HistoryStorageAddress, slot); have != want {
+			t.Errorf("block %d: stored hash mismatch: have %x, want %x", number, have, want)
+		}
+	}
+	// Contracts read the history through the contract's getter
+	vmenv := vm.NewEVM(NewEVMBlockContext(chain.CurrentHeader(), chain, &common.Address{}), vm.TxContext{}, statedb, &config, vm.Config{})
+	ret, _, err := vmenv.Call(vm.AccountRef(common.Address{0x01}), params.HistoryStorageAddress, common.BigToHash(big.NewInt(2)).Bytes(), 100000, new(big.Int))
+	if err != nil {
+		t.Fatalf("history lookup failed: %v", err)
+	}
+	if have, want := common.BytesToHash(ret), blocks[1].Hash(); have != want {
+		t.Fatalf("history lookup mismatch: have %x, want %x", have, want)
+	}
+	// Lookups of the current block are rejected by the contract
+	if _, _, err := vmenv.Call(vm.AccountRef(common.Address{0x01}), params.HistoryStorageAddress, common.BigToHash(chain.CurrentHeader().Number).Bytes(), 100000, new(big.Int)); err == nil {
+		t.Fatalf("history lookup of the current block succeeded")
+	}
+}
@@ -18,6 +18,7 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"sort"
@@ -77,10 +78,6 @@ var (
 	// maximum allowance of the current block.
 	ErrGasLimit = errors.New("exceeds block gas limit")
 
-	// ErrTransactionGasLimit is returned if a transaction's gas limit exceeds the
-	// per-transaction gas limit (EIP-7825: 2^24 = 16,777,216).
-	ErrTransactionGasLimit = errors.New("exceeds transaction gas limit")
-
 	// ErrNegativeValue is a sanity error to ensure no one is able to specify a
 	// transaction with a negative value.
 	ErrNegativeValue = errors.New("negative value")
@@ -89,6 +86,26 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrMissingBlobSidecar is returned if a blob transaction is submitted to
+	// the pool without the blobs it commits to.
+	ErrMissingBlobSidecar = errors.New("missing blob sidecar")
+
+	// ErrTooManyBlobs is returned if a blob transaction carries more blobs than
+	// fit into a block.
+	ErrTooManyBlobs = errors.New("too many blobs")
+
+	// ErrBlobSidecarVersion is returned if a blob transaction is submitted with
+	// a sidecar carrying the wrong kind of proofs for the current fork.
+	ErrBlobSidecarVersion = errors.New("unexpected blob sidecar version")
+
+	// ErrInflightTxLimitReached is returned if a delegated account, or one with a
+	// pending authorization, attempts to queue more than one transaction.
+	ErrInflightTxLimitReached = errors.New("in-flight transaction limit reached for delegated accounts")
+
+	// ErrAuthorityReserved is returned if a set code transaction carries an
+	// authorization from an account which already has transactions in the pool.
+	ErrAuthorityReserved = errors.New("authority already reserved")
 )
 
 var (
@@ -247,6 +264,10 @@ type TxPool struct {
 	istanbul bool // Fork indicator whether we are in the istanbul stage.
 	eip2718  bool // Fork indicator whether we are using EIP-2718 type transactions.
 	eip1559  bool // Fork indicator whether we are using EIP-1559 type transactions.
+	shanghai bool // Fork indicator whether we are in the Shanghai stage.
+	cancun   bool // Fork indicator whether we are using EIP-4844 type transactions.
+	prague   bool // Fork indicator whether we are using EIP-7702 type transactions.
+	fusaka   bool // Fork indicator whether blob sidecars carry EIP-7594 cell proofs.
 
 	currentState  *state.StateDB // Current state in the blockchain head
 	pendingNonces *txNoncer      // Pending state tracking virtual nonces
@@ -596,10 +617,23 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if !pool.eip1559 && tx.Type() == types.DynamicFeeTxType {
 		return ErrTxTypeNotSupported
 	}
-	// Reject transactions over defined size to prevent DOS attacks
-	if uint64(tx.Size()) > txMaxSize {
+	// Reject blob transactions until EIP-4844 activates.
+	if !pool.cancun && tx.Type() == types.BlobTxType {
+		return ErrTxTypeNotSupported
+	}
+	// Reject set code transactions until EIP-7702 activates.
+	if !pool.prague && tx.Type() == types.SetCodeTxType {
+		return ErrTxTypeNotSupported
+	}
+	// Reject transactions over defined size to prevent DOS attacks. The blobs
+	// are bounded by the per-block blob limit instead.
+	if uint64(tx.WithoutBlobTxSidecar().Size()) > txMaxSize {
 		return ErrOversizedData
 	}
+	// Check whether the init code size has been exceeded.
+	if pool.shanghai && tx.To() == nil && len(tx.Data()) > params.MaxInitCodeSize {
+		return fmt.Errorf("%w: code size %v limit %v", ErrMaxInitCodeSizeExceeded, len(tx.Data()), params.MaxInitCodeSize)
+	}
 	// Transactions can't be negative. This may never happen using RLP decoded
 	// transactions but may occur if you create a transaction using the RPC.
 	if tx.Value().Sign() < 0 {
@@ -626,11 +660,25 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if tx.GasFeeCapIntCmp(tx.GasTipCap()) < 0 {
 		return ErrTipAboveFeeCap
 	}
+	// Ensure blob transactions carry the blobs they commit to
+	if tx.Type() == types.BlobTxType {
+		if err := validateBlobTx(tx, pool.fusaka); err != nil {
+			return err
+		}
+	}
+	// Ensure set code transactions authorize at least one delegation
+	if tx.Type() == types.SetCodeTxType && len(tx.SetCodeAuthorizations()) == 0 {
+		return ErrEmptyAuthList
+	}
 	// Make sure the transaction is signed properly.
 	from, err := types.Sender(pool.signer, tx)
 	if err != nil {
 		return ErrInvalidSender
 	}
+	// Ensure delegations and authorizations can't invalidate pooled transactions
+	if err := pool.validateAuth(from, tx); err != nil {
+		return err
+	}
 	// Drop non-local transactions under our own minimal accepted gas price or tip
 	if !local && tx.GasTipCapIntCmp(pool.gasPrice) < 0 {
 		return ErrUnderpriced
@@ -645,7 +693,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 		return ErrInsufficientFunds
 	}
 	// Ensure the transaction has more gas than the basic tx fee.
-	intrGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true, pool.istanbul)
+	intrGas, err := IntrinsicGas(tx.Data(), tx.AccessList(), tx.SetCodeAuthorizations(), tx.To() == nil, true, pool.istanbul, pool.shanghai)
 	if err != nil {
 		return err
 	}
@@ -655,6 +703,48 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	return nil
 }
 
+// validateAuth checks the restrictions EIP-7702 places on pooled transactions.
+// The nonce of a delegated account can be bumped by any contract call, and the
+// nonce of an authority by any set code transaction, so such accounts may only
+// have a single transaction in flight.
+func (pool *TxPool) validateAuth(from common.Address, tx *types.Transaction) error {
+	// Allow at most one in-flight tx for delegated accounts or those with a
+	// pending authorization.
+	_, delegated := types.ParseDelegation(pool.currentState.GetCode(from))
+	if delegated || pool.all.HasAuth(from) {
+		var (
+			count  int
+			exists bool
+		)
+		if pending := pool.pending[from]; pending != nil {
+			count += pending.Len()
+			exists = pending.Overlaps(tx)
+		}
+		if queue := pool.queue[from]; queue != nil {
+			count += queue.Len()
+			exists = exists || queue.Overlaps(tx)
+		}
+		// Replacing the existing in-flight transaction is still allowed
+		if count >= 1 && !exists {
+			return ErrInflightTxLimitReached
+		}
+	}
+	// Authorities cannot conflict with any pending or queued transactions.
+	for _, auth := range tx.SetCodeAuthorities() {
+		var count int
+		if pending := pool.pending[auth]; pending != nil {
+			count += pending.Len()
+		}
+		if queue := pool.queue[auth]; queue != nil {
+			count += queue.Len()
+		}
+		if count > 1 || (count == 1 && auth != from) {
+			return ErrAuthorityReserved
+		}
+	}
+	return nil
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
@@ -1314,6 +1404,38 @@ func (pool *TxPool) reset(oldHead, newHead *types.Header) {
 	pool.istanbul = pool.chainconfig.IsIstanbul(next)
 	pool.eip2718 = pool.chainconfig.IsBerlin(next)
 	pool.eip1559 = pool.chainconfig.IsLondon(next)
+	pool.shanghai = pool.chainconfig.IsShanghai(next)
+	pool.cancun = pool.chainconfig.IsCancun(next)
+	pool.prague = pool.chainconfig.IsPrague(next)
+	pool.fusaka = pool.chainconfig.IsFusaka(next)
+}
+
+// validateBlobTx checks the blob fields of a blob transaction and that its
+// sidecar holds valid blobs matching the versioned hashes. Once cell proofs are
+// required, only sidecars carrying them are accepted, and only those before.
+func validateBlobTx(tx *types.Transaction, cellProofs bool) error {
+	if tx.BlobGasFeeCap().BitLen() > 256 {
+		return ErrFeeCapVeryHigh
+	}
+	hashes := tx.BlobHashes()
+	if len(hashes) == 0 {
+		return ErrMissingBlobHashes
+	}
+	if tx.BlobGas() > params.BlobTxMaxBlobGasPerBlock {
+		return fmt.Errorf("%w: have %d, permitted %d", ErrTooManyBlobs, len(hashes), params.BlobTxMaxBlobGasPerBlock/params.BlobTxBlobGasPerBlob)
+	}
+	sidecar := tx.BlobTxSidecar()
+	if sidecar == nil {
+		return ErrMissingBlobSidecar
+	}
+	version := types.BlobSidecarVersion0
+	if cellProofs {
+		version = types.BlobSidecarVersion1
+	}
+	if sidecar.Version != version {
+		return fmt.Errorf("%w: have %d, want %d", ErrBlobSidecarVersion, sidecar.Version, version)
+	}
+	return sidecar.ValidateBlobs(hashes)
 }
 
 // promoteExecutables moves transactions that have become processable from the
@@ -1673,6 +1795,7 @@ type txLookup struct {
 	lock    sync.RWMutex
 	locals  map[common.Hash]*types.Transaction
 	remotes map[common.Hash]*types.Transaction
+	auths   map[common.Address][]common.Hash // All accounts with a pooled authorization
 }
 
 // newTxLookup returns a new txLookup structure.
@@ -1680,6 +1803,7 @@ func newTxLookup() *txLookup {
 	return &txLookup{
 		locals:  make(map[common.Hash]*types.Transaction),
 		remotes: make(map[common.Hash]*types.Transaction),
+		auths:   make(map[common.Address][]common.Hash),
 	}
 }
 
@@ -1778,6 +1902,7 @@ func (t *txLookup) Add(tx *types.Transaction, local bool) {
 	} else {
 		t.remotes[tx.Hash()] = tx
 	}
+	t.addAuthorities(tx)
 }
 
 // Remove removes a transaction from the lookup.
@@ -1796,10 +1921,46 @@ func (t *txLookup) Remove(hash common.Hash) {
 	t.slots -= numSlots(tx)
 	slotsGauge.Update(int64(t.slots))
 
+	t.removeAuthorities(tx)
 	delete(t.locals, hash)
 	delete(t.remotes, hash)
 }
 
+// HasAuth returns whether any pooled set code transaction carries an
+// authorization signed by the given account.
+func (t *txLookup) HasAuth(addr common.Address) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return len(t.auths[addr]) > 0
+}
+
+// addAuthorities tracks the authorities of a set code transaction.
+func (t *txLookup) addAuthorities(tx *types.Transaction) {
+	for _, addr := range tx.SetCodeAuthorities() {
+		t.auths[addr] = append(t.auths[addr], tx.Hash())
+	}
+}
+
+// removeAuthorities stops tracking the authorities of a set code transaction.
+func (t *txLookup) removeAuthorities(tx *types.Transaction) {
+	hash := tx.Hash()
+	for _, addr := range tx.SetCodeAuthorities() {
+		list := t.auths[addr]
+		for i, h := range list {
+			if h == hash {
+				list = append(list[:i], list[i+1:]...)
+				break
+			}
+		}
+		if len(list) == 0 {
+			delete(t.auths, addr)
+		} else {
+			t.auths[addr] = list
+		}
+	}
+}
+
 // RemoteToLocals migrates the transactions belongs to the given locals to locals
 // set. The assumption is held the locals set is thread-safe to be used.
 func (t *txLookup) RemoteToLocals(locals *accountSet) int {
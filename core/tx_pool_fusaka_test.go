@@ -1,15 +1,17 @@
 // Copyright 2025 The Altcoinchain Authors
-// This file contains tests for FUSAKA EIP-7825 transaction gas limit enforcement
+// This file contains tests for FUSAKA transaction pool rules (EIP-7825, EIP-7594)
 
 package core
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -54,3 +56,31 @@ func TestEIP7825TransactionGasLimit(t *testing.T) {
 
 	t.Logf("EIP-7825 constant verified: MaxTransactionGasFUSAKA = %d (0x%x)", params.MaxTransactionGasFUSAKA, params.MaxTransactionGasFUSAKA)
 }
+
+// Tests that blob sidecars must carry cell proofs once FUSAKA is active, and
+// blob proofs before (EIP-7594).
+func TestBlobSidecarVersion(t *testing.T) {
+	sidecar := &types.BlobTxSidecar{
+		Blobs:       make([]kzg4844.Blob, 1),
+		Commitments: make([]kzg4844.Commitment, 1),
+		Proofs:      make([]kzg4844.Proof, 1),
+	}
+	tx := types.NewTx(&types.BlobTx{
+		ChainID:    big.NewInt(2330),
+		GasTipCap:  big.NewInt(1),
+		GasFeeCap:  big.NewInt(1),
+		Gas:        21000,
+		BlobFeeCap: big.NewInt(1),
+		BlobHashes: sidecar.BlobHashes(),
+		Sidecar:    sidecar,
+	})
+	if err := validateBlobTx(tx, true); !errors.Is(err, ErrBlobSidecarVersion) {
+		t.Errorf("blob proofs after FUSAKA: have %v, want %v", err, ErrBlobSidecarVersion)
+	}
+	cells := *sidecar
+	cells.Version = types.BlobSidecarVersion1
+	cells.Proofs = make([]kzg4844.Proof, kzg4844.CellsPerExtBlob)
+	if err := validateBlobTx(tx.WithBlobTxSidecar(&cells), false); !errors.Is(err, ErrBlobSidecarVersion) {
+		t.Errorf("cell proofs before FUSAKA: have %v, want %v", err, ErrBlobSidecarVersion)
+	}
+}
@@ -2419,6 +2419,83 @@ func TestTransactionSlotCount(t *testing.T) {
 	}
 }
 
+func setCodeTx(nonce uint64, key *ecdsa.PrivateKey, auths []types.SetCodeAuthorization) *types.Transaction {
+	return types.MustSignNewTx(key, types.LatestSignerForChainID(params.TestChainConfig.ChainID), &types.SetCodeTx{
+		ChainID:   params.TestChainConfig.ChainID,
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(1),
+		Gas:       100000,
+		To:        common.Address{},
+		Value:     new(big.Int),
+		AuthList:  auths,
+	})
+}
+
+func setCodeAuth(nonce uint64, key *ecdsa.PrivateKey) types.SetCodeAuthorization {
+	auth, _ := types.SignSetCode(key, types.SetCodeAuthorization{
+		ChainID: params.TestChainConfig.ChainID,
+		Address: common.Address{0xaa},
+		Nonce:   nonce,
+	})
+	return auth
+}
+
+// Tests that set code transactions are only accepted after Prague, and that
+// accounts with pending authorizations may only have one transaction in flight.
+func TestTransactionSetCode(t *testing.T) {
+	t.Parallel()
+
+	// Set code transactions are rejected until the fork activates, already by
+	// the pool's signer not recognising them
+	pool, key := setupTxPoolWithConfig(eip1559Config)
+	testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether))
+	if err := pool.AddRemote(setCodeTx(0, key, []types.SetCodeAuthorization{setCodeAuth(0, key)})); !errors.Is(err, ErrInvalidSender) {
+		t.Errorf("pre-Prague error mismatch: have %v, want %v", err, ErrInvalidSender)
+	}
+	pool.Stop()
+
+	config := *eip1559Config
+	config.ShanghaiBlock = common.Big0
+	config.CancunBlock = common.Big0
+	config.PragueBlock = common.Big0
+	pool, keyA := setupTxPoolWithConfig(&config)
+	defer pool.Stop()
+
+	keyB, _ := crypto.GenerateKey()
+	keyC, _ := crypto.GenerateKey()
+	for _, key := range []*ecdsa.PrivateKey{keyA, keyB, keyC} {
+		testAddBalance(pool, crypto.PubkeyToAddress(key.PublicKey), big.NewInt(params.Ether))
+	}
+	if err := pool.AddRemote(setCodeTx(0, keyA, nil)); !errors.Is(err, ErrEmptyAuthList) {
+		t.Errorf("empty authorization list error mismatch: have %v, want %v", err, ErrEmptyAuthList)
+	}
+	// Authorities with pooled transactions can't be used
+	if err := pool.AddRemote(pricedTransaction(0, 100000, big.NewInt(1), keyC)); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.AddRemote(setCodeTx(0, keyA, []types.SetCodeAuthorization{setCodeAuth(0, keyC)})); !errors.Is(err, ErrAuthorityReserved) {
+		t.Errorf("reserved authority error mismatch: have %v, want %v", err, ErrAuthorityReserved)
+	}
+	// Once authorized, an account may only have a single transaction in flight
+	if err := pool.AddRemote(setCodeTx(0, keyA, []types.SetCodeAuthorization{setCodeAuth(0, keyB)})); err != nil {
+		t.Fatalf("failed to add set code transaction: %v", err)
+	}
+	if err := pool.AddRemote(pricedTransaction(0, 100000, big.NewInt(1), keyB)); err != nil {
+		t.Fatalf("failed to add authority transaction: %v", err)
+	}
+	if err := pool.AddRemote(pricedTransaction(1, 100000, big.NewInt(1), keyB)); !errors.Is(err, ErrInflightTxLimitReached) {
+		t.Errorf("in-flight limit error mismatch: have %v, want %v", err, ErrInflightTxLimitReached)
+	}
+	// Replacing the in-flight transaction is still allowed
+	if err := pool.AddRemote(pricedTransaction(0, 100000, big.NewInt(2), keyB)); err != nil {
+		t.Errorf("failed to replace authority transaction: %v", err)
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Benchmarks the speed of validating the contents of the pending queue of the
 // transaction pool.
 func BenchmarkPendingDemotion100(b *testing.B)   { benchmarkPendingDemotion(b, 100) }
@@ -18,6 +18,7 @@ package types
 
 import (
 	"bytes"
+	"encoding/json"
 	"hash"
 	"math/big"
 	"reflect"
@@ -194,6 +195,42 @@ func TestEIP2718BlockEncoding(t *testing.T) {
 	}
 }
 
+// Tests that the data availability commitment of post-Fusaka headers survives
+// the RLP and JSON round trips, and that it's omitted from earlier headers.
+func TestHeaderDACommitmentEncoding(t *testing.T) {
+	commitment := common.HexToHash("0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20")
+	for _, want := range []*Header{
+		{Difficulty: big.NewInt(1), Number: big.NewInt(1), BaseFee: big.NewInt(7)},
+		{Difficulty: big.NewInt(1), Number: big.NewInt(2), BaseFee: big.NewInt(7), DACommitment: &commitment},
+	} {
+		enc, err := rlp.EncodeToBytes(want)
+		if err != nil {
+			t.Fatalf("failed to encode header: %v", err)
+		}
+		var have Header
+		if err := rlp.DecodeBytes(enc, &have); err != nil {
+			t.Fatalf("failed to decode header: %v", err)
+		}
+		if !reflect.DeepEqual(have.DACommitment, want.DACommitment) {
+			t.Errorf("rlp commitment mismatch: have %v, want %v", have.DACommitment, want.DACommitment)
+		}
+		if have.Hash() != want.Hash() {
+			t.Errorf("rlp hash mismatch: have %x, want %x", have.Hash(), want.Hash())
+		}
+		blob, err := json.Marshal(want)
+		if err != nil {
+			t.Fatalf("failed to marshal header: %v", err)
+		}
+		have = Header{}
+		if err := json.Unmarshal(blob, &have); err != nil {
+			t.Fatalf("failed to unmarshal header: %v", err)
+		}
+		if !reflect.DeepEqual(have.DACommitment, want.DACommitment) {
+			t.Errorf("json commitment mismatch: have %v, want %v", have.DACommitment, want.DACommitment)
+		}
+	}
+}
+
 func TestUncleHash(t *testing.T) {
 	uncles := make([]*Header, 0)
 	h := CalcUncleHash(uncles)
@@ -16,23 +16,26 @@ var _ = (*headerMarshaling)(nil)
 // MarshalJSON marshals as JSON.
 func (h Header) MarshalJSON() ([]byte, error) {
 	type Header struct {
-		ParentHash  common.Hash    `json:"parentHash"       gencodec:"required"`
-		UncleHash   common.Hash    `json:"sha3Uncles"       gencodec:"required"`
-		Coinbase    common.Address `json:"miner"`
-		Root        common.Hash    `json:"stateRoot"        gencodec:"required"`
-		TxHash      common.Hash    `json:"transactionsRoot" gencodec:"required"`
-		ReceiptHash common.Hash    `json:"receiptsRoot"     gencodec:"required"`
-		Bloom       Bloom          `json:"logsBloom"        gencodec:"required"`
-		Difficulty  *hexutil.Big   `json:"difficulty"       gencodec:"required"`
-		Number      *hexutil.Big   `json:"number"           gencodec:"required"`
-		GasLimit    hexutil.Uint64 `json:"gasLimit"         gencodec:"required"`
-		GasUsed     hexutil.Uint64 `json:"gasUsed"          gencodec:"required"`
-		Time        hexutil.Uint64 `json:"timestamp"        gencodec:"required"`
-		Extra       hexutil.Bytes  `json:"extraData"        gencodec:"required"`
-		MixDigest   common.Hash    `json:"mixHash"`
-		Nonce       BlockNonce     `json:"nonce"`
-		BaseFee     *hexutil.Big   `json:"baseFeePerGas" rlp:"optional"`
-		Hash        common.Hash    `json:"hash"`
+		ParentHash    common.Hash     `json:"parentHash"       gencodec:"required"`
+		UncleHash     common.Hash     `json:"sha3Uncles"       gencodec:"required"`
+		Coinbase      common.Address  `json:"miner"`
+		Root          common.Hash     `json:"stateRoot"        gencodec:"required"`
+		TxHash        common.Hash     `json:"transactionsRoot" gencodec:"required"`
+		ReceiptHash   common.Hash     `json:"receiptsRoot"     gencodec:"required"`
+		Bloom         Bloom           `json:"logsBloom"        gencodec:"required"`
+		Difficulty    *hexutil.Big    `json:"difficulty"       gencodec:"required"`
+		Number        *hexutil.Big    `json:"number"           gencodec:"required"`
+		GasLimit      hexutil.Uint64  `json:"gasLimit"         gencodec:"required"`
+		GasUsed       hexutil.Uint64  `json:"gasUsed"          gencodec:"required"`
+		Time          hexutil.Uint64  `json:"timestamp"        gencodec:"required"`
+		Extra         hexutil.Bytes   `json:"extraData"        gencodec:"required"`
+		MixDigest     common.Hash     `json:"mixHash"`
+		Nonce         BlockNonce      `json:"nonce"`
+		BaseFee       *hexutil.Big    `json:"baseFeePerGas" rlp:"optional"`
+		BlobGasUsed   *hexutil.Uint64 `json:"blobGasUsed" rlp:"optional"`
+		ExcessBlobGas *hexutil.Uint64 `json:"excessBlobGas" rlp:"optional"`
+		DACommitment  *common.Hash    `json:"daCommitment" rlp:"optional"`
+		Hash          common.Hash     `json:"hash"`
 	}
 	var enc Header
 	enc.ParentHash = h.ParentHash
@@ -51,6 +54,9 @@ func (h Header) MarshalJSON() ([]byte, error) {
 	enc.MixDigest = h.MixDigest
 	enc.Nonce = h.Nonce
 	enc.BaseFee = (*hexutil.Big)(h.BaseFee)
+	enc.BlobGasUsed = (*hexutil.Uint64)(h.BlobGasUsed)
+	enc.ExcessBlobGas = (*hexutil.Uint64)(h.ExcessBlobGas)
+	enc.DACommitment = h.DACommitment
 	enc.Hash = h.Hash()
 	return json.Marshal(&enc)
 }
@@ -58,22 +64,25 @@ func (h Header) MarshalJSON() ([]byte, error) {
 // UnmarshalJSON unmarshals from JSON.
 func (h *Header) UnmarshalJSON(input []byte) error {
 	type Header struct {
-		ParentHash  *common.Hash    `json:"parentHash"       gencodec:"required"`
-		UncleHash   *common.Hash    `json:"sha3Uncles"       gencodec:"required"`
-		Coinbase    *common.Address `json:"miner"`
-		Root        *common.Hash    `json:"stateRoot"        gencodec:"required"`
-		TxHash      *common.Hash    `json:"transactionsRoot" gencodec:"required"`
-		ReceiptHash *common.Hash    `json:"receiptsRoot"     gencodec:"required"`
-		Bloom       *Bloom          `json:"logsBloom"        gencodec:"required"`
-		Difficulty  *hexutil.Big    `json:"difficulty"       gencodec:"required"`
-		Number      *hexutil.Big    `json:"number"           gencodec:"required"`
-		GasLimit    *hexutil.Uint64 `json:"gasLimit"         gencodec:"required"`
-		GasUsed     *hexutil.Uint64 `json:"gasUsed"          gencodec:"required"`
-		Time        *hexutil.Uint64 `json:"timestamp"        gencodec:"required"`
-		Extra       *hexutil.Bytes  `json:"extraData"        gencodec:"required"`
-		MixDigest   *common.Hash    `json:"mixHash"`
-		Nonce       *BlockNonce     `json:"nonce"`
-		BaseFee     *hexutil.Big    `json:"baseFeePerGas" rlp:"optional"`
+		ParentHash    *common.Hash    `json:"parentHash"       gencodec:"required"`
+		UncleHash     *common.Hash    `json:"sha3Uncles"       gencodec:"required"`
+		Coinbase      *common.Address `json:"miner"`
+		Root          *common.Hash    `json:"stateRoot"        gencodec:"required"`
+		TxHash        *common.Hash    `json:"transactionsRoot" gencodec:"required"`
+		ReceiptHash   *common.Hash    `json:"receiptsRoot"     gencodec:"required"`
+		Bloom         *Bloom          `json:"logsBloom"        gencodec:"required"`
+		Difficulty    *hexutil.Big    `json:"difficulty"       gencodec:"required"`
+		Number        *hexutil.Big    `json:"number"           gencodec:"required"`
+		GasLimit      *hexutil.Uint64 `json:"gasLimit"         gencodec:"required"`
+		GasUsed       *hexutil.Uint64 `json:"gasUsed"          gencodec:"required"`
+		Time          *hexutil.Uint64 `json:"timestamp"        gencodec:"required"`
+		Extra         *hexutil.Bytes  `json:"extraData"        gencodec:"required"`
+		MixDigest     *common.Hash    `json:"mixHash"`
+		Nonce         *BlockNonce     `json:"nonce"`
+		BaseFee       *hexutil.Big    `json:"baseFeePerGas" rlp:"optional"`
+		BlobGasUsed   *hexutil.Uint64 `json:"blobGasUsed" rlp:"optional"`
+		ExcessBlobGas *hexutil.Uint64 `json:"excessBlobGas" rlp:"optional"`
+		DACommitment  *common.Hash    `json:"daCommitment" rlp:"optional"`
 	}
 	var dec Header
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -139,5 +148,14 @@ func (h *Header) UnmarshalJSON(input []byte) error {
 	if dec.BaseFee != nil {
 		h.BaseFee = (*big.Int)(dec.BaseFee)
 	}
+	if dec.BlobGasUsed != nil {
+		h.BlobGasUsed = (*uint64)(dec.BlobGasUsed)
+	}
+	if dec.ExcessBlobGas != nil {
+		h.ExcessBlobGas = (*uint64)(dec.ExcessBlobGas)
+	}
+	if dec.DACommitment != nil {
+		h.DACommitment = dec.DACommitment
+	}
 	return nil
 }
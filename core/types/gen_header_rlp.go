@@ -41,7 +41,10 @@ func (obj *Header) EncodeRLP(_w io.Writer) error {
 	w.WriteBytes(obj.MixDigest[:])
 	w.WriteBytes(obj.Nonce[:])
 	_tmp1 := obj.BaseFee != nil
-	if _tmp1 {
+	_tmp2 := obj.BlobGasUsed != nil
+	_tmp3 := obj.ExcessBlobGas != nil
+	_tmp4 := obj.DACommitment != nil
+	if _tmp1 || _tmp2 || _tmp3 || _tmp4 {
 		if obj.BaseFee == nil {
 			w.Write(rlp.EmptyString)
 		} else {
@@ -51,6 +54,27 @@ func (obj *Header) EncodeRLP(_w io.Writer) error {
 			w.WriteBigInt(obj.BaseFee)
 		}
 	}
+	if _tmp2 || _tmp3 || _tmp4 {
+		if obj.BlobGasUsed == nil {
+			w.Write([]byte{0x80})
+		} else {
+			w.WriteUint64((*obj.BlobGasUsed))
+		}
+	}
+	if _tmp3 || _tmp4 {
+		if obj.ExcessBlobGas == nil {
+			w.Write([]byte{0x80})
+		} else {
+			w.WriteUint64((*obj.ExcessBlobGas))
+		}
+	}
+	if _tmp4 {
+		if obj.DACommitment == nil {
+			w.Write([]byte{0x80})
+		} else {
+			w.WriteBytes(obj.DACommitment[:])
+		}
+	}
 	w.ListEnd(_tmp0)
 	return w.Flush()
 }
@@ -0,0 +1,321 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// BlobTx represents an EIP-4844 transaction.
+type BlobTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int // a.k.a. maxPriorityFeePerGas
+	GasFeeCap  *big.Int // a.k.a. maxFeePerGas
+	Gas        uint64
+	To         common.Address
+	Value      *big.Int
+	Data       []byte
+	AccessList AccessList
+	BlobFeeCap *big.Int // a.k.a. maxFeePerBlobGas
+	BlobHashes []common.Hash
+
+	// A blob transaction can optionally contain blobs. This field must be set when BlobTx
+	// is used to create a transaction for signing.
+	Sidecar *BlobTxSidecar `rlp:"-"`
+
+	// Signature values
+	V *big.Int `json:"v" gencodec:"required"`
+	R *big.Int `json:"r" gencodec:"required"`
+	S *big.Int `json:"s" gencodec:"required"`
+}
+
+// Versions of the blob sidecar, differing in the proofs carried.
+const (
+	BlobSidecarVersion0 = byte(0) // One blob proof per blob (EIP-4844)
+	BlobSidecarVersion1 = byte(1) // One proof per cell of the extended blob (EIP-7594)
+)
+
+// BlobTxSidecar contains the blobs of a blob transaction.
+type BlobTxSidecar struct {
+	Version     byte                 // Version of the sidecar, selecting the kind of proofs
+	Blobs       []kzg4844.Blob       // Blobs needed by the blob pool
+	Commitments []kzg4844.Commitment // Commitments needed by the blob pool
+	Proofs      []kzg4844.Proof      // Proofs needed by the blob pool
+}
+
+// BlobHashes computes the blob hashes of the given blobs.
+func (sc *BlobTxSidecar) BlobHashes() []common.Hash {
+	hasher := sha256.New()
+	h := make([]common.Hash, len(sc.Commitments))
+	for i := range sc.Blobs {
+		h[i] = kzg4844.CalcBlobHashV1(hasher, &sc.Commitments[i])
+	}
+	return h
+}
+
+// CellProofs returns the cell proofs of the blob with the given index, nil if
+// the sidecar doesn't carry cell proofs.
+func (sc *BlobTxSidecar) CellProofs(blob int) []kzg4844.Proof {
+	if sc.Version != BlobSidecarVersion1 || len(sc.Proofs) != len(sc.Blobs)*kzg4844.CellsPerExtBlob {
+		return nil
+	}
+	return sc.Proofs[blob*kzg4844.CellsPerExtBlob : (blob+1)*kzg4844.CellsPerExtBlob]
+}
+
+// ValidateBlobs checks that the sidecar carries exactly the blobs committed to
+// by the given versioned hashes, with valid KZG proofs. Version 0 sidecars carry
+// a proof per blob, version 1 ones a proof per cell of each extended blob, all
+// verified in a single batch.
+func (sc *BlobTxSidecar) ValidateBlobs(hashes []common.Hash) error {
+	if len(sc.Blobs) != len(hashes) {
+		return fmt.Errorf("invalid number of %d blobs compared to %d blob hashes", len(sc.Blobs), len(hashes))
+	}
+	if len(sc.Commitments) != len(hashes) {
+		return fmt.Errorf("invalid number of %d blob commitments compared to %d blob hashes", len(sc.Commitments), len(hashes))
+	}
+	proofsPerBlob := 1
+	switch sc.Version {
+	case BlobSidecarVersion0:
+	case BlobSidecarVersion1:
+		proofsPerBlob = kzg4844.CellsPerExtBlob
+	default:
+		return fmt.Errorf("unsupported blob sidecar version %d", sc.Version)
+	}
+	if len(sc.Proofs) != len(hashes)*proofsPerBlob {
+		return fmt.Errorf("invalid number of %d blob proofs compared to %d blob hashes", len(sc.Proofs), len(hashes))
+	}
+	for i, vhash := range sc.BlobHashes() {
+		if vhash != hashes[i] {
+			return fmt.Errorf("blob %d: computed hash %#x mismatches transaction one %#x", i, vhash, hashes[i])
+		}
+	}
+	if sc.Version == BlobSidecarVersion0 {
+		for i := range sc.Blobs {
+			if err := kzg4844.VerifyBlobProof(&sc.Blobs[i], sc.Commitments[i], sc.Proofs[i]); err != nil {
+				return fmt.Errorf("invalid blob %d: %v", i, err)
+			}
+		}
+		return nil
+	}
+	var (
+		commitments = make([]kzg4844.Commitment, 0, len(sc.Proofs))
+		indices     = make([]uint64, 0, len(sc.Proofs))
+		cells       = make([]kzg4844.Cell, 0, len(sc.Proofs))
+	)
+	for i := range sc.Blobs {
+		blobCells, err := kzg4844.ComputeCells(&sc.Blobs[i])
+		if err != nil {
+			return fmt.Errorf("invalid blob %d: %v", i, err)
+		}
+		for j := range blobCells {
+			commitments = append(commitments, sc.Commitments[i])
+			indices = append(indices, uint64(j))
+		}
+		cells = append(cells, blobCells...)
+	}
+	if err := kzg4844.VerifyCellProofBatch(commitments, indices, cells, sc.Proofs); err != nil {
+		return fmt.Errorf("invalid cell proofs: %v", err)
+	}
+	return nil
+}
+
+// blobTxWithBlobs is used for encoding of transactions when blobs are present.
+type blobTxWithBlobs struct {
+	BlobTx      *BlobTx
+	Blobs       []kzg4844.Blob
+	Commitments []kzg4844.Commitment
+	Proofs      []kzg4844.Proof
+}
+
+// blobTxWithBlobsV1 is used for encoding of transactions when blobs with cell
+// proofs are present.
+type blobTxWithBlobsV1 struct {
+	BlobTx      *BlobTx
+	Version     byte
+	Blobs       []kzg4844.Blob
+	Commitments []kzg4844.Commitment
+	Proofs      []kzg4844.Proof
+}
+
+// copy creates a deep copy of the transaction data and initializes all fields.
+func (tx *BlobTx) copy() TxData {
+	cpy := &BlobTx{
+		Nonce: tx.Nonce,
+		To:    tx.To,
+		Data:  common.CopyBytes(tx.Data),
+		Gas:   tx.Gas,
+		// These are copied below.
+		AccessList: make(AccessList, len(tx.AccessList)),
+		BlobHashes: make([]common.Hash, len(tx.BlobHashes)),
+		Value:      new(big.Int),
+		ChainID:    new(big.Int),
+		GasTipCap:  new(big.Int),
+		GasFeeCap:  new(big.Int),
+		BlobFeeCap: new(big.Int),
+		V:          new(big.Int),
+		R:          new(big.Int),
+		S:          new(big.Int),
+	}
+	copy(cpy.AccessList, tx.AccessList)
+	copy(cpy.BlobHashes, tx.BlobHashes)
+
+	if tx.Value != nil {
+		cpy.Value.Set(tx.Value)
+	}
+	if tx.ChainID != nil {
+		cpy.ChainID.Set(tx.ChainID)
+	}
+	if tx.GasTipCap != nil {
+		cpy.GasTipCap.Set(tx.GasTipCap)
+	}
+	if tx.GasFeeCap != nil {
+		cpy.GasFeeCap.Set(tx.GasFeeCap)
+	}
+	if tx.BlobFeeCap != nil {
+		cpy.BlobFeeCap.Set(tx.BlobFeeCap)
+	}
+	if tx.V != nil {
+		cpy.V.Set(tx.V)
+	}
+	if tx.R != nil {
+		cpy.R.Set(tx.R)
+	}
+	if tx.S != nil {
+		cpy.S.Set(tx.S)
+	}
+	if tx.Sidecar != nil {
+		cpy.Sidecar = &BlobTxSidecar{
+			Version:     tx.Sidecar.Version,
+			Blobs:       append([]kzg4844.Blob(nil), tx.Sidecar.Blobs...),
+			Commitments: append([]kzg4844.Commitment(nil), tx.Sidecar.Commitments...),
+			Proofs:      append([]kzg4844.Proof(nil), tx.Sidecar.Proofs...),
+		}
+	}
+	return cpy
+}
+
+// accessors for innerTx.
+func (tx *BlobTx) txType() byte           { return BlobTxType }
+func (tx *BlobTx) chainID() *big.Int      { return tx.ChainID }
+func (tx *BlobTx) accessList() AccessList { return tx.AccessList }
+func (tx *BlobTx) data() []byte           { return tx.Data }
+func (tx *BlobTx) gas() uint64            { return tx.Gas }
+func (tx *BlobTx) gasFeeCap() *big.Int    { return tx.GasFeeCap }
+func (tx *BlobTx) gasTipCap() *big.Int    { return tx.GasTipCap }
+func (tx *BlobTx) gasPrice() *big.Int     { return tx.GasFeeCap }
+func (tx *BlobTx) value() *big.Int        { return tx.Value }
+func (tx *BlobTx) nonce() uint64          { return tx.Nonce }
+func (tx *BlobTx) to() *common.Address    { tmp := tx.To; return &tmp }
+func (tx *BlobTx) blobGas() uint64        { return params.BlobTxBlobGasPerBlob * uint64(len(tx.BlobHashes)) }
+
+func (tx *BlobTx) rawSignatureValues() (v, r, s *big.Int) {
+	return tx.V, tx.R, tx.S
+}
+
+func (tx *BlobTx) setSignatureValues(chainID, v, r, s *big.Int) {
+	tx.ChainID, tx.V, tx.R, tx.S = chainID, v, r, s
+}
+
+// withSidecarEncoding returns the value the transaction is encoded as on the
+// network, wrapping it together with its sidecar.
+func (tx *BlobTx) withSidecarEncoding() interface{} {
+	if tx.Sidecar.Version == BlobSidecarVersion0 {
+		return &blobTxWithBlobs{
+			BlobTx:      tx,
+			Blobs:       tx.Sidecar.Blobs,
+			Commitments: tx.Sidecar.Commitments,
+			Proofs:      tx.Sidecar.Proofs,
+		}
+	}
+	return &blobTxWithBlobsV1{
+		BlobTx:      tx,
+		Version:     tx.Sidecar.Version,
+		Blobs:       tx.Sidecar.Blobs,
+		Commitments: tx.Sidecar.Commitments,
+		Proofs:      tx.Sidecar.Proofs,
+	}
+}
+
+// withoutSidecar returns a copy of the transaction data without the blobs.
+func (tx *BlobTx) withoutSidecar() *BlobTx {
+	cpy := *tx
+	cpy.Sidecar = nil
+	return &cpy
+}
+
+// decode decodes a blob transaction from either its canonical encoding or the
+// network encoding, which wraps the transaction together with its sidecar.
+func (tx *BlobTx) decode(input []byte) error {
+	outerList, _, err := rlp.SplitList(input)
+	if err != nil {
+		return err
+	}
+	firstElemKind, _, _, err := rlp.Split(outerList)
+	if err != nil {
+		return err
+	}
+	if firstElemKind != rlp.List {
+		return rlp.DecodeBytes(input, tx)
+	}
+	// It's a tx with blobs, the version of the sidecar follows the tx unless
+	// it's the original one.
+	_, _, rest, err := rlp.Split(outerList)
+	if err != nil {
+		return err
+	}
+	secondElemKind, _, _, err := rlp.Split(rest)
+	if err != nil {
+		return err
+	}
+	if secondElemKind == rlp.List {
+		var inner blobTxWithBlobs
+		if err := rlp.DecodeBytes(input, &inner); err != nil {
+			return err
+		}
+		*tx = *inner.BlobTx
+		tx.Sidecar = &BlobTxSidecar{
+			Version:     BlobSidecarVersion0,
+			Blobs:       inner.Blobs,
+			Commitments: inner.Commitments,
+			Proofs:      inner.Proofs,
+		}
+		return nil
+	}
+	var inner blobTxWithBlobsV1
+	if err := rlp.DecodeBytes(input, &inner); err != nil {
+		return err
+	}
+	if inner.Version != BlobSidecarVersion1 {
+		return fmt.Errorf("unsupported blob sidecar version %d", inner.Version)
+	}
+	*tx = *inner.BlobTx
+	tx.Sidecar = &BlobTxSidecar{
+		Version:     inner.Version,
+		Blobs:       inner.Blobs,
+		Commitments: inner.Commitments,
+		Proofs:      inner.Proofs,
+	}
+	return nil
+}
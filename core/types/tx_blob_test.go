@@ -0,0 +1,137 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+func newTestBlobTx(t *testing.T) *Transaction {
+	key, _ := crypto.GenerateKey()
+	sidecar := &BlobTxSidecar{
+		Blobs:       []kzg4844.Blob{{0x01}},
+		Commitments: []kzg4844.Commitment{{0xc0}},
+		Proofs:      []kzg4844.Proof{{0xc0}},
+	}
+	tx, err := SignNewTx(key, NewCancunSigner(big.NewInt(1)), &BlobTx{
+		ChainID:    big.NewInt(1),
+		Nonce:      1,
+		GasTipCap:  big.NewInt(1),
+		GasFeeCap:  big.NewInt(10),
+		Gas:        21000,
+		To:         testAddr,
+		Value:      big.NewInt(1),
+		BlobFeeCap: big.NewInt(5),
+		BlobHashes: sidecar.BlobHashes(),
+		Sidecar:    sidecar,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// Tests that blob transactions are encoded with their sidecar on the network
+// but hashed and indexed without it.
+func TestBlobTxEncoding(t *testing.T) {
+	tx := newTestBlobTx(t)
+
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := new(Transaction)
+	if err := dec.UnmarshalBinary(enc); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Hash() != tx.Hash() {
+		t.Errorf("hash mismatch: have %x, want %x", dec.Hash(), tx.Hash())
+	}
+	if dec.BlobTxSidecar() == nil {
+		t.Fatal("sidecar lost in network encoding")
+	}
+	if dec.BlobTxSidecar().Blobs[0] != tx.BlobTxSidecar().Blobs[0] {
+		t.Error("sidecar blob mismatch")
+	}
+	stripped := tx.WithoutBlobTxSidecar()
+	if stripped.BlobTxSidecar() != nil {
+		t.Fatal("sidecar not stripped")
+	}
+	if stripped.Hash() != tx.Hash() {
+		t.Errorf("stripping changed hash: have %x, want %x", stripped.Hash(), tx.Hash())
+	}
+	if stripped.Size() >= tx.Size() {
+		t.Errorf("stripped size %v not below full size %v", stripped.Size(), tx.Size())
+	}
+	if root, want := DeriveSha(Transactions{tx}, newHasher()), DeriveSha(Transactions{stripped}, newHasher()); root != want {
+		t.Errorf("tx root includes sidecar: have %x, want %x", root, want)
+	}
+	from, err := Sender(NewCancunSigner(big.NewInt(1)), dec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := Sender(NewCancunSigner(big.NewInt(1)), tx); from != want {
+		t.Errorf("sender mismatch: have %x, want %x", from, want)
+	}
+	if have := dec.BlobHashes(); len(have) != 1 || have[0] != common.Hash(kzg4844.CalcBlobHashV1(sha256.New(), &kzg4844.Commitment{0xc0})) {
+		t.Errorf("unexpected blob hashes %v", have)
+	}
+}
+
+// Tests that sidecars carrying cell proofs survive the network encoding with
+// their version intact.
+func TestBlobTxEncodingCellProofs(t *testing.T) {
+	tx := newTestBlobTx(t)
+	sidecar := *tx.BlobTxSidecar()
+	sidecar.Version = BlobSidecarVersion1
+	sidecar.Proofs = make([]kzg4844.Proof, kzg4844.CellsPerExtBlob)
+	sidecar.Proofs[kzg4844.CellsPerExtBlob-1][0] = 0xc0
+	tx = tx.WithBlobTxSidecar(&sidecar)
+
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := new(Transaction)
+	if err := dec.UnmarshalBinary(enc); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Hash() != tx.Hash() {
+		t.Errorf("hash mismatch: have %x, want %x", dec.Hash(), tx.Hash())
+	}
+	have := dec.BlobTxSidecar()
+	if have == nil || have.Version != BlobSidecarVersion1 {
+		t.Fatalf("sidecar version lost in network encoding: %v", have)
+	}
+	if proofs := have.CellProofs(0); len(proofs) != kzg4844.CellsPerExtBlob || proofs[kzg4844.CellsPerExtBlob-1][0] != 0xc0 {
+		t.Error("cell proofs mismatch")
+	}
+	// Unknown sidecar versions must be rejected
+	sidecar.Version = 2
+	if enc, err = tx.WithBlobTxSidecar(&sidecar).MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+	if err := new(Transaction).UnmarshalBinary(enc); err == nil {
+		t.Error("sidecar with unknown version decoded")
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that delegation designators are only recognised in their exact form.
+func TestParseDelegation(t *testing.T) {
+	addr := common.Address{0x42}
+	for i, tt := range []struct {
+		code []byte
+		want common.Address
+		ok   bool
+	}{
+		{code: AddressToDelegation(addr), want: addr, ok: true},
+		{code: nil},
+		{code: DelegationPrefix},
+		{code: append(AddressToDelegation(addr), 0x00)},
+		{code: append([]byte{0xef, 0x01, 0x01}, addr.Bytes()...)},
+	} {
+		got, ok := ParseDelegation(tt.code)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("test %d: have (%x, %v), want (%x, %v)", i, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func newTestSetCodeTx(t *testing.T) (*Transaction, common.Address) {
+	key, _ := crypto.GenerateKey()
+	authKey, _ := crypto.GenerateKey()
+	auth, err := SignSetCode(authKey, SetCodeAuthorization{
+		ChainID: big.NewInt(1),
+		Address: common.Address{0xaa},
+		Nonce:   3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := SignNewTx(key, NewPragueSigner(big.NewInt(1)), &SetCodeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     1,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(10),
+		Gas:       50000,
+		To:        testAddr,
+		Value:     big.NewInt(1),
+		AuthList:  []SetCodeAuthorization{auth, auth},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx, crypto.PubkeyToAddress(authKey.PublicKey)
+}
+
+// Tests that set code transactions survive binary and JSON round trips and
+// that the authorities can be recovered from their signatures.
+func TestSetCodeTxEncoding(t *testing.T) {
+	tx, authority := newTestSetCodeTx(t)
+
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc[0] != SetCodeTxType {
+		t.Fatalf("wrong type prefix: have %d, want %d", enc[0], SetCodeTxType)
+	}
+	dec := new(Transaction)
+	if err := dec.UnmarshalBinary(enc); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Hash() != tx.Hash() {
+		t.Errorf("hash mismatch: have %x, want %x", dec.Hash(), tx.Hash())
+	}
+	if have := dec.SetCodeAuthorities(); len(have) != 1 || have[0] != authority {
+		t.Errorf("authorities mismatch: have %x, want [%x]", have, authority)
+	}
+	js, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec = new(Transaction)
+	if err := json.Unmarshal(js, dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Hash() != tx.Hash() {
+		t.Errorf("JSON hash mismatch: have %x, want %x", dec.Hash(), tx.Hash())
+	}
+	if len(dec.SetCodeAuthorizations()) != 2 {
+		t.Errorf("JSON authorization count mismatch: have %d, want 2", len(dec.SetCodeAuthorizations()))
+	}
+}
+
+// Tests that authorizations with tampered fields recover a different
+// authority, and that malleable signatures are rejected outright.
+func TestSetCodeAuthority(t *testing.T) {
+	tx, authority := newTestSetCodeTx(t)
+	auth := tx.SetCodeAuthorizations()[0]
+
+	tampered := auth.copy()
+	tampered.Nonce++
+	if addr, err := tampered.Authority(); err == nil && addr == authority {
+		t.Error("tampered authorization recovered original authority")
+	}
+	malleable := auth.copy()
+	malleable.S = new(big.Int).Sub(crypto.S256().Params().N, auth.S)
+	if _, err := malleable.Authority(); err == nil {
+		t.Error("malleable authorization signature accepted")
+	}
+}
@@ -19,12 +19,16 @@ package vm
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 // precompiledTest defines the input/output pairs for precompiled contract tests.
@@ -52,6 +56,7 @@ var allPrecompiles = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{4}):    &dataCopy{},
 	common.BytesToAddress([]byte{5}):    &bigModExp{eip2565: false},
 	common.BytesToAddress([]byte{0xf5}): &bigModExp{eip2565: true},
+	common.BytesToAddress([]byte{0xf6}): &bigModExp{eip2565: true, eip7823: true, eip7883: true},
 	common.BytesToAddress([]byte{6}):    &bn256AddIstanbul{},
 	common.BytesToAddress([]byte{7}):    &bn256ScalarMulIstanbul{},
 	common.BytesToAddress([]byte{8}):    &bn256PairingIstanbul{},
@@ -65,6 +70,9 @@ var allPrecompiles = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{16}):   &bls12381Pairing{},
 	common.BytesToAddress([]byte{17}):   &bls12381MapG1{},
 	common.BytesToAddress([]byte{18}):   &bls12381MapG2{},
+	common.BytesToAddress([]byte{0xfa}): &kzgPointEvaluation{},
+
+	common.BytesToAddress([]byte{0x01, 0x00}): &p256Verify{},
 }
 
 // EIP-152 test vectors
@@ -238,6 +246,11 @@ func BenchmarkPrecompiledModExp(b *testing.B) { benchJson("modexp", "05", b) }
 func TestPrecompiledModExpEip2565(t *testing.T)      { testJson("modexp_eip2565", "f5", t) }
 func BenchmarkPrecompiledModExpEip2565(b *testing.B) { benchJson("modexp_eip2565", "f5", b) }
 
+func TestPrecompiledModExpEip7883(t *testing.T)      { testJson("modexp_eip7883", "f6", t) }
+func BenchmarkPrecompiledModExpEip7883(b *testing.B) { benchJson("modexp_eip7883", "f6", b) }
+
+func TestPrecompiledModExpEip7823Fail(t *testing.T) { testJsonFail("modexp_eip7823", "f6", t) }
+
 // Tests the sample inputs from the elliptic curve addition EIP 213.
 func TestPrecompiledBn256Add(t *testing.T)      { testJson("bn256Add", "06", t) }
 func BenchmarkPrecompiledBn256Add(b *testing.B) { benchJson("bn256Add", "06", b) }
@@ -272,6 +285,9 @@ func TestPrecompileBlake2FMalformedInput(t *testing.T) {
 
 func TestPrecompiledEcrecover(t *testing.T) { testJson("ecRecover", "01", t) }
 
+func TestPrecompiledP256Verify(t *testing.T)      { testJson("p256Verify", "100", t) }
+func BenchmarkPrecompiledP256Verify(b *testing.B) { benchJson("p256Verify", "100", b) }
+
 func testJson(name, addr string, t *testing.T) {
 	tests, err := loadJson(name)
 	if err != nil {
@@ -391,3 +407,78 @@ func BenchmarkPrecompiledBLS12381G2MultiExpWorstCase(b *testing.B) {
 	}
 	benchmarkPrecompiled("0f", testcase, b)
 }
+
+// Tests the point evaluation precompile against proofs created with the
+// trusted setup of the KZG ceremony.
+func TestPrecompiledPointEvaluation(t *testing.T) {
+	var blob kzg4844.Blob
+	for i := 0; i < 16; i++ {
+		blob[i*kzg4844.BytesPerFieldElement+31] = byte(i + 1)
+	}
+	commitment, err := kzg4844.BlobToCommitment(&blob)
+	if err != nil {
+		t.Fatalf("failed to commit to blob: %v", err)
+	}
+	point := kzg4844.Point{31: 0x42}
+	proof, claim, err := kzg4844.ComputeProof(&blob, point)
+	if err != nil {
+		t.Fatalf("failed to create proof: %v", err)
+	}
+	vhash := kZGToVersionedHash(commitment)
+	input := func(claim kzg4844.Claim) []byte {
+		return common.CopyBytes(bytes.Join([][]byte{vhash[:], point[:], claim[:], commitment[:], proof[:]}, nil))
+	}
+	p := allPrecompiles[common.BytesToAddress([]byte{0xfa})]
+
+	in := input(claim)
+	res, _, err := RunPrecompiledContract(p, in, p.RequiredGas(in))
+	if err != nil {
+		t.Fatalf("failed to verify proof: %v", err)
+	}
+	if common.Bytes2Hex(res) != blobPrecompileReturnValue {
+		t.Errorf("output mismatch: have %x, want %s", res, blobPrecompileReturnValue)
+	}
+	// Check that invalid claims, hashes and inputs are rejected
+	in = input(kzg4844.Claim{31: 0x01})
+	if _, _, err := RunPrecompiledContract(p, in, p.RequiredGas(in)); !errors.Is(err, errBlobVerifyKZGProof) {
+		t.Errorf("invalid claim error mismatch: have %v, want %v", err, errBlobVerifyKZGProof)
+	}
+	in = input(claim)
+	in[0] = 0x02
+	if _, _, err := RunPrecompiledContract(p, in, p.RequiredGas(in)); err != errBlobVerifyMismatchedVersion {
+		t.Errorf("invalid hash error mismatch: have %v, want %v", err, errBlobVerifyMismatchedVersion)
+	}
+	in = input(claim)[:blobVerifyInputLength-1]
+	if _, _, err := RunPrecompiledContract(p, in, p.RequiredGas(in)); err != errBlobVerifyInvalidInputLength {
+		t.Errorf("short input error mismatch: have %v, want %v", err, errBlobVerifyInvalidInputLength)
+	}
+}
+
+// Tests that the P256VERIFY precompile is only active from the Fusaka fork on.
+func TestP256VerifyActivation(t *testing.T) {
+	addr := common.BytesToAddress([]byte{0x01, 0x00})
+
+	config := *params.AllEthashProtocolChanges
+	config.CancunBlock = big.NewInt(0)
+	config.FusakaBlock = big.NewInt(10)
+
+	for _, tt := range []struct {
+		number int64
+		active bool
+	}{
+		{9, false},
+		{10, true},
+	} {
+		evm := NewEVM(BlockContext{BlockNumber: big.NewInt(tt.number)}, TxContext{}, nil, &config, Config{})
+		if _, ok := evm.precompile(addr); ok != tt.active {
+			t.Errorf("block %d: precompile activation mismatch: have %v, want %v", tt.number, ok, tt.active)
+		}
+		var listed bool
+		for _, active := range ActivePrecompiles(evm.chainRules) {
+			listed = listed || active == addr
+		}
+		if listed != tt.active {
+			t.Errorf("block %d: precompile listing mismatch: have %v, want %v", tt.number, listed, tt.active)
+		}
+	}
+}
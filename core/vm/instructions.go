@@ -478,6 +478,11 @@ func opDifficulty(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext)
 }
 
 func opRandom(pc *uint64, interpreter *EVMInterpreter, scope *ScopeContext) ([]byte, error) {
+	// Forks after the merge may activate on a proof-of-work chain, in which
+	// case the opcode keeps returning the block difficulty.
+	if interpreter.evm.Context.Random == nil {
+		return opDifficulty(pc, interpreter, scope)
+	}
 	v := new(uint256.Int).SetBytes(interpreter.evm.Context.Random.Bytes())
 	scope.Stack.push(v)
 	return nil, nil
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -692,3 +693,137 @@ func TestRandom(t *testing.T) {
 		}
 	}
 }
+
+func TestBlobHash(t *testing.T) {
+	type testcase struct {
+		name   string
+		idx    uint64
+		expect common.Hash
+		hashes []common.Hash
+	}
+	var (
+		zero  = common.Hash{0}
+		one   = common.Hash{1}
+		two   = common.Hash{2}
+		three = common.Hash{3}
+	)
+	for _, tt := range []testcase{
+		{name: "[{1}]", idx: 0, expect: one, hashes: []common.Hash{one}},
+		{name: "[1,{2},3]", idx: 2, expect: three, hashes: []common.Hash{one, two, three}},
+		{name: "out-of-bounds (empty)", idx: 10, expect: zero, hashes: []common.Hash{}},
+		{name: "out-of-bounds", idx: 25, expect: zero, hashes: []common.Hash{one, two, three}},
+		{name: "out-of-bounds (nil)", idx: 25, expect: zero, hashes: nil},
+	} {
+		var (
+			env            = NewEVM(BlockContext{}, TxContext{BlobHashes: tt.hashes}, nil, params.TestChainConfig, Config{})
+			stack          = newstack()
+			pc             = uint64(0)
+			evmInterpreter = env.interpreter
+		)
+		stack.push(uint256.NewInt(tt.idx))
+		opBlobHash(&pc, evmInterpreter, &ScopeContext{nil, stack, nil})
+		if len(stack.data) != 1 {
+			t.Errorf("Expected one item on stack after %v, got %d: ", tt.name, len(stack.data))
+		}
+		actual := stack.pop()
+		expected, overflow := uint256.FromBig(new(big.Int).SetBytes(tt.expect.Bytes()))
+		if overflow {
+			t.Errorf("Testcase %v: invalid overflow", tt.name)
+		}
+		if actual.Cmp(expected) != 0 {
+			t.Errorf("Testcase %v: expected  %x, got %x", tt.name, expected, actual)
+		}
+	}
+}
+
+func TestCLZ(t *testing.T) {
+	// Testcases from https://eips.ethereum.org/EIPS/eip-7939#test-cases
+	tests := []struct {
+		x        string
+		expected uint64
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000000", 256},
+		{"8000000000000000000000000000000000000000000000000000000000000000", 0},
+		{"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", 0},
+		{"4000000000000000000000000000000000000000000000000000000000000000", 1},
+		{"7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", 1},
+		{"0000000000000000000000000000000000000000000000000000000000000001", 255},
+	}
+	var (
+		env            = NewEVM(BlockContext{}, TxContext{}, nil, params.TestChainConfig, Config{})
+		stack          = newstack()
+		pc             = uint64(0)
+		evmInterpreter = env.interpreter
+	)
+	for i, test := range tests {
+		x := new(uint256.Int).SetBytes(common.Hex2Bytes(test.x))
+		stack.push(x)
+		opCLZ(&pc, evmInterpreter, &ScopeContext{nil, stack, nil})
+		if len(stack.data) != 1 {
+			t.Errorf("Expected one item on stack after clz, got %d: ", len(stack.data))
+		}
+		if actual := stack.pop(); !actual.Eq(uint256.NewInt(test.expected)) {
+			t.Errorf("Testcase %d, clz(%x): expected %d, got %v", i, test.x, test.expected, actual)
+		}
+	}
+}
+
+func BenchmarkOpCLZ(b *testing.B) {
+	x := "00000000000000000000000000000000000000000000000000000000000000ff"
+	opBenchmark(b, opCLZ, x)
+}
+
+func TestOpMCopy(t *testing.T) {
+	// Testcases from https://eips.ethereum.org/EIPS/eip-5656#test-cases
+	tests := []struct {
+		dst, src, len uint64
+		pre           string
+		want          string
+	}{
+		{ // MCOPY 0 32 32 - copy 32 bytes from offset 32 to offset 0.
+			dst: 0, src: 32, len: 32,
+			pre:  "0000000000000000000000000000000000000000000000000000000000000000 000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+			want: "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f 000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+		},
+		{ // MCOPY 0 0 32 - copy 32 bytes from offset 0 to offset 0.
+			dst: 0, src: 0, len: 32,
+			pre:  "0101010101010101010101010101010101010101010101010101010101010101",
+			want: "0101010101010101010101010101010101010101010101010101010101010101",
+		},
+		{ // MCOPY 0 1 8 - copy 8 bytes from offset 1 to offset 0 (overlapping).
+			dst: 0, src: 1, len: 8,
+			pre:  "000102030405060708 000000000000000000000000000000000000000000000000",
+			want: "010203040506070808 000000000000000000000000000000000000000000000000",
+		},
+		{ // MCOPY 1 0 8 - copy 8 bytes from offset 0 to offset 1 (overlapping).
+			dst: 1, src: 0, len: 8,
+			pre:  "000102030405060708 000000000000000000000000000000000000000000000000",
+			want: "000001020304050607 000000000000000000000000000000000000000000000000",
+		},
+		{ // MCOPY 0 0 0 - zero-length copy is a no-op.
+			dst: 0, src: 0, len: 0,
+			pre:  "0101010101010101010101010101010101010101010101010101010101010101",
+			want: "0101010101010101010101010101010101010101010101010101010101010101",
+		},
+	}
+	for i, tc := range tests {
+		var (
+			env            = NewEVM(BlockContext{}, TxContext{}, nil, params.TestChainConfig, Config{})
+			stack          = newstack()
+			pc             = uint64(0)
+			evmInterpreter = env.interpreter
+			mem            = NewMemory()
+		)
+		data := common.FromHex(strings.ReplaceAll(tc.pre, " ", ""))
+		mem.Resize(uint64(len(data)))
+		mem.Set(0, uint64(len(data)), data)
+
+		stack.push(uint256.NewInt(tc.len))
+		stack.push(uint256.NewInt(tc.src))
+		stack.push(uint256.NewInt(tc.dst))
+		opMcopy(&pc, evmInterpreter, &ScopeContext{mem, stack, nil})
+		if have, want := common.Bytes2Hex(mem.Data()), strings.ReplaceAll(tc.want, " ", ""); have != want {
+			t.Errorf("case %d: have %v, want %v", i, have, want)
+		}
+	}
+}
@@ -39,6 +39,9 @@ type StateDB interface {
 	SetCode(common.Address, []byte)
 	GetCodeSize(common.Address) int
 
+	ResolveCode(common.Address) []byte
+	ResolveCodeHash(common.Address) common.Hash
+
 	AddRefund(uint64)
 	SubRefund(uint64)
 	GetRefund() uint64
@@ -47,9 +50,16 @@ type StateDB interface {
 	GetState(common.Address, common.Hash) common.Hash
 	SetState(common.Address, common.Hash, common.Hash)
 
+	GetTransientState(addr common.Address, key common.Hash) common.Hash
+	SetTransientState(addr common.Address, key, value common.Hash)
+
 	Suicide(common.Address) bool
 	HasSuicided(common.Address) bool
 
+	// Suicide6780 suicides the account only if it was created in the current
+	// transaction (EIP-6780).
+	Suicide6780(common.Address)
+
 	// Exist reports whether the given account exists in state.
 	// Notably this should also return true for suicided accounts.
 	Exist(common.Address) bool
@@ -74,3 +74,48 @@ func TestLoopInterrupt(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the CLZ opcode is only available from the Fusaka fork on.
+func TestFusakaInstructionSet(t *testing.T) {
+	var (
+		address = common.BytesToAddress([]byte("contract"))
+		config  = *params.AllEthashProtocolChanges
+	)
+	config.CancunBlock = big.NewInt(0)
+	config.FusakaBlock = big.NewInt(10)
+
+	// push(1) clz push(0) mstore push(32) push(0) return
+	code := common.Hex2Bytes("60011e60005260206000f3")
+	for _, tt := range []struct {
+		number int64
+		err    error
+	}{
+		{9, &ErrInvalidOpCode{opcode: CLZ}},
+		{10, nil},
+	} {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		statedb.CreateAccount(address)
+		statedb.SetCode(address, code)
+		statedb.Finalise(true)
+
+		vmctx := BlockContext{
+			BlockNumber: big.NewInt(tt.number),
+			Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+		}
+		evm := NewEVM(vmctx, TxContext{}, statedb, &config, Config{})
+
+		ret, _, err := evm.Call(AccountRef(common.Address{}), address, nil, 100000, new(big.Int))
+		if tt.err != nil {
+			if err == nil || err.Error() != tt.err.Error() {
+				t.Errorf("block %d: error mismatch: have %v, want %v", tt.number, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("block %d: call failed: %v", tt.number, err)
+		}
+		if have := new(big.Int).SetBytes(ret); have.Int64() != 255 {
+			t.Errorf("block %d: result mismatch: have %v, want %v", tt.number, have, 255)
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -165,6 +166,7 @@ func makeCallVariantGasCallEIP2929(oldCalculator gasFunc) gasFunc {
 		// The WarmStorageReadCostEIP2929 (100) is already deducted in the form of a constant cost, so
 		// the cost to charge for cold access, if any, is Cold - Warm
 		coldCost := params.ColdAccountAccessCostEIP2929 - params.WarmStorageReadCostEIP2929
+		var eip2929Cost uint64
 		if !warmAccess {
 			evm.StateDB.AddAddressToAccessList(addr)
 			// Charge the remaining difference here already, to correctly calculate available
@@ -172,6 +174,23 @@ func makeCallVariantGasCallEIP2929(oldCalculator gasFunc) gasFunc {
 			if !contract.UseGas(coldCost) {
 				return 0, ErrOutOfGas
 			}
+			eip2929Cost = coldCost
+		}
+		// After Prague, calling into a delegated account (EIP-7702) additionally
+		// charges for accessing the delegation target.
+		var eip7702Cost uint64
+		if evm.chainRules.IsPrague {
+			if target, ok := types.ParseDelegation(evm.StateDB.GetCode(addr)); ok {
+				if evm.StateDB.AddressInAccessList(target) {
+					eip7702Cost = params.WarmStorageReadCostEIP2929
+				} else {
+					evm.StateDB.AddAddressToAccessList(target)
+					eip7702Cost = params.ColdAccountAccessCostEIP2929
+				}
+				if !contract.UseGas(eip7702Cost) {
+					return 0, ErrOutOfGas
+				}
+			}
 		}
 		// Now call the old calculator, which takes into account
 		// - create new account
@@ -179,15 +198,23 @@ func makeCallVariantGasCallEIP2929(oldCalculator gasFunc) gasFunc {
 		// - memory expansion
 		// - 63/64ths rule
 		gas, err := oldCalculator(evm, contract, stack, mem, memorySize)
-		if warmAccess || err != nil {
+		if err != nil {
 			return gas, err
 		}
+		extraCost := eip2929Cost + eip7702Cost
+		if extraCost == 0 {
+			return gas, nil
+		}
 		// In case of a cold access, we temporarily add the cold charge back, and also
 		// add it to the returned gas. By adding it to the return, it will be charged
 		// outside of this function, as part of the dynamic gas, and that will make it
 		// also become correctly reported to tracers.
-		contract.Gas += coldCost
-		return gas + coldCost, nil
+		contract.Gas += extraCost
+		var overflow bool
+		if gas, overflow = math.SafeAdd(gas, extraCost); overflow {
+			return 0, ErrGasUintOverflow
+		}
+		return gas, nil
 	}
 }
 
@@ -53,6 +53,31 @@ func TestG1Serialization(t *testing.T) {
 	}
 }
 
+func TestG1CompressedSerialization(t *testing.T) {
+	g1 := NewG1()
+	for i := 0; i < fuz; i++ {
+		a := g1.rand()
+		b, err := g1.FromCompressed(g1.ToCompressed(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g1.Equal(a, b) {
+			t.Fatal("bad compressed serialization from/to")
+		}
+	}
+	// Generator and infinity in the zcash format
+	if have, want := g1.ToCompressed(g1.One()), common.FromHex("97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"); !bytes.Equal(have, want) {
+		t.Fatalf("bad compressed generator: have %x, want %x", have, want)
+	}
+	zero, err := g1.FromCompressed(g1.ToCompressed(g1.Zero()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g1.IsZero(zero) {
+		t.Fatal("bad compressed serialization of infinity")
+	}
+}
+
 func TestG1IsOnCurve(t *testing.T) {
 	g := NewG1()
 	zero := g.Zero()
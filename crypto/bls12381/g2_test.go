@@ -55,6 +55,32 @@ func TestG2Serialization(t *testing.T) {
 	}
 }
 
+func TestG2CompressedSerialization(t *testing.T) {
+	g2 := NewG2()
+	for i := 0; i < fuz; i++ {
+		a := g2.rand()
+		b, err := g2.FromCompressed(g2.ToCompressed(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g2.Equal(a, b) {
+			t.Fatal("bad compressed serialization from/to")
+		}
+	}
+	// Generator and infinity in the zcash format
+	want := common.FromHex("93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8")
+	if have := g2.ToCompressed(g2.One()); !bytes.Equal(have, want) {
+		t.Fatalf("bad compressed generator: have %x, want %x", have, want)
+	}
+	zero, err := g2.FromCompressed(g2.ToCompressed(g2.Zero()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g2.IsZero(zero) {
+		t.Fatal("bad compressed serialization of infinity")
+	}
+}
+
 func TestG2IsOnCurve(t *testing.T) {
 	g := NewG2()
 	zero := g.Zero()
@@ -0,0 +1,110 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package kzg4844
+
+import (
+	"reflect"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// The cells of a blob are the evaluations of its polynomial over a domain twice
+// the size of the blob domain, split into cosets (EIP-7594). The first half of
+// the cells holds the blob itself, the second half its Reed-Solomon extension,
+// so any half of the cells recovers the blob.
+const (
+	// FieldElementsPerExtBlob is the number of field elements of an extended blob.
+	FieldElementsPerExtBlob = 2 * FieldElementsPerBlob
+
+	// FieldElementsPerCell is the number of field elements a cell consists of.
+	FieldElementsPerCell = FieldElementsPerExtBlob / CellsPerExtBlob
+
+	// CellsPerExtBlob is the number of cells an extended blob is split into.
+	CellsPerExtBlob = goethkzg.CellsPerExtBlob
+
+	// BytesPerCell is the size of a serialized cell.
+	BytesPerCell = goethkzg.BytesPerCell
+)
+
+// Cell is the evaluation of a blob polynomial over a coset of the extended
+// domain.
+type Cell [BytesPerCell]byte
+
+// UnmarshalJSON parses a cell in hex syntax.
+func (c *Cell) UnmarshalJSON(input []byte) error {
+	return hexutil.UnmarshalFixedJSON(cellT, input, c[:])
+}
+
+// MarshalText returns the hex representation of c.
+func (c Cell) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(c[:]).MarshalText()
+}
+
+var cellT = reflect.TypeOf(Cell{})
+
+// ComputeCells returns the cells of the extended blob.
+func ComputeCells(blob *Blob) ([]Cell, error) {
+	cells, err := kzgContext().ComputeCells((*goethkzg.Blob)(blob), 0)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Cell, len(cells))
+	for i, cell := range cells {
+		res[i] = Cell(*cell)
+	}
+	return res, nil
+}
+
+// ComputeCellProofs returns the KZG proofs of all cells of the extended blob,
+// in cell order.
+//
+// Every proof is a commitment of its own, computing them is expensive. They are
+// meant to be computed once by the sender of a blob and verified by everyone
+// else.
+func ComputeCellProofs(blob *Blob) ([]Proof, error) {
+	_, proofs, err := kzgContext().ComputeCellsAndKZGProofs((*goethkzg.Blob)(blob), 0)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Proof, len(proofs))
+	for i, proof := range proofs {
+		res[i] = Proof(proof)
+	}
+	return res, nil
+}
+
+// VerifyCellProofBatch verifies that the cells, given by their index in the
+// extended blob, belong to the blobs committed to, using their KZG proofs. All
+// cells are checked at once with a random linear combination of their proofs.
+func VerifyCellProofBatch(commitments []Commitment, cellIndices []uint64, cells []Cell, proofs []Proof) error {
+	var (
+		kzgCommitments = make([]goethkzg.KZGCommitment, len(commitments))
+		kzgCells       = make([]*goethkzg.Cell, len(cells))
+		kzgProofs      = make([]goethkzg.KZGProof, len(proofs))
+	)
+	for i := range commitments {
+		kzgCommitments[i] = goethkzg.KZGCommitment(commitments[i])
+	}
+	for i := range cells {
+		kzgCells[i] = (*goethkzg.Cell)(&cells[i])
+	}
+	for i := range proofs {
+		kzgProofs[i] = goethkzg.KZGProof(proofs[i])
+	}
+	return kzgContext().VerifyCellKZGProofBatch(kzgCommitments, cellIndices, kzgCells, kzgProofs)
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package kzg4844
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// errInvalidLength is the error of test inputs not fitting the KZG types.
+var errInvalidLength = errors.New("invalid input length")
+
+// Tests that the first half of the cells of a blob holds the blob itself.
+func TestComputeCells(t *testing.T) {
+	blob := randBlob()
+	cells, err := ComputeCells(blob)
+	if err != nil {
+		t.Fatalf("failed to compute cells: %v", err)
+	}
+	if len(cells) != CellsPerExtBlob {
+		t.Fatalf("cell count mismatch: have %d, want %d", len(cells), CellsPerExtBlob)
+	}
+	for i := 0; i < CellsPerExtBlob/2; i++ {
+		if want := blob[i*BytesPerCell : (i+1)*BytesPerCell]; string(cells[i][:]) != string(want) {
+			t.Fatalf("cell %d doesn't match the blob", i)
+		}
+	}
+}
+
+// Tests that cell proofs verify in batch against the blob commitments, and
+// that tampered cells are rejected.
+func TestVerifyCellProofBatch(t *testing.T) {
+	var (
+		commitments []Commitment
+		indices     []uint64
+		cells       []Cell
+		proofs      []Proof
+	)
+	for _, batch := range []struct {
+		blob    *Blob
+		indices []uint64
+	}{
+		{randBlob(), []uint64{0, 65}},
+		{randBlob(), []uint64{127}},
+	} {
+		commitment, err := BlobToCommitment(batch.blob)
+		if err != nil {
+			t.Fatalf("failed to commit to blob: %v", err)
+		}
+		allCells, err := ComputeCells(batch.blob)
+		if err != nil {
+			t.Fatalf("failed to compute cells: %v", err)
+		}
+		allProofs, err := ComputeCellProofs(batch.blob)
+		if err != nil {
+			t.Fatalf("failed to compute cell proofs: %v", err)
+		}
+		for _, index := range batch.indices {
+			commitments = append(commitments, commitment)
+			indices = append(indices, index)
+			cells = append(cells, allCells[index])
+			proofs = append(proofs, allProofs[index])
+		}
+	}
+	if err := VerifyCellProofBatch(commitments, indices, cells, proofs); err != nil {
+		t.Fatalf("failed to verify cell proofs: %v", err)
+	}
+	if err := VerifyCellProofBatch(commitments[:1], indices[:1], cells[:1], proofs[:1]); err != nil {
+		t.Fatalf("failed to verify single cell proof: %v", err)
+	}
+	// Tamper with a cell, its index and the order of the proofs
+	cells[1][BytesPerCell-1] ^= 0x01
+	if err := VerifyCellProofBatch(commitments, indices, cells, proofs); err == nil {
+		t.Fatal("tampered cell verified")
+	}
+	cells[1][BytesPerCell-1] ^= 0x01
+
+	indices[0] = 1
+	if err := VerifyCellProofBatch(commitments, indices, cells, proofs); err == nil {
+		t.Fatal("wrong index verified")
+	}
+	indices[0] = CellsPerExtBlob
+	if err := VerifyCellProofBatch(commitments, indices, cells, proofs); err == nil {
+		t.Fatal("out of range index verified")
+	}
+	indices[0] = 0
+
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	if err := VerifyCellProofBatch(commitments, indices, cells, proofs); err == nil {
+		t.Fatal("swapped proofs verified")
+	}
+	if err := VerifyCellProofBatch(commitments, indices, cells, proofs[:1]); err == nil {
+		t.Fatal("mismatching batch lengths verified")
+	}
+}
+
+// loadCellTests loads the test cases of the consensus specs for the given cell
+// operation of EIP-7594. A null output denotes inputs that must be rejected.
+func loadCellTests(t *testing.T, name string, tests interface{}) {
+	data, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+	if err != nil {
+		t.Fatalf("failed to read test vectors: %v", err)
+	}
+	if err := json.Unmarshal(data, tests); err != nil {
+		t.Fatalf("failed to parse test vectors: %v", err)
+	}
+}
+
+// checkLengths reports whether all hex encoded byte strings have the given size.
+func checkLengths(inputs []hexutil.Bytes, size int) bool {
+	for _, input := range inputs {
+		if len(input) != size {
+			return false
+		}
+	}
+	return true
+}
+
+// Tests the cell computation against the vectors of the consensus specs.
+func TestComputeCellsVectors(t *testing.T) {
+	var tests []struct {
+		Name  string `json:"name"`
+		Input struct {
+			Blob hexutil.Bytes `json:"blob"`
+		} `json:"input"`
+		Output *[2][]hexutil.Bytes `json:"output"`
+	}
+	loadCellTests(t, "compute_cells_and_kzg_proofs", &tests)
+
+	for _, test := range tests {
+		var (
+			blob   Blob
+			cells  []Cell
+			proofs []Proof
+			err    = errInvalidLength
+		)
+		if len(test.Input.Blob) == BlobSize {
+			copy(blob[:], test.Input.Blob)
+			if cells, err = ComputeCells(&blob); err == nil {
+				proofs, err = ComputeCellProofs(&blob)
+			}
+		}
+		if test.Output == nil {
+			if err == nil {
+				t.Errorf("%s: invalid blob accepted", test.Name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: failed to compute cells: %v", test.Name, err)
+			continue
+		}
+		for i, want := range test.Output[0] {
+			if !bytes.Equal(cells[i][:], want) {
+				t.Errorf("%s: cell %d mismatch", test.Name, i)
+			}
+		}
+		for i, want := range test.Output[1] {
+			if !bytes.Equal(proofs[i][:], want) {
+				t.Errorf("%s: proof %d mismatch: have %x, want %x", test.Name, i, proofs[i], want)
+			}
+		}
+	}
+}
+
+// Tests the batch verification of cell proofs against the vectors of the
+// consensus specs.
+func TestVerifyCellProofBatchVectors(t *testing.T) {
+	var tests []struct {
+		Name  string `json:"name"`
+		Input struct {
+			Commitments []hexutil.Bytes `json:"commitments"`
+			CellIndices []uint64        `json:"cell_indices"`
+			Cells       []hexutil.Bytes `json:"cells"`
+			Proofs      []hexutil.Bytes `json:"proofs"`
+		} `json:"input"`
+		Output *bool `json:"output"`
+	}
+	loadCellTests(t, "verify_cell_kzg_proof_batch", &tests)
+
+	for _, test := range tests {
+		err := errInvalidLength
+
+		in := test.Input
+		if checkLengths(in.Commitments, len(Commitment{})) && checkLengths(in.Cells, BytesPerCell) && checkLengths(in.Proofs, len(Proof{})) {
+			var (
+				commitments = make([]Commitment, len(in.Commitments))
+				cells       = make([]Cell, len(in.Cells))
+				proofs      = make([]Proof, len(in.Proofs))
+			)
+			for i := range in.Commitments {
+				copy(commitments[i][:], in.Commitments[i])
+			}
+			for i := range in.Cells {
+				copy(cells[i][:], in.Cells[i])
+			}
+			for i := range in.Proofs {
+				copy(proofs[i][:], in.Proofs[i])
+			}
+			err = VerifyCellProofBatch(commitments, in.CellIndices, cells, proofs)
+		}
+		switch {
+		case test.Output == nil && err == nil:
+			t.Errorf("%s: invalid input accepted", test.Name)
+		case test.Output != nil && *test.Output && err != nil:
+			t.Errorf("%s: failed to verify cell proofs: %v", test.Name, err)
+		case test.Output != nil && !*test.Output && err == nil:
+			t.Errorf("%s: incorrect cell proofs verified", test.Name)
+		}
+	}
+}
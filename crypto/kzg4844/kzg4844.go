@@ -0,0 +1,152 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package kzg4844 implements the KZG crypto for EIP-4844.
+package kzg4844
+
+import (
+	"hash"
+	"reflect"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// FieldElementsPerBlob is the number of field elements a blob consists of.
+	FieldElementsPerBlob = goethkzg.ScalarsPerBlob
+
+	// BytesPerFieldElement is the size of a serialized field element.
+	BytesPerFieldElement = goethkzg.SerializedScalarSize
+
+	// BlobSize is the size of a serialized blob.
+	BlobSize = FieldElementsPerBlob * BytesPerFieldElement
+
+	// versionedHashVersion is the version byte of the hashes committing to a
+	// blob via its KZG commitment.
+	versionedHashVersion = 0x01
+)
+
+// Blob represents a 4844 data blob.
+type Blob [BlobSize]byte
+
+// UnmarshalJSON parses a blob in hex syntax.
+func (b *Blob) UnmarshalJSON(input []byte) error {
+	return hexutil.UnmarshalFixedJSON(blobT, input, b[:])
+}
+
+// MarshalText returns the hex representation of b.
+func (b Blob) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(b[:]).MarshalText()
+}
+
+// Commitment is a serialized commitment to a polynomial.
+type Commitment [48]byte
+
+// UnmarshalJSON parses a commitment in hex syntax.
+func (c *Commitment) UnmarshalJSON(input []byte) error {
+	return hexutil.UnmarshalFixedJSON(commitmentT, input, c[:])
+}
+
+// MarshalText returns the hex representation of c.
+func (c Commitment) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(c[:]).MarshalText()
+}
+
+// Proof is a serialized commitment to the quotient polynomial.
+type Proof [48]byte
+
+// UnmarshalJSON parses a proof in hex syntax.
+func (p *Proof) UnmarshalJSON(input []byte) error {
+	return hexutil.UnmarshalFixedJSON(proofT, input, p[:])
+}
+
+// MarshalText returns the hex representation of p.
+func (p Proof) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(p[:]).MarshalText()
+}
+
+// Point is a BLS field element.
+type Point [32]byte
+
+// Claim is a claimed evaluation value in a specific point.
+type Claim [32]byte
+
+var (
+	blobT       = reflect.TypeOf(Blob{})
+	commitmentT = reflect.TypeOf(Commitment{})
+	proofT      = reflect.TypeOf(Proof{})
+)
+
+// CalcBlobHashV1 calculates the 'versioned blob hash' of a commitment.
+// The given hasher must be a sha256 hash instance, otherwise the result will be invalid!
+func CalcBlobHashV1(hasher hash.Hash, commit *Commitment) (vh [32]byte) {
+	if hasher.Size() != 32 {
+		panic("wrong hash size")
+	}
+	hasher.Reset()
+	hasher.Write(commit[:])
+	hasher.Sum(vh[:0])
+	vh[0] = versionedHashVersion
+	return vh
+}
+
+// IsValidVersionedHash checks that h is a structurally-valid versioned blob hash.
+func IsValidVersionedHash(h []byte) bool {
+	return len(h) == 32 && h[0] == versionedHashVersion
+}
+
+// BlobToCommitment creates a small commitment out of a data blob.
+func BlobToCommitment(blob *Blob) (Commitment, error) {
+	commitment, err := kzgContext().BlobToKZGCommitment((*goethkzg.Blob)(blob), 0)
+	if err != nil {
+		return Commitment{}, err
+	}
+	return Commitment(commitment), nil
+}
+
+// ComputeProof computes the KZG proof at the given point for the polynomial
+// represented by the blob.
+func ComputeProof(blob *Blob, point Point) (Proof, Claim, error) {
+	proof, claim, err := kzgContext().ComputeKZGProof((*goethkzg.Blob)(blob), goethkzg.Scalar(point), 0)
+	if err != nil {
+		return Proof{}, Claim{}, err
+	}
+	return Proof(proof), Claim(claim), nil
+}
+
+// VerifyProof verifies the KZG proof that the polynomial represented by the blob
+// evaluated at the given point is the claimed value.
+func VerifyProof(commitment Commitment, point Point, claim Claim, proof Proof) error {
+	return kzgContext().VerifyKZGProof(goethkzg.KZGCommitment(commitment), goethkzg.Scalar(point), goethkzg.Scalar(claim), goethkzg.KZGProof(proof))
+}
+
+// ComputeBlobProof returns the KZG proof that is used to verify the blob against
+// the commitment.
+//
+// This method does not verify that the commitment is correct with respect to blob.
+func ComputeBlobProof(blob *Blob, commitment Commitment) (Proof, error) {
+	proof, err := kzgContext().ComputeBlobKZGProof((*goethkzg.Blob)(blob), goethkzg.KZGCommitment(commitment), 0)
+	if err != nil {
+		return Proof{}, err
+	}
+	return Proof(proof), nil
+}
+
+// VerifyBlobProof verifies that the blob data corresponds to the provided commitment.
+func VerifyBlobProof(blob *Blob, commitment Commitment, proof Proof) error {
+	return kzgContext().VerifyBlobKZGProof((*goethkzg.Blob)(blob), goethkzg.KZGCommitment(commitment), goethkzg.KZGProof(proof))
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package kzg4844
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func randFieldElement() [32]byte {
+	var r fr.Element
+	if _, err := r.SetRandom(); err != nil {
+		panic("failed to get random field element")
+	}
+	return r.Bytes()
+}
+
+func randBlob() *Blob {
+	var blob Blob
+	for i := 0; i < len(blob); i += BytesPerFieldElement {
+		elem := randFieldElement()
+		copy(blob[i:i+BytesPerFieldElement], elem[:])
+	}
+	return &blob
+}
+
+func TestKZGWithPoint(t *testing.T) {
+	blob := randBlob()
+	commitment, err := BlobToCommitment(blob)
+	if err != nil {
+		t.Fatalf("failed to create KZG commitment from blob: %v", err)
+	}
+	point := randFieldElement()
+	proof, claim, err := ComputeProof(blob, point)
+	if err != nil {
+		t.Fatalf("failed to create KZG proof at point: %v", err)
+	}
+	if err := VerifyProof(commitment, point, claim, proof); err != nil {
+		t.Fatalf("failed to verify KZG proof at point: %v", err)
+	}
+	claim[31] ^= 0x01
+	if err := VerifyProof(commitment, point, claim, proof); err == nil {
+		t.Fatal("invalid claim verified")
+	}
+}
+
+func TestKZGWithBlob(t *testing.T) {
+	blob := randBlob()
+	commitment, err := BlobToCommitment(blob)
+	if err != nil {
+		t.Fatalf("failed to create KZG commitment from blob: %v", err)
+	}
+	proof, err := ComputeBlobProof(blob, commitment)
+	if err != nil {
+		t.Fatalf("failed to create KZG proof for blob: %v", err)
+	}
+	if err := VerifyBlobProof(blob, commitment, proof); err != nil {
+		t.Fatalf("failed to verify KZG proof for blob: %v", err)
+	}
+	blob[31] ^= 0x01
+	if err := VerifyBlobProof(blob, commitment, proof); err == nil {
+		t.Fatal("modified blob verified")
+	}
+	blob[0] = 0xff
+	if err := VerifyBlobProof(blob, commitment, proof); err == nil {
+		t.Fatal("non-canonical blob verified")
+	}
+}
+
+// Tests that proofs of the consensus specs verify against the embedded setup of
+// the KZG ceremony.
+func TestVerifyProofCeremony(t *testing.T) {
+	// verify_kzg_proof_case_correct_proof_26b753dec0560daa
+	var (
+		commitment Commitment
+		point      Point
+		claim      Claim
+		proof      Proof
+	)
+	copy(commitment[:], common.FromHex("0x93efc82d2017e9c57834a1246463e64774e56183bb247c8fc9dd98c56817e878d97b05f5c8d900acf1fbbbca6f146556"))
+	copy(claim[:], common.FromHex("0x73e66878b46ae3705eb6a46a89213de7d3686828bfce5c19400fffff00100001"))
+	copy(proof[:], common.FromHex("0xb82ded761997f2c6f1bb3db1e1dada2ef06d936551667c82f659b75f99d2da2068b81340823ee4e829a93c9fbed7810d"))
+	if err := VerifyProof(commitment, point, claim, proof); err != nil {
+		t.Fatalf("failed to verify proof: %v", err)
+	}
+	claim[31] ^= 0x01
+	if err := VerifyProof(commitment, point, claim, proof); err == nil {
+		t.Fatal("invalid claim verified")
+	}
+}
+
+func TestCalcBlobHashV1(t *testing.T) {
+	var commitment Commitment
+	commitment[0] = 0xc0
+
+	hash := CalcBlobHashV1(sha256.New(), &commitment)
+	if !IsValidVersionedHash(hash[:]) {
+		t.Fatalf("invalid versioned hash: %x", hash)
+	}
+	want := sha256.Sum256(commitment[:])
+	if !bytes.Equal(hash[1:], want[1:]) {
+		t.Fatalf("versioned hash mismatch: have %x, want %x", hash, want)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package kzg4844
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	goethkzg "github.com/crate-crypto/go-eth-kzg"
+)
+
+// content is the output of the KZG ceremony, embedded so every node verifies
+// blobs against the same setup.
+//
+//go:embed trusted_setup.json
+var content []byte
+
+var (
+	context     *goethkzg.Context // KZG context over the ceremony setup, created on first use
+	contextOnce sync.Once
+)
+
+// kzgContext returns the KZG context over the trusted setup of the KZG ceremony,
+// creating it on first use.
+func kzgContext() *goethkzg.Context {
+	contextOnce.Do(func() {
+		setup := new(goethkzg.JSONTrustedSetup)
+		if err := json.Unmarshal(content, setup); err != nil {
+			panic(fmt.Sprintf("kzg4844: invalid embedded trusted setup: %v", err))
+		}
+		ctx, err := goethkzg.NewContext4096(setup)
+		if err != nil {
+			panic(fmt.Sprintf("kzg4844: failed to create KZG context: %v", err))
+		}
+		context = ctx
+	})
+	return context
+}
@@ -18,6 +18,7 @@ package eth
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"errors"
 	"math/big"
 	"time"
@@ -367,6 +368,10 @@ func (b *EthAPIBackend) StartMining(threads int) error {
 	return b.eth.StartMining(threads)
 }
 
+func (b *EthAPIBackend) StartValidating(key *ecdsa.PrivateKey, db ethdb.KeyValueStore) error {
+	return b.eth.StartValidating(key, db)
+}
+
 func (b *EthAPIBackend) StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, checkLive, preferDisk bool) (*state.StateDB, error) {
 	return b.eth.StateAtBlock(block, reexec, base, checkLive, preferDisk)
 }
@@ -18,6 +18,7 @@
 package eth
 
 import (
+	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"math/big"
@@ -32,6 +33,9 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
+	"github.com/ethereum/go-ethereum/consensus/hybrid/validator"
+	"github.com/ethereum/go-ethereum/consensus/peerdas"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -41,6 +45,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
+	"github.com/ethereum/go-ethereum/eth/protocols/att"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -91,6 +96,9 @@ type Ethereum struct {
 	gasPrice  *big.Int
 	etherbase common.Address
 
+	validator *validator.Validator // Built-in hybrid validator client, if enabled
+	peerdas   *peerdas.Protocol    // PeerDAS sample exchange, if Fusaka is scheduled
+
 	networkID     uint64
 	netRPCService *ethapi.NetAPI
 
@@ -220,6 +228,23 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 	eth.bloomIndexer.Start(eth.blockchain)
 
+	// Sample the data of post-Fusaka blocks over the PeerDAS protocol, keeping
+	// the columns custodied by the local node on disk
+	var daValidator *peerdas.DAValidator
+	if chainConfig.FusakaBlock != nil {
+		das := peerdas.NewPeerDAS(chainConfig)
+		eth.peerdas = peerdas.NewProtocol(das)
+		if key := stack.Server().PrivateKey; key != nil {
+			eth.peerdas.SetStore(peerdas.NewSampleStore(chainDb, enode.PubkeyToIDV4(&key.PublicKey), peerdas.CustodyRequirement, peerdas.SampleRetention))
+		}
+		daValidator = peerdas.NewDAValidator(das, eth.peerdas)
+		eth.blockchain.SetDataAvailability(daValidator, config.DAPolicy)
+	}
+	// Track the validator set of the staking contract if hybrid consensus is configured
+	if hy := eth.hybridEngine(); hy != nil {
+		hy.Start(eth.blockchain)
+	}
+
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
@@ -242,12 +267,16 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		EventMux:       eth.eventMux,
 		Checkpoint:     checkpoint,
 		RequiredBlocks: config.RequiredBlocks,
+		Hybrid:         eth.hybridEngine(),
 	}); err != nil {
 		return nil, err
 	}
 
 	eth.miner = miner.New(eth, &config.Miner, chainConfig, eth.EventMux(), eth.engine, eth.isLocalBlock)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
+	if daValidator != nil {
+		eth.miner.SetDAValidator(daValidator)
+	}
 
 	eth.APIBackend = &EthAPIBackend{stack.Config().ExtRPCEnabled(), stack.Config().AllowUnprotectedTxs, eth, nil}
 	if eth.APIBackend.allowUnprotectedTxs {
@@ -471,6 +500,15 @@ func (s *Ethereum) StartMining(threads int) error {
 			}
 			cli.Authorize(eb, wallet.SignData)
 		}
+		// The hybrid engine signs the attestations and slashing evidence it embeds
+		// with the etherbase, blocks can still be mined without them.
+		if hy := s.hybridEngine(); hy != nil {
+			if wallet, err := s.accountManager.Find(accounts.Account{Address: eb}); wallet == nil || err != nil {
+				log.Warn("Etherbase account unavailable locally, not embedding hybrid payload", "err", err)
+			} else {
+				hy.Authorize(eb, wallet.SignTx)
+			}
+		}
 		// If mining is started, we can disable the transaction rejection mechanism
 		// introduced to speed sync times.
 		atomic.StoreUint32(&s.handler.acceptTxs, 1)
@@ -494,6 +532,37 @@ func (s *Ethereum) StopMining() {
 	s.miner.Stop()
 }
 
+// StartValidating starts the built-in hybrid validator client, attesting to
+// every new canonical head with the given key. Signed votes are recorded in
+// the given slashing-protection database.
+func (s *Ethereum) StartValidating(key *ecdsa.PrivateKey, db ethdb.KeyValueStore) error {
+	if s.handler.hybrid == nil {
+		return errors.New("validating requires the hybrid consensus engine")
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.validator != nil {
+		return errors.New("validator already running")
+	}
+	s.validator = validator.New(key, s.blockchain, s.handler.hybrid, validator.NewProtection(db), s.handler.SubmitAttestation)
+	s.validator.Start()
+	return nil
+}
+
+// hybridEngine returns the hybrid PoW/PoS consensus engine if the node runs
+// one, unwrapping the beacon engine, or nil otherwise.
+func (s *Ethereum) hybridEngine() *hybrid.Hybrid {
+	engine := s.engine
+	if b, ok := engine.(*beacon.Beacon); ok {
+		engine = b.InnerEngine()
+	}
+	if hy, ok := engine.(*hybrid.Hybrid); ok {
+		return hy
+	}
+	return nil
+}
+
 func (s *Ethereum) IsMining() bool      { return s.miner.Mining() }
 func (s *Ethereum) Miner() *miner.Miner { return s.miner }
 
@@ -510,6 +579,7 @@ func (s *Ethereum) SetSynced()                         { atomic.StoreUint32(&s.h
 func (s *Ethereum) ArchiveMode() bool                  { return s.config.NoPruning }
 func (s *Ethereum) BloomIndexer() *core.ChainIndexer   { return s.bloomIndexer }
 func (s *Ethereum) Merger() *consensus.Merger          { return s.merger }
+func (s *Ethereum) PeerDAS() *peerdas.Protocol         { return s.peerdas }
 func (s *Ethereum) SyncMode() downloader.SyncMode {
 	mode, _ := s.handler.chainSync.modeAndLocalHead()
 	return mode
@@ -522,6 +592,12 @@ func (s *Ethereum) Protocols() []p2p.Protocol {
 	if s.config.SnapshotCache > 0 {
 		protos = append(protos, snap.MakeProtocols((*snapHandler)(s.handler), s.snapDialCandidates)...)
 	}
+	if s.handler.hybrid != nil {
+		protos = append(protos, att.MakeProtocols((*attHandler)(s.handler))...)
+	}
+	if s.peerdas != nil {
+		protos = append(protos, peerdas.MakeProtocols(s.peerdas)...)
+	}
 	return protos
 }
 
@@ -562,6 +638,11 @@ func (s *Ethereum) Stop() error {
 	close(s.closeBloomHandler)
 	s.txPool.Stop()
 	s.miner.Close()
+	s.lock.RLock()
+	if s.validator != nil {
+		s.validator.Stop()
+	}
+	s.lock.RUnlock()
 	s.blockchain.Stop()
 	s.engine.Close()
 
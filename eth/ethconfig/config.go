@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
@@ -144,6 +145,10 @@ type Config struct {
 	// presence of these blocks for every new peer connection.
 	RequiredBlocks map[uint64]common.Hash `toml:"-"`
 
+	// DAPolicy selects how block import treats post-Fusaka blocks whose data
+	// can't be sampled from the network.
+	DAPolicy core.DataAvailabilityPolicy
+
 	// Light client options
 	LightServ          int  `toml:",omitempty"` // Maximum percentage of time allowed for serving LES requests
 	LightIngress       int  `toml:",omitempty"` // Incoming bandwidth limit for light servers
@@ -234,7 +239,7 @@ func CreateConsensusEngine(stack *node.Node, chainConfig *params.ChainConfig, co
 		case ethash.ModeShared:
 			log.Warn("Ethash used in shared mode")
 		}
-		engine = ethash.New(ethash.Config{
+		ethashConfig := ethash.Config{
 			PowMode:          config.PowMode,
 			CacheDir:         stack.ResolvePath(config.CacheDir),
 			CachesInMem:      config.CachesInMem,
@@ -245,8 +250,18 @@ func CreateConsensusEngine(stack *node.Node, chainConfig *params.ChainConfig, co
 			DatasetsOnDisk:   config.DatasetsOnDisk,
 			DatasetsLockMmap: config.DatasetsLockMmap,
 			NotifyFull:       config.NotifyFull,
-		}, notify, noverify)
-		engine.(*ethash.Ethash).SetThreads(-1) // Disable CPU mining
+		}
+		// If the hybrid PoW/PoS fork is scheduled, wrap ethash into the hybrid
+		// engine. It behaves exactly like ethash before the fork block.
+		if chainConfig.HybridBlock != nil {
+			log.Info("Hybrid PoW/PoS consensus scheduled", "block", chainConfig.HybridBlock)
+			hy := hybrid.New(hybrid.NewConfig(chainConfig.Hybrid), db, ethashConfig, notify, noverify)
+			hy.SetThreads(-1) // Disable CPU mining
+			engine = hy
+		} else {
+			engine = ethash.New(ethashConfig, notify, noverify)
+			engine.(*ethash.Ethash).SetThreads(-1) // Disable CPU mining
+		}
 	}
 	return beacon.New(engine)
 }
@@ -27,18 +27,19 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		NoPrefetch                            bool
 		TxLookupLimit                         uint64                 `toml:",omitempty"`
 		RequiredBlocks                        map[uint64]common.Hash `toml:"-"`
-		LightServ                             int                    `toml:",omitempty"`
-		LightIngress                          int                    `toml:",omitempty"`
-		LightEgress                           int                    `toml:",omitempty"`
-		LightPeers                            int                    `toml:",omitempty"`
-		LightNoPrune                          bool                   `toml:",omitempty"`
-		LightNoSyncServe                      bool                   `toml:",omitempty"`
-		SyncFromCheckpoint                    bool                   `toml:",omitempty"`
-		UltraLightServers                     []string               `toml:",omitempty"`
-		UltraLightFraction                    int                    `toml:",omitempty"`
-		UltraLightOnlyAnnounce                bool                   `toml:",omitempty"`
-		SkipBcVersionCheck                    bool                   `toml:"-"`
-		DatabaseHandles                       int                    `toml:"-"`
+		DAPolicy                              core.DataAvailabilityPolicy
+		LightServ                             int      `toml:",omitempty"`
+		LightIngress                          int      `toml:",omitempty"`
+		LightEgress                           int      `toml:",omitempty"`
+		LightPeers                            int      `toml:",omitempty"`
+		LightNoPrune                          bool     `toml:",omitempty"`
+		LightNoSyncServe                      bool     `toml:",omitempty"`
+		SyncFromCheckpoint                    bool     `toml:",omitempty"`
+		UltraLightServers                     []string `toml:",omitempty"`
+		UltraLightFraction                    int      `toml:",omitempty"`
+		UltraLightOnlyAnnounce                bool     `toml:",omitempty"`
+		SkipBcVersionCheck                    bool     `toml:"-"`
+		DatabaseHandles                       int      `toml:"-"`
 		DatabaseCache                         int
 		DatabaseFreezer                       string
 		TrieCleanCache                        int
@@ -73,6 +74,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.NoPrefetch = c.NoPrefetch
 	enc.TxLookupLimit = c.TxLookupLimit
 	enc.RequiredBlocks = c.RequiredBlocks
+	enc.DAPolicy = c.DAPolicy
 	enc.LightServ = c.LightServ
 	enc.LightIngress = c.LightIngress
 	enc.LightEgress = c.LightEgress
@@ -123,18 +125,19 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		NoPrefetch                            *bool
 		TxLookupLimit                         *uint64                `toml:",omitempty"`
 		RequiredBlocks                        map[uint64]common.Hash `toml:"-"`
-		LightServ                             *int                   `toml:",omitempty"`
-		LightIngress                          *int                   `toml:",omitempty"`
-		LightEgress                           *int                   `toml:",omitempty"`
-		LightPeers                            *int                   `toml:",omitempty"`
-		LightNoPrune                          *bool                  `toml:",omitempty"`
-		LightNoSyncServe                      *bool                  `toml:",omitempty"`
-		SyncFromCheckpoint                    *bool                  `toml:",omitempty"`
-		UltraLightServers                     []string               `toml:",omitempty"`
-		UltraLightFraction                    *int                   `toml:",omitempty"`
-		UltraLightOnlyAnnounce                *bool                  `toml:",omitempty"`
-		SkipBcVersionCheck                    *bool                  `toml:"-"`
-		DatabaseHandles                       *int                   `toml:"-"`
+		DAPolicy                              *core.DataAvailabilityPolicy
+		LightServ                             *int     `toml:",omitempty"`
+		LightIngress                          *int     `toml:",omitempty"`
+		LightEgress                           *int     `toml:",omitempty"`
+		LightPeers                            *int     `toml:",omitempty"`
+		LightNoPrune                          *bool    `toml:",omitempty"`
+		LightNoSyncServe                      *bool    `toml:",omitempty"`
+		SyncFromCheckpoint                    *bool    `toml:",omitempty"`
+		UltraLightServers                     []string `toml:",omitempty"`
+		UltraLightFraction                    *int     `toml:",omitempty"`
+		UltraLightOnlyAnnounce                *bool    `toml:",omitempty"`
+		SkipBcVersionCheck                    *bool    `toml:"-"`
+		DatabaseHandles                       *int     `toml:"-"`
 		DatabaseCache                         *int
 		DatabaseFreezer                       *string
 		TrieCleanCache                        *int
@@ -190,6 +193,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RequiredBlocks != nil {
 		c.RequiredBlocks = dec.RequiredBlocks
 	}
+	if dec.DAPolicy != nil {
+		c.DAPolicy = *dec.DAPolicy
+	}
 	if dec.LightServ != nil {
 		c.LightServ = *dec.LightServ
 	}
@@ -27,11 +27,13 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/forkid"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/fetcher"
+	"github.com/ethereum/go-ethereum/eth/protocols/att"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -39,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/params"
+	lru "github.com/hashicorp/golang-lru"
 )
 
 const (
@@ -87,6 +90,7 @@ type handlerConfig struct {
 	EventMux       *event.TypeMux            // Legacy event mux, deprecate for `feed`
 	Checkpoint     *params.TrustedCheckpoint // Hard coded checkpoint for sync challenges
 	RequiredBlocks map[uint64]common.Hash    // Hard coded map of required block hashes for sync challenges
+	Hybrid         *hybrid.Hybrid            // Hybrid consensus engine to feed attestations into (nil = disabled)
 }
 
 type handler struct {
@@ -117,6 +121,12 @@ type handler struct {
 
 	requiredBlocks map[uint64]common.Hash
 
+	hybrid       *hybrid.Hybrid       // Hybrid consensus engine receiving attestations
+	attestations *lru.Cache           // Recently seen attestations for dedup and serving
+	rejectedAtts *lru.Cache           // Recently rejected attestation hashes, never re-checked
+	attPeers     map[string]*att.Peer // Connected `att` peers
+	attLock      sync.RWMutex
+
 	// channels for fetcher, syncer, txsyncLoop
 	quitSync chan struct{}
 
@@ -141,8 +151,12 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		peers:          newPeerSet(),
 		merger:         config.Merger,
 		requiredBlocks: config.RequiredBlocks,
+		hybrid:         config.Hybrid,
+		attPeers:       make(map[string]*att.Peer),
 		quitSync:       make(chan struct{}),
 	}
+	h.attestations, _ = lru.New(attCacheSize)
+	h.rejectedAtts, _ = lru.New(attCacheSize)
 	if config.Sync == downloader.FullSync {
 		// The database seems empty as the current block is the genesis. Yet the snap
 		// block is ahead, so snap sync was enabled for this node at a certain point.
@@ -0,0 +1,213 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
+	"github.com/ethereum/go-ethereum/eth/protocols/att"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// attCacheSize is the number of recently seen attestations kept around for
+// deduplication and for serving remote requests.
+const attCacheSize = 16384
+
+var (
+	errAttPeerAlreadyRegistered = errors.New("att peer already registered")
+	errHybridNotConfigured      = errors.New("hybrid consensus not configured")
+)
+
+// attPeerInfo represents a short summary of the `att` sub-protocol metadata known
+// about a connected peer.
+type attPeerInfo struct {
+	Version uint `json:"version"` // Attestation protocol version negotiated
+	Score   int  `json:"score"`   // Reputation of the peer
+}
+
+// attHandler implements the att.Backend interface to handle the various network
+// packets that are sent as replies or broadcasts.
+type attHandler handler
+
+// Attestation retrieves a recently seen attestation by hash.
+func (h *attHandler) Attestation(hash common.Hash) *hybrid.Attestation {
+	if cached, ok := h.attestations.Get(hash); ok {
+		return cached.(*hybrid.Attestation)
+	}
+	return nil
+}
+
+// RunPeer is invoked when a peer joins on the `att` protocol.
+func (h *attHandler) RunPeer(peer *att.Peer, hand att.Handler) error {
+	return (*handler)(h).runAttPeer(peer, hand)
+}
+
+// PeerInfo retrieves all known `att` information about a peer.
+func (h *attHandler) PeerInfo(id enode.ID) interface{} {
+	h.attLock.RLock()
+	defer h.attLock.RUnlock()
+
+	if p := h.attPeers[id.String()]; p != nil {
+		return &attPeerInfo{Version: p.Version(), Score: p.Score()}
+	}
+	return nil
+}
+
+// Handle is invoked from a peer's message handler when it receives a new remote
+// message that the handler couldn't consume and serve itself.
+func (h *attHandler) Handle(peer *att.Peer, packet att.Packet) error {
+	switch packet := packet.(type) {
+	case *att.NewAttestationHashesPacket:
+		// Request any announced attestation we haven't seen yet
+		var unknown []common.Hash
+		for _, hash := range *packet {
+			if !h.attestations.Contains(hash) && !h.rejectedAtts.Contains(hash) {
+				unknown = append(unknown, hash)
+			}
+		}
+		if len(unknown) == 0 {
+			return nil
+		}
+		return peer.RequestAttestations(unknown)
+
+	case *att.AttestationsPacket:
+		var accepted []*hybrid.Attestation
+		for _, attestation := range *packet {
+			hash := attestation.Hash()
+			if h.attestations.Contains(hash) || h.rejectedAtts.Contains(hash) {
+				continue
+			}
+			// Forged signatures can never become valid, punish the sender
+			if !attestation.VerifySignature(h.chain.Config().ChainID) {
+				peer.Log().Debug("Invalid attestation signature", "validator", attestation.Validator, "block", attestation.BlockNumber)
+				peer.Adjust(att.InvalidSignaturePenalty)
+				h.rejectedAtts.Add(hash, struct{}{})
+				continue
+			}
+			// Anything else may be caused by a differing local view, drop silently and
+			// only remember the attestation if it can't become valid once caught up
+			if err := h.hybrid.AddAttestation(attestation); err != nil {
+				peer.Log().Trace("Discarded remote attestation", "validator", attestation.Validator, "block", attestation.BlockNumber, "err", err)
+				if permanentAttestationError(err) {
+					h.rejectedAtts.Add(hash, struct{}{})
+				}
+				continue
+			}
+			peer.Adjust(att.UsefulAttestationReward)
+			h.attestations.Add(hash, attestation)
+			accepted = append(accepted, attestation)
+		}
+		(*handler)(h).BroadcastAttestations(accepted)
+		return nil
+
+	default:
+		return fmt.Errorf("unexpected att packet type: %T", packet)
+	}
+}
+
+// permanentAttestationError reports whether the engine rejected an attestation
+// for a reason independent of how far the local chain got: a conflicting vote,
+// or checkpoints, a validator or a stake not matching the attested block. Errors
+// about blocks or states not available locally are transient.
+func permanentAttestationError(err error) bool {
+	return errors.Is(err, hybrid.ErrSlashableAttestation) ||
+		errors.Is(err, hybrid.ErrInvalidCheckpoint) ||
+		errors.Is(err, hybrid.ErrValidatorNotActive) ||
+		errors.Is(err, hybrid.ErrInsufficientStake)
+}
+
+// runAttPeer registers an `att` peer and starts handling inbound messages until
+// the connection is torn down.
+func (h *handler) runAttPeer(peer *att.Peer, handler att.Handler) error {
+	h.peerWG.Add(1)
+	defer h.peerWG.Done()
+
+	h.attLock.Lock()
+	if _, ok := h.attPeers[peer.ID()]; ok {
+		h.attLock.Unlock()
+		return errAttPeerAlreadyRegistered
+	}
+	h.attPeers[peer.ID()] = peer
+	h.attLock.Unlock()
+
+	defer func() {
+		h.attLock.Lock()
+		delete(h.attPeers, peer.ID())
+		h.attLock.Unlock()
+	}()
+	return handler(peer)
+}
+
+// SubmitAttestation adds a locally created attestation to the hybrid engine and
+// propagates it to the network.
+func (h *handler) SubmitAttestation(attestation *hybrid.Attestation) error {
+	if h.hybrid == nil {
+		return errHybridNotConfigured
+	}
+	if err := h.hybrid.AddAttestation(attestation); err != nil {
+		return err
+	}
+	h.attestations.Add(attestation.Hash(), attestation)
+	h.BroadcastAttestations([]*hybrid.Attestation{attestation})
+	return nil
+}
+
+// BroadcastAttestations will propagate a batch of attestations
+// - To a square root of all `att` peers
+// - And, separately, as announcements to all peers which are not known to
+// already have the given attestation.
+func (h *handler) BroadcastAttestations(atts []*hybrid.Attestation) {
+	if len(atts) == 0 {
+		return
+	}
+	var (
+		direct = make(map[*att.Peer][]*hybrid.Attestation)
+		annos  = make(map[*att.Peer][]common.Hash)
+	)
+	h.attLock.RLock()
+	for _, attestation := range atts {
+		hash := attestation.Hash()
+
+		var peers []*att.Peer
+		for _, p := range h.attPeers {
+			if !p.KnownAttestation(hash) {
+				peers = append(peers, p)
+			}
+		}
+		numDirect := int(math.Sqrt(float64(len(peers))))
+		for _, p := range peers[:numDirect] {
+			direct[p] = append(direct[p], attestation)
+		}
+		for _, p := range peers[numDirect:] {
+			annos[p] = append(annos[p], hash)
+		}
+	}
+	h.attLock.RUnlock()
+
+	for p, batch := range direct {
+		p.AsyncSendAttestations(batch)
+	}
+	for p, hashes := range annos {
+		p.AsyncSendAttestationHashes(hashes)
+	}
+	log.Trace("Attestation broadcast", "attestations", len(atts), "direct peers", len(direct), "announce peers", len(annos))
+}
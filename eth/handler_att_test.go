@@ -0,0 +1,111 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/protocols/att"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that an attestation rejected once is remembered, so a peer resending it
+// can't make the node verify it over and over.
+func TestRejectedAttestationsRemembered(t *testing.T) {
+	th := newTestHandler()
+	defer th.close()
+	h := th.handler
+
+	// Sign with one key but claim to be another validator
+	key, _ := crypto.GenerateKey()
+	checkpoint := hybrid.Checkpoint{Epoch: hybrid.EpochOf(1)}
+	forged := hybrid.NewAttestation(common.Address{0xff}, common.Hash{0x01}, 1, checkpoint, checkpoint)
+	if err := forged.Sign(key, params.TestChainConfig.ChainID); err != nil {
+		t.Fatalf("failed to sign attestation: %v", err)
+	}
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	peer := att.NewFakePeer(att.ATT1, "0123456789abcdef", app)
+	defer peer.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := (*attHandler)(h).Handle(peer, &att.AttestationsPacket{forged}); err != nil {
+			t.Fatalf("attempt %d: failed to handle attestations: %v", i, err)
+		}
+	}
+	if have, want := peer.Score(), att.InvalidSignaturePenalty; have != want {
+		t.Fatalf("peer score mismatch: have %d, want %d", have, want)
+	}
+	if !h.rejectedAtts.Contains(forged.Hash()) {
+		t.Fatalf("forged attestation not remembered as rejected")
+	}
+}
+
+// Tests that only attestations which can never become valid are remembered as
+// rejected, while those failing against the local view stay retryable.
+func TestTransientAttestationErrorsRetried(t *testing.T) {
+	th := newTestHandler()
+	defer th.close()
+	h := th.handler
+
+	engine := hybrid.New(hybrid.NewConfig(nil), rawdb.NewMemoryDatabase(), ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+	defer engine.Close()
+	engine.Start(th.chain)
+	h.hybrid = engine
+
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	peer := att.NewFakePeer(att.ATT1, "0123456789abcdef", app)
+	defer peer.Close()
+
+	key, _ := crypto.GenerateKey()
+	sign := func(hash common.Hash, number uint64) *hybrid.Attestation {
+		checkpoint := hybrid.Checkpoint{Epoch: hybrid.EpochOf(number), Hash: hash}
+		attestation := hybrid.NewAttestation(crypto.PubkeyToAddress(key.PublicKey), hash, number, checkpoint, checkpoint)
+		if err := attestation.Sign(key, params.TestChainConfig.ChainID); err != nil {
+			t.Fatalf("failed to sign attestation: %v", err)
+		}
+		return attestation
+	}
+	// An attestation for a block not imported yet may become valid later
+	unknown := sign(common.Hash{0x01}, 1)
+	if err := (*attHandler)(h).Handle(peer, &att.AttestationsPacket{unknown}); err != nil {
+		t.Fatalf("failed to handle attestations: %v", err)
+	}
+	if h.rejectedAtts.Contains(unknown.Hash()) {
+		t.Errorf("attestation for unknown block remembered as rejected")
+	}
+	// An attestation from a non-validator at a known block can't
+	genesis := th.chain.Genesis()
+	outsider := sign(genesis.Hash(), 0)
+	if err := (*attHandler)(h).Handle(peer, &att.AttestationsPacket{outsider}); err != nil {
+		t.Fatalf("failed to handle attestations: %v", err)
+	}
+	if !h.rejectedAtts.Contains(outsider.Hash()) {
+		t.Errorf("attestation from non-validator not remembered as rejected")
+	}
+}
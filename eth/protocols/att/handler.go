@@ -0,0 +1,201 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package att
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+const (
+	// maxAttestationsServe is the maximum number of attestations to serve in a
+	// single reply. This number is there to limit the number of lookups.
+	maxAttestationsServe = 1024
+
+	// maxAttestationAnnounces is the maximum number of attestation hashes a peer
+	// may announce in a single message.
+	maxAttestationAnnounces = 4096
+)
+
+// Handler is a callback to invoke from an outside runner after the boilerplate
+// exchanges have passed.
+type Handler func(peer *Peer) error
+
+// Backend defines the data retrieval methods to serve remote requests and the
+// callback methods to invoke on remote deliveries.
+type Backend interface {
+	// Attestation retrieves a locally known attestation by its hash, returning
+	// nil if it is not available.
+	Attestation(hash common.Hash) *hybrid.Attestation
+
+	// RunPeer is invoked when a peer joins on the `att` protocol. The handler
+	// should do any peer maintenance work, handshakes and validations. If all
+	// is passed, control should be given back to the `handler` to process the
+	// inbound messages going forward.
+	RunPeer(peer *Peer, handler Handler) error
+
+	// PeerInfo retrieves all known `att` information about a peer.
+	PeerInfo(id enode.ID) interface{}
+
+	// Handle is a callback to be invoked when a data packet is received from
+	// the remote peer. Only packets not consumed by the protocol handler will
+	// be forwarded to the backend.
+	Handle(peer *Peer, packet Packet) error
+}
+
+// MakeProtocols constructs the P2P protocol definitions for `att`.
+func MakeProtocols(backend Backend) []p2p.Protocol {
+	protocols := make([]p2p.Protocol, len(ProtocolVersions))
+	for i, version := range ProtocolVersions {
+		version := version // Closure
+
+		protocols[i] = p2p.Protocol{
+			Name:    ProtocolName,
+			Version: version,
+			Length:  protocolLengths[version],
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				peer := NewPeer(version, p, rw)
+				defer peer.Close()
+
+				return backend.RunPeer(peer, func(peer *Peer) error {
+					return Handle(backend, peer)
+				})
+			},
+			PeerInfo: func(id enode.ID) interface{} {
+				return backend.PeerInfo(id)
+			},
+			Attributes: []enr.Entry{&enrEntry{}},
+		}
+	}
+	return protocols
+}
+
+// Handle is the callback invoked to manage the life cycle of an `att` peer.
+// When this function terminates, the peer is disconnected.
+func Handle(backend Backend, peer *Peer) error {
+	for {
+		if err := HandleMessage(backend, peer); err != nil {
+			peer.Log().Debug("Message handling failed in `att`", "err", err)
+			return err
+		}
+	}
+}
+
+// HandleMessage is invoked whenever an inbound message is received from a
+// remote peer on the `att` protocol. The remote connection is torn down upon
+// returning any error.
+func HandleMessage(backend Backend, peer *Peer) error {
+	// Read the next message from the remote peer, and ensure it's fully consumed
+	msg, err := peer.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > maxMessageSize {
+		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
+	}
+	defer msg.Discard()
+	start := time.Now()
+	// Track the emount of time it takes to serve the request and run the handler
+	if metrics.Enabled {
+		h := fmt.Sprintf("%s/%s/%d/%#02x", p2p.HandleHistName, ProtocolName, peer.Version(), msg.Code)
+		defer func(start time.Time) {
+			sampler := func() metrics.Sample {
+				return metrics.ResettingSample(
+					metrics.NewExpDecaySample(1028, 0.015),
+				)
+			}
+			metrics.GetOrRegisterHistogramLazy(h, nil, sampler).Update(time.Since(start).Microseconds())
+		}(start)
+	}
+	// Handle the message depending on its contents
+	switch {
+	case msg.Code == NewAttestationHashesMsg:
+		// New attestations were announced, make sure we don't send them back
+		ann := new(NewAttestationHashesPacket)
+		if err := msg.Decode(ann); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		if len(*ann) > maxAttestationAnnounces {
+			return fmt.Errorf("%w: %d announcements", errDecode, len(*ann))
+		}
+		peer.markAttestations(*ann...)
+		if err := backend.Handle(peer, ann); err != nil {
+			return err
+		}
+
+	case msg.Code == GetAttestationsMsg:
+		// Decode the attestation retrieval request
+		var req GetAttestationsPacket
+		if err := msg.Decode(&req); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		// Service the request, skipping anything we don't know about
+		atts := ServiceGetAttestationsQuery(backend, req)
+		return p2p.Send(peer.rw, AttestationsMsg, atts)
+
+	case msg.Code == AttestationsMsg:
+		// Attestations arrived, either broadcast or as a reply to a request
+		atts := new(AttestationsPacket)
+		if err := msg.Decode(atts); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		for i, att := range *atts {
+			if att == nil {
+				return fmt.Errorf("%w: attestation %d is nil", errDecode, i)
+			}
+			peer.markAttestations(att.Hash())
+		}
+		// Discard the batch if the peer is flooding us
+		if !peer.allow(len(*atts)) {
+			peer.Log().Debug("Attestation rate limit exceeded", "count", len(*atts))
+			peer.Adjust(RateLimitPenalty)
+			break
+		}
+		if err := backend.Handle(peer, atts); err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
+	}
+	if score := peer.Score(); score <= minScore {
+		return fmt.Errorf("%w: %d", errMisbehaving, score)
+	}
+	return nil
+}
+
+// ServiceGetAttestationsQuery assembles the response to an attestation query.
+// It is exposed to allow external packages to test protocol behavior.
+func ServiceGetAttestationsQuery(backend Backend, query GetAttestationsPacket) AttestationsPacket {
+	atts := make(AttestationsPacket, 0, len(query))
+	for i, hash := range query {
+		if i >= maxAttestationsServe {
+			break
+		}
+		if att := backend.Attestation(hash); att != nil {
+			atts = append(atts, att)
+		}
+	}
+	return atts
+}
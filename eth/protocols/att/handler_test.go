@@ -0,0 +1,174 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package att
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// testBackend is a mock implementation of the att.Backend interface which
+// records delivered packets and serves a fixed set of attestations.
+type testBackend struct {
+	known     map[common.Hash]*hybrid.Attestation
+	delivered []Packet
+}
+
+func (b *testBackend) Attestation(hash common.Hash) *hybrid.Attestation { return b.known[hash] }
+func (b *testBackend) RunPeer(peer *Peer, handler Handler) error        { return handler(peer) }
+func (b *testBackend) PeerInfo(id enode.ID) interface{}                 { return nil }
+
+func (b *testBackend) Handle(peer *Peer, packet Packet) error {
+	b.delivered = append(b.delivered, packet)
+	if atts, ok := packet.(*AttestationsPacket); ok {
+		for _, att := range *atts {
+			if !att.VerifySignature(common.Big1) {
+				peer.Adjust(InvalidSignaturePenalty)
+			}
+		}
+	}
+	return nil
+}
+
+func makeAttestation(t *testing.T, number uint64) *hybrid.Attestation {
+	key, _ := crypto.GenerateKey()
+	checkpoint := hybrid.Checkpoint{Epoch: hybrid.EpochOf(number)}
+	att := hybrid.NewAttestation(crypto.PubkeyToAddress(key.PublicKey), common.Hash{byte(number)}, number, checkpoint, checkpoint)
+	if err := att.Sign(key, common.Big1); err != nil {
+		t.Fatalf("failed to sign attestation: %v", err)
+	}
+	return att
+}
+
+// Tests that attestation queries are served from the backend, skipping any
+// unknown hashes.
+func TestGetAttestations(t *testing.T) {
+	att := makeAttestation(t, 1)
+	backend := &testBackend{known: map[common.Hash]*hybrid.Attestation{att.Hash(): att}}
+
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	peer := NewFakePeer(ATT1, "0123456789abcdef", app)
+	defer peer.Close()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- HandleMessage(backend, peer)
+	}()
+	if err := p2p.Send(net, GetAttestationsMsg, GetAttestationsPacket{att.Hash(), {0xff}}); err != nil {
+		t.Fatalf("failed to send query: %v", err)
+	}
+	if err := p2p.ExpectMsg(net, AttestationsMsg, AttestationsPacket{att}); err != nil {
+		t.Fatalf("response mismatch: %v", err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("failed to handle query: %v", err)
+	}
+}
+
+// Tests that delivered attestations are marked as known and forwarded to the
+// backend, and that peers flooding the node get rate limited.
+func TestAttestationRateLimit(t *testing.T) {
+	backend := new(testBackend)
+
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	peer := NewFakePeer(ATT1, "0123456789abcdef", app)
+	defer peer.Close()
+
+	batch := make(AttestationsPacket, attestationBurst)
+	for i := range batch {
+		batch[i] = makeAttestation(t, uint64(i))
+	}
+	// The first full burst is accepted
+	go p2p.Send(net, AttestationsMsg, batch)
+	if err := HandleMessage(backend, peer); err != nil {
+		t.Fatalf("failed to handle attestations: %v", err)
+	}
+	if len(backend.delivered) != 1 {
+		t.Fatalf("delivered packet count mismatch: have %d, want %d", len(backend.delivered), 1)
+	}
+	if !peer.KnownAttestation(batch[0].Hash()) {
+		t.Errorf("delivered attestation not marked as known")
+	}
+	// A second burst right after exceeds the allowance and gets discarded
+	go p2p.Send(net, AttestationsMsg, batch)
+	if err := HandleMessage(backend, peer); err != nil {
+		t.Fatalf("failed to handle attestations: %v", err)
+	}
+	if len(backend.delivered) != 1 {
+		t.Errorf("rate limited packet delivered")
+	}
+	if have := peer.Score(); have != RateLimitPenalty {
+		t.Errorf("score mismatch: have %d, want %d", have, RateLimitPenalty)
+	}
+}
+
+// Tests that peers repeatedly sending forged attestations get disconnected.
+func TestInvalidSignatureDisconnect(t *testing.T) {
+	backend := new(testBackend)
+
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	peer := NewFakePeer(ATT1, "0123456789abcdef", app)
+	defer peer.Close()
+
+	forged := makeAttestation(t, 1)
+	forged.BlockNumber = 2
+
+	for i := 0; ; i++ {
+		go p2p.Send(net, AttestationsMsg, AttestationsPacket{forged})
+		err := HandleMessage(backend, peer)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, errMisbehaving) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := minScore / InvalidSignaturePenalty; i+1 != want {
+			t.Errorf("disconnected after %d messages, want %d", i+1, want)
+		}
+		break
+	}
+}
+
+// Tests that peers with identifiers shorter than the logged prefix can be
+// created, as is common for fake test peers.
+func TestShortPeerID(t *testing.T) {
+	app, net := p2p.MsgPipe()
+	defer app.Close()
+	defer net.Close()
+
+	peer := NewFakePeer(ATT1, "ab", app)
+	defer peer.Close()
+
+	if peer.ID() != "ab" {
+		t.Fatalf("peer id mismatch: have %s, want ab", peer.ID())
+	}
+}
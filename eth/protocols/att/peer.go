@@ -0,0 +1,253 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package att
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	mapset "github.com/deckarep/golang-set"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+const (
+	// maxKnownAttestations is the maximum attestation hashes to keep in the known
+	// list before starting to randomly evict them.
+	maxKnownAttestations = 32768
+
+	// maxQueuedAttestations is the maximum number of attestation batches to queue
+	// up before dropping broadcasts.
+	maxQueuedAttestations = 128
+
+	// attestationRate is the number of attestations per second a peer may deliver
+	// before its messages are discarded.
+	attestationRate = 256
+
+	// attestationBurst is the maximum number of attestations a peer may deliver
+	// at once after being idle.
+	attestationBurst = 1024
+)
+
+// Score adjustments applied to peers depending on their behaviour.
+const (
+	// InvalidSignaturePenalty is applied for each attestation with a signature
+	// not matching its validator.
+	InvalidSignaturePenalty = -20
+
+	// RateLimitPenalty is applied whenever a peer exceeds its attestation rate.
+	RateLimitPenalty = -5
+
+	// UsefulAttestationReward is applied for each new valid attestation received.
+	UsefulAttestationReward = 1
+
+	// minScore is the score at which a peer gets disconnected.
+	minScore = -100
+
+	// maxScore caps the score a peer can build up by being useful.
+	maxScore = 100
+)
+
+// Peer is a collection of relevant information we have about an `att` peer.
+type Peer struct {
+	id string // Unique ID for the peer, cached
+
+	*p2p.Peer                   // The embedded P2P package peer
+	rw        p2p.MsgReadWriter // Input/output streams for att
+	version   uint              // Protocol version negotiated
+
+	known    mapset.Set                 // Set of attestation hashes known to be known by this peer
+	queue    chan []*hybrid.Attestation // Queue of attestations to broadcast to the peer
+	announce chan []common.Hash         // Queue of attestation hashes to announce to the peer
+	term     chan struct{}              // Termination channel to stop the broadcasters
+
+	score      int       // Reputation of the peer, disconnected below minScore
+	tokens     float64   // Attestations the peer may currently deliver
+	lastRefill time.Time // Time the token bucket was last refilled
+	lock       sync.Mutex
+
+	logger log.Logger // Contextual logger with the peer id injected
+}
+
+// NewPeer create a wrapper for a network connection and negotiated protocol
+// version.
+func NewPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
+	return newPeer(version, p.ID().String(), p, rw)
+}
+
+// NewFakePeer create a fake att peer without a backing p2p peer, for testing purposes.
+func NewFakePeer(version uint, id string, rw p2p.MsgReadWriter) *Peer {
+	return newPeer(version, id, nil, rw)
+}
+
+func newPeer(version uint, id string, p *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
+	logid := id
+	if len(logid) > 8 {
+		logid = logid[:8]
+	}
+	peer := &Peer{
+		id:         id,
+		Peer:       p,
+		rw:         rw,
+		version:    version,
+		known:      mapset.NewSet(),
+		queue:      make(chan []*hybrid.Attestation, maxQueuedAttestations),
+		announce:   make(chan []common.Hash, maxQueuedAttestations),
+		term:       make(chan struct{}),
+		tokens:     attestationBurst,
+		lastRefill: time.Now(),
+		logger:     log.New("peer", logid),
+	}
+	go peer.broadcast()
+	return peer
+}
+
+// Close signals the broadcast goroutine to terminate. Only ever call this if
+// you created the peer yourself via NewPeer. Otherwise let whoever created it
+// clean it up!
+func (p *Peer) Close() {
+	close(p.term)
+}
+
+// ID retrieves the peer's unique identifier.
+func (p *Peer) ID() string {
+	return p.id
+}
+
+// Version retrieves the peer's negotiated `att` protocol version.
+func (p *Peer) Version() uint {
+	return p.version
+}
+
+// Log overrides the P2P logger with the higher level one containing only the id.
+func (p *Peer) Log() log.Logger {
+	return p.logger
+}
+
+// KnownAttestation returns whether the peer is known to already have an
+// attestation.
+func (p *Peer) KnownAttestation(hash common.Hash) bool {
+	return p.known.Contains(hash)
+}
+
+// markAttestations marks a batch of attestations as known for the peer,
+// ensuring that they will never be propagated to this particular peer.
+func (p *Peer) markAttestations(hashes ...common.Hash) {
+	for p.known.Cardinality() > maxKnownAttestations-len(hashes) && p.known.Cardinality() > 0 {
+		p.known.Pop()
+	}
+	for _, hash := range hashes {
+		p.known.Add(hash)
+	}
+}
+
+// Score returns the current reputation of the peer.
+func (p *Peer) Score() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.score
+}
+
+// Adjust changes the reputation of the peer by delta, capped at maxScore.
+func (p *Peer) Adjust(delta int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.score += delta
+	if p.score > maxScore {
+		p.score = maxScore
+	}
+}
+
+// allow reports whether the peer may deliver n more attestations without
+// exceeding its rate limit, consuming the allowance if so.
+func (p *Peer) allow(n int) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := time.Now()
+	p.tokens = math.Min(attestationBurst, p.tokens+now.Sub(p.lastRefill).Seconds()*attestationRate)
+	p.lastRefill = now
+
+	if float64(n) > p.tokens {
+		return false
+	}
+	p.tokens -= float64(n)
+	return true
+}
+
+// AsyncSendAttestations queues a batch of attestations for propagation to the
+// remote peer. If the peer's broadcast queue is full, the attestations are
+// silently dropped.
+func (p *Peer) AsyncSendAttestations(atts []*hybrid.Attestation) {
+	select {
+	case p.queue <- atts:
+		hashes := make([]common.Hash, len(atts))
+		for i, att := range atts {
+			hashes[i] = att.Hash()
+		}
+		p.markAttestations(hashes...)
+	default:
+		p.Log().Debug("Dropping attestation propagation", "count", len(atts))
+	}
+}
+
+// AsyncSendAttestationHashes queues a batch of attestation hashes for
+// announcement to the remote peer. If the peer's announcement queue is full,
+// the hashes are silently dropped.
+func (p *Peer) AsyncSendAttestationHashes(hashes []common.Hash) {
+	select {
+	case p.announce <- hashes:
+		p.markAttestations(hashes...)
+	default:
+		p.Log().Debug("Dropping attestation announcement", "count", len(hashes))
+	}
+}
+
+// RequestAttestations fetches a batch of attestations from the remote peer.
+func (p *Peer) RequestAttestations(hashes []common.Hash) error {
+	p.Log().Debug("Fetching batch of attestations", "count", len(hashes))
+	return p2p.Send(p.rw, GetAttestationsMsg, GetAttestationsPacket(hashes))
+}
+
+// broadcast is a write loop that multiplexes attestations and announcements
+// to the remote peer. The goal is to have an async writer that does not lock
+// up the message handlers.
+func (p *Peer) broadcast() {
+	for {
+		select {
+		case atts := <-p.queue:
+			if err := p2p.Send(p.rw, AttestationsMsg, AttestationsPacket(atts)); err != nil {
+				return
+			}
+			p.Log().Trace("Propagated attestations", "count", len(atts))
+
+		case hashes := <-p.announce:
+			if err := p2p.Send(p.rw, NewAttestationHashesMsg, NewAttestationHashesPacket(hashes)); err != nil {
+				return
+			}
+			p.Log().Trace("Announced attestations", "count", len(hashes))
+
+		case <-p.term:
+			return
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package att
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	ATT1 = 1
+)
+
+// ProtocolName is the official short name of the `att` protocol used during
+// devp2p capability negotiation.
+const ProtocolName = "att"
+
+// ProtocolVersions are the supported versions of the `att` protocol (first
+// is primary).
+var ProtocolVersions = []uint{ATT1}
+
+// protocolLengths are the number of implemented message corresponding to
+// different protocol versions.
+var protocolLengths = map[uint]uint64{ATT1: 3}
+
+// maxMessageSize is the maximum cap on the size of a protocol message.
+const maxMessageSize = 2 * 1024 * 1024
+
+const (
+	NewAttestationHashesMsg = 0x00
+	GetAttestationsMsg      = 0x01
+	AttestationsMsg         = 0x02
+)
+
+var (
+	errMsgTooLarge    = errors.New("message too long")
+	errDecode         = errors.New("invalid message")
+	errInvalidMsgCode = errors.New("invalid message code")
+	errMisbehaving    = errors.New("peer score too low")
+)
+
+// Packet represents a p2p message in the `att` protocol.
+type Packet interface {
+	Name() string // Name returns a string corresponding to the message type.
+	Kind() byte   // Kind returns the message type.
+}
+
+// NewAttestationHashesPacket is the network packet for announcing the hashes
+// of newly seen attestations.
+type NewAttestationHashesPacket []common.Hash
+
+// GetAttestationsPacket represents an attestation query by hash.
+type GetAttestationsPacket []common.Hash
+
+// AttestationsPacket is the network packet carrying signed attestations, both
+// as a broadcast and as a reply to an attestation query.
+type AttestationsPacket []*hybrid.Attestation
+
+// enrEntry is the ENR entry which advertises `att` protocol on the discovery.
+type enrEntry struct {
+	// Ignore additional fields (for forward compatibility).
+	Rest []rlp.RawValue `rlp:"tail"`
+}
+
+// ENRKey implements enr.Entry.
+func (e enrEntry) ENRKey() string {
+	return "att"
+}
+
+func (*NewAttestationHashesPacket) Name() string { return "NewAttestationHashes" }
+func (*NewAttestationHashesPacket) Kind() byte   { return NewAttestationHashesMsg }
+
+func (*GetAttestationsPacket) Name() string { return "GetAttestations" }
+func (*GetAttestationsPacket) Kind() byte   { return GetAttestationsMsg }
+
+func (*AttestationsPacket) Name() string { return "Attestations" }
+func (*AttestationsPacket) Kind() byte   { return AttestationsMsg }
@@ -17,6 +17,7 @@
 package eth
 
 import (
+	"errors"
 	"math"
 	"math/big"
 	"math/rand"
@@ -34,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 var (
@@ -555,3 +557,71 @@ func testGetBlockReceipts(t *testing.T, protocol uint) {
 		t.Errorf("receipts mismatch: %v", err)
 	}
 }
+
+// Tests that propagated blocks are only rejected for their size if they exceed
+// the EIP-7934 limit after Fusaka.
+func TestCheckBlockSize(t *testing.T) {
+	config := *params.TestChainConfig
+	config.CancunBlock = big.NewInt(2)
+	config.FusakaBlock = big.NewInt(2)
+
+	db := rawdb.NewMemoryDatabase()
+	(&core.Genesis{Config: &config}).MustCommit(db)
+	chain, _ := core.NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer chain.Stop()
+	backend := &testBackend{db: db, chain: chain}
+
+	tests := []struct {
+		number uint64
+		extra  uint64
+		err    error
+	}{
+		{number: 1, extra: 0},
+		{number: 1, extra: params.MaxRLPBlockSizeFUSAKA},
+		{number: 2, extra: 0},
+		{number: 2, extra: params.MaxRLPBlockSizeFUSAKA, err: core.ErrBlockTooLarge},
+	}
+	for i, tt := range tests {
+		block := types.NewBlockWithHeader(&types.Header{
+			Number:     new(big.Int).SetUint64(tt.number),
+			Difficulty: common.Big1,
+			Extra:      make([]byte, tt.extra),
+		})
+		blob, err := rlp.EncodeToBytes(block)
+		if err != nil {
+			t.Fatalf("test %d: failed to encode block: %v", i, err)
+		}
+		if err := checkBlockSize(backend, blob); !errors.Is(err, tt.err) {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+// Tests that oversized block bodies are rejected once Fusaka is scheduled, even
+// while the local head is still before the fork.
+func TestOversizedBlockBodies(t *testing.T) {
+	config := *params.TestChainConfig
+	config.CancunBlock = big.NewInt(100)
+	config.FusakaBlock = big.NewInt(100)
+
+	db := rawdb.NewMemoryDatabase()
+	(&core.Genesis{Config: &config}).MustCommit(db)
+	chain, _ := core.NewBlockChain(db, nil, &config, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer chain.Stop()
+	backend := &testBackend{db: db, chain: chain}
+
+	body, err := rlp.EncodeToBytes(&BlockBody{
+		Uncles: []*types.Header{{Number: common.Big1, Difficulty: common.Big1, Extra: make([]byte, params.MaxRLPBlockSizeFUSAKA)}},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode body: %v", err)
+	}
+	size, r, err := rlp.EncodeToReader(&BlockBodiesRLPPacket66{RequestId: 1, BlockBodiesRLPPacket: BlockBodiesRLPPacket{body}})
+	if err != nil {
+		t.Fatalf("failed to encode packet: %v", err)
+	}
+	msg := p2p.Msg{Code: BlockBodiesMsg, Size: uint32(size), Payload: r}
+	if err := handleBlockBodies66(backend, msg, nil); !errors.Is(err, core.ErrBlockTooLarge) {
+		t.Errorf("error mismatch: have %v, want %v", err, core.ErrBlockTooLarge)
+	}
+}
@@ -17,6 +17,7 @@
 package eth
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 )
@@ -326,9 +328,17 @@ func handleNewBlockhashes(backend Backend, msg Decoder, peer *Peer) error {
 }
 
 func handleNewBlock(backend Backend, msg Decoder, peer *Peer) error {
-	// Retrieve and decode the propagated block
-	ann := new(NewBlockPacket)
-	if err := msg.Decode(ann); err != nil {
+	// Retrieve the propagated block, rejecting it if oversized before decoding
+	// the full body
+	raw := new(NewBlockRLPPacket)
+	if err := msg.Decode(raw); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	if err := checkBlockSize(backend, raw.Block); err != nil {
+		return err
+	}
+	ann := &NewBlockPacket{Block: new(types.Block), TD: raw.TD}
+	if err := rlp.DecodeBytes(raw.Block, ann.Block); err != nil {
 		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
 	}
 	if err := ann.sanityCheck(); err != nil {
@@ -372,11 +382,27 @@ func handleBlockHeaders66(backend Backend, msg Decoder, peer *Peer) error {
 }
 
 func handleBlockBodies66(backend Backend, msg Decoder, peer *Peer) error {
-	// A batch of block bodies arrived to one of our previous requests
-	res := new(BlockBodiesPacket66)
-	if err := msg.Decode(res); err != nil {
+	// A batch of block bodies arrived to one of our previous requests. Bodies
+	// don't carry their block number and may be requested for blocks past the
+	// local head, so any oversized body is rejected before decoding it once
+	// Fusaka is scheduled (EIP-7934).
+	raw := new(BlockBodiesRLPPacket66)
+	if err := msg.Decode(raw); err != nil {
 		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
 	}
+	res := &BlockBodiesPacket66{
+		RequestId:         raw.RequestId,
+		BlockBodiesPacket: make(BlockBodiesPacket, len(raw.BlockBodiesRLPPacket)),
+	}
+	for i, body := range raw.BlockBodiesRLPPacket {
+		if size := uint64(len(body)); size > params.MaxRLPBlockSizeFUSAKA && backend.Chain().Config().FusakaBlock != nil {
+			return fmt.Errorf("%w: body %d, size %d, limit %d", core.ErrBlockTooLarge, i, size, params.MaxRLPBlockSizeFUSAKA)
+		}
+		res.BlockBodiesPacket[i] = new(BlockBody)
+		if err := rlp.DecodeBytes(body, res.BlockBodiesPacket[i]); err != nil {
+			return fmt.Errorf("%w: message %v: body %d: %v", errDecode, msg, i, err)
+		}
+	}
 	metadata := func() interface{} {
 		var (
 			txsHashes   = make([]common.Hash, len(res.BlockBodiesPacket))
@@ -526,3 +552,24 @@ func handlePooledTransactions66(backend Backend, msg Decoder, peer *Peer) error
 
 	return backend.Handle(peer, &txs.PooledTransactionsPacket)
 }
+
+// checkBlockSize rejects an RLP-encoded post-Fusaka block exceeding the EIP-7934
+// size limit. Only the header is decoded, to find out whether the limit applies.
+func checkBlockSize(backend Backend, block rlp.RawValue) error {
+	size := uint64(len(block))
+	if size <= params.MaxRLPBlockSizeFUSAKA {
+		return nil
+	}
+	stream := rlp.NewStream(bytes.NewReader(block), size)
+	if _, err := stream.List(); err != nil {
+		return fmt.Errorf("%w: block: %v", errDecode, err)
+	}
+	header := new(types.Header)
+	if err := stream.Decode(header); err != nil {
+		return fmt.Errorf("%w: block header: %v", errDecode, err)
+	}
+	if backend.Chain().Config().IsFusaka(header.Number) {
+		return fmt.Errorf("%w: block %d, size %d, limit %d", core.ErrBlockTooLarge, header.Number, size, params.MaxRLPBlockSizeFUSAKA)
+	}
+	return nil
+}
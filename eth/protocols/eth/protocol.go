@@ -205,6 +205,13 @@ func (request *NewBlockPacket) sanityCheck() error {
 	return nil
 }
 
+// NewBlockRLPPacket is the block propagation message with the block left
+// RLP-encoded, so that its size can be checked before decoding it.
+type NewBlockRLPPacket struct {
+	Block rlp.RawValue
+	TD    *big.Int
+}
+
 // GetBlockBodiesPacket represents a block body query.
 type GetBlockBodiesPacket []common.Hash
 
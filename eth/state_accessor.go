@@ -175,6 +175,11 @@ func (eth *Ethereum) stateAtTransaction(block *types.Block, txIndex int, reexec
 	if err != nil {
 		return nil, vm.BlockContext{}, nil, err
 	}
+	// Record the parent hash in the history storage contract (EIP-2935)
+	if eth.blockchain.Config().IsPrague(block.Number()) {
+		vmenv := vm.NewEVM(core.NewEVMBlockContext(block.Header(), eth.blockchain, nil), vm.TxContext{}, statedb, eth.blockchain.Config(), vm.Config{})
+		core.ProcessParentBlockHash(block.ParentHash(), vmenv, statedb)
+	}
 	if txIndex == 0 && len(block.Transactions()) == 0 {
 		return nil, vm.BlockContext{}, statedb, nil
 	}
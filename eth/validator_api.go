@@ -17,46 +17,89 @@
 package eth
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
+	"sort"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// stakingABI is the subset of the ValidatorStaking contract ABI (see
+// contracts/staking/build/ValidatorStaking.abi) queried by the validator API.
+const stakingABI = `[
+	{"name":"getValidator","type":"function","stateMutability":"view","inputs":[{"name":"validator","type":"address"}],"outputs":[{"name":"selfStake","type":"uint256"},{"name":"totalDelegated","type":"uint256"},{"name":"commission","type":"uint256"},{"name":"lastActiveBlock","type":"uint256"},{"name":"isActive","type":"bool"},{"name":"isOnline","type":"bool"},{"name":"isSlashed","type":"bool"}]},
+	{"name":"getDelegation","type":"function","stateMutability":"view","inputs":[{"name":"delegator","type":"address"},{"name":"validator","type":"address"}],"outputs":[{"name":"amount","type":"uint256"},{"name":"pendingRewards","type":"uint256"}]},
+	{"name":"getPendingWithdrawals","type":"function","stateMutability":"view","inputs":[{"name":"user","type":"address"}],"outputs":[{"name":"totalPending","type":"uint256"},{"name":"totalReady","type":"uint256"}]},
+	{"name":"rewardPool","type":"function","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+var errHybridNotActive = errors.New("hybrid consensus not active")
+
 // ValidatorAPI provides RPC methods for hybrid PoW/PoS validator operations.
+// Validator and finality data come from the hybrid consensus engine, which
+// registers its own engine-level methods in the same namespace; staking data
+// is read through calls to the staking contract.
 type ValidatorAPI struct {
-	e *Ethereum
+	e   *Ethereum
+	abi abi.ABI
 }
 
 // NewValidatorAPI creates a new ValidatorAPI instance.
 func NewValidatorAPI(e *Ethereum) *ValidatorAPI {
-	return &ValidatorAPI{e: e}
+	parsed, err := abi.JSON(strings.NewReader(stakingABI))
+	if err != nil {
+		panic(err)
+	}
+	return &ValidatorAPI{e: e, abi: parsed}
 }
 
 // ValidatorInfo contains information about a validator.
 type ValidatorInfo struct {
 	Address           common.Address `json:"address"`
 	Stake             *hexutil.Big   `json:"stake"`
+	SelfStake         *hexutil.Big   `json:"selfStake"`
+	TotalDelegated    *hexutil.Big   `json:"totalDelegated"`
+	Commission        uint64         `json:"commission"`
 	IsActive          bool           `json:"isActive"`
+	IsOnline          bool           `json:"isOnline"`
+	IsSlashed         bool           `json:"isSlashed"`
 	PendingRewards    *hexutil.Big   `json:"pendingRewards"`
-	AttestationCount  uint64         `json:"attestationCount"`
 	LastAttestation   uint64         `json:"lastAttestation"`
 	WithdrawalPending bool           `json:"withdrawalPending"`
-	WithdrawalTime    uint64         `json:"withdrawalTime,omitempty"`
+}
+
+// DelegationInfo contains a delegation to a validator.
+type DelegationInfo struct {
+	Delegator      common.Address `json:"delegator"`
+	Validator      common.Address `json:"validator"`
+	Amount         *hexutil.Big   `json:"amount"`
+	PendingRewards *hexutil.Big   `json:"pendingRewards"`
+}
+
+// WithdrawalInfo contains the undelegated amounts queued for withdrawal.
+type WithdrawalInfo struct {
+	Pending *hexutil.Big `json:"pending"` // Still within the withdrawal delay
+	Ready   *hexutil.Big `json:"ready"`   // Withdrawable now
 }
 
 // NetworkStats contains network-wide validator statistics.
 type NetworkStats struct {
-	TotalValidators   uint64       `json:"totalValidators"`
-	ActiveValidators  uint64       `json:"activeValidators"`
-	TotalStaked       *hexutil.Big `json:"totalStaked"`
-	CurrentEpoch      uint64       `json:"currentEpoch"`
-	LastFinalizedBlock uint64      `json:"lastFinalizedBlock"`
-	PendingRewards    *hexutil.Big `json:"pendingRewards"`
+	TotalValidators    uint64       `json:"totalValidators"`
+	ActiveValidators   uint64       `json:"activeValidators"`
+	TotalStaked        *hexutil.Big `json:"totalStaked"`
+	CurrentEpoch       uint64       `json:"currentEpoch"`
+	JustifiedEpoch     uint64       `json:"justifiedEpoch"`
+	LastFinalizedBlock uint64       `json:"lastFinalizedBlock"`
+	PendingRewards     *hexutil.Big `json:"pendingRewards"`
 }
 
 // FinalityStatus contains finality information for a block.
@@ -70,104 +113,199 @@ type FinalityStatus struct {
 	Percentage       float64      `json:"percentage"`
 }
 
-// GetValidatorInfo returns information about a specific validator.
-func (api *ValidatorAPI) GetValidatorInfo(ctx context.Context, address common.Address) (*ValidatorInfo, error) {
-	// Check if hybrid consensus is active
-	if !api.e.blockchain.Config().IsHybrid(api.e.blockchain.CurrentBlock().Number()) {
-		return nil, errors.New("hybrid consensus not active")
+// engine returns the hybrid consensus engine if hybrid consensus is active at
+// the current head.
+func (api *ValidatorAPI) engine() (*hybrid.Hybrid, error) {
+	engine := api.e.hybridEngine()
+	if engine == nil || !api.e.blockchain.Config().IsHybrid(api.e.blockchain.CurrentBlock().Number()) {
+		return nil, errHybridNotActive
 	}
+	return engine, nil
+}
 
-	// Get staking contract state
-	state, _, err := api.e.APIBackend.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+// callStaking executes a read-only call to the staking contract on top of the
+// latest block and returns the decoded outputs.
+func (api *ValidatorAPI) callStaking(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	data, err := api.abi.Pack(method, args...)
 	if err != nil {
 		return nil, err
 	}
+	var (
+		contract = api.e.hybridEngine().Config().StakingContract
+		input    = hexutil.Bytes(data)
+		backend  = api.e.APIBackend
+	)
+	result, err := ethapi.DoCall(ctx, backend, ethapi.TransactionArgs{To: &contract, Input: &input}, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil, backend.RPCEVMTimeout(), backend.RPCGasCap())
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Err; err != nil {
+		return nil, fmt.Errorf("staking contract %s failed: %w", method, err)
+	}
+	return api.abi.Unpack(method, result.Return())
+}
 
-	// Read validator info from staking contract
-	stakingContract := api.e.blockchain.Config().Hybrid.StakingContract
-
-	// Get stake amount (slot 0 in validators mapping)
-	stakeSlot := common.BytesToHash(address.Bytes())
-	stakeValue := state.GetState(stakingContract, stakeSlot)
-	stake := new(big.Int).SetBytes(stakeValue.Bytes())
+// GetValidatorInfo returns information about a specific validator.
+func (api *ValidatorAPI) GetValidatorInfo(ctx context.Context, address common.Address) (*ValidatorInfo, error) {
+	engine, err := api.engine()
+	if err != nil {
+		return nil, err
+	}
+	v, err := api.callStaking(ctx, "getValidator", address)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		selfStake      = v[0].(*big.Int)
+		totalDelegated = v[1].(*big.Int)
+		stake          = new(big.Int).Add(selfStake, totalDelegated)
+	)
+	info := &ValidatorInfo{
+		Address:         address,
+		Stake:           (*hexutil.Big)(stake),
+		SelfStake:       (*hexutil.Big)(selfStake),
+		TotalDelegated:  (*hexutil.Big)(totalDelegated),
+		Commission:      v[2].(*big.Int).Uint64(),
+		IsActive:        v[4].(bool) && !v[6].(bool) && stake.Cmp(engine.Config().MinStake) >= 0,
+		IsOnline:        v[5].(bool),
+		IsSlashed:       v[6].(bool),
+		LastAttestation: v[3].(*big.Int).Uint64(),
+	}
+	// Attestations seen by the engine are more recent than the ones recorded on chain
+	if validator, ok := engine.GetValidators()[address]; ok && validator.LastAttestation > info.LastAttestation {
+		info.LastAttestation = validator.LastAttestation
+	}
+	// Commission that could not be paid out is kept as rewards of the self delegation
+	d, err := api.callStaking(ctx, "getDelegation", address, address)
+	if err != nil {
+		return nil, err
+	}
+	info.PendingRewards = (*hexutil.Big)(d[1].(*big.Int))
 
-	// Check if validator is active (stake >= minStake)
-	minStake := (*big.Int)(api.e.blockchain.Config().Hybrid.MinStake)
-	isActive := stake.Cmp(minStake) >= 0
+	w, err := api.callStaking(ctx, "getPendingWithdrawals", address)
+	if err != nil {
+		return nil, err
+	}
+	info.WithdrawalPending = w[0].(*big.Int).Sign() > 0 || w[1].(*big.Int).Sign() > 0
+	return info, nil
+}
 
-	// Get pending rewards (would need to calculate from contract state)
-	pendingRewards := big.NewInt(0) // Simplified - actual implementation reads from contract
+// GetDelegation returns the amount delegated by an account to a validator and
+// the rewards it can claim.
+func (api *ValidatorAPI) GetDelegation(ctx context.Context, delegator common.Address, validator common.Address) (*DelegationInfo, error) {
+	if _, err := api.engine(); err != nil {
+		return nil, err
+	}
+	d, err := api.callStaking(ctx, "getDelegation", delegator, validator)
+	if err != nil {
+		return nil, err
+	}
+	return &DelegationInfo{
+		Delegator:      delegator,
+		Validator:      validator,
+		Amount:         (*hexutil.Big)(d[0].(*big.Int)),
+		PendingRewards: (*hexutil.Big)(d[1].(*big.Int)),
+	}, nil
+}
 
-	return &ValidatorInfo{
-		Address:          address,
-		Stake:            (*hexutil.Big)(stake),
-		IsActive:         isActive,
-		PendingRewards:   (*hexutil.Big)(pendingRewards),
-		AttestationCount: 0, // Would track in hybrid consensus engine
-		LastAttestation:  0,
+// GetPendingWithdrawals returns the amounts an account has queued for
+// withdrawal.
+func (api *ValidatorAPI) GetPendingWithdrawals(ctx context.Context, address common.Address) (*WithdrawalInfo, error) {
+	if _, err := api.engine(); err != nil {
+		return nil, err
+	}
+	w, err := api.callStaking(ctx, "getPendingWithdrawals", address)
+	if err != nil {
+		return nil, err
+	}
+	return &WithdrawalInfo{
+		Pending: (*hexutil.Big)(w[0].(*big.Int)),
+		Ready:   (*hexutil.Big)(w[1].(*big.Int)),
 	}, nil
 }
 
 // GetActiveValidators returns a list of all active validator addresses.
 func (api *ValidatorAPI) GetActiveValidators(ctx context.Context) ([]common.Address, error) {
-	// Check if hybrid consensus is active
-	if !api.e.blockchain.Config().IsHybrid(api.e.blockchain.CurrentBlock().Number()) {
-		return nil, errors.New("hybrid consensus not active")
+	engine, err := api.engine()
+	if err != nil {
+		return nil, err
 	}
-
-	// In a full implementation, this would iterate through the staking contract
-	// to find all validators with stake >= minStake
-	// For now, return empty list as placeholder
-	return []common.Address{}, nil
+	active := make([]common.Address, 0)
+	for addr, info := range engine.GetValidators() {
+		if info.Active {
+			active = append(active, addr)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return bytes.Compare(active[i][:], active[j][:]) < 0
+	})
+	return active, nil
 }
 
 // GetNetworkStats returns network-wide validator statistics.
 func (api *ValidatorAPI) GetNetworkStats(ctx context.Context) (*NetworkStats, error) {
-	// Check if hybrid consensus is active
-	currentBlock := api.e.blockchain.CurrentBlock()
-	if !api.e.blockchain.Config().IsHybrid(currentBlock.Number()) {
-		return nil, errors.New("hybrid consensus not active")
+	engine, err := api.engine()
+	if err != nil {
+		return nil, err
 	}
+	validators := engine.GetValidators()
 
-	return &NetworkStats{
-		TotalValidators:    0,
-		ActiveValidators:   0,
-		TotalStaked:        (*hexutil.Big)(big.NewInt(0)),
-		CurrentEpoch:       currentBlock.NumberU64() / 32, // 32 blocks per epoch
-		LastFinalizedBlock: 0, // Would come from hybrid consensus engine
-		PendingRewards:     (*hexutil.Big)(big.NewInt(0)),
-	}, nil
+	stats := &NetworkStats{
+		TotalValidators: uint64(len(validators)),
+		TotalStaked:     (*hexutil.Big)(engine.GetTotalStake()),
+		CurrentEpoch:    hybrid.EpochOf(api.e.blockchain.CurrentBlock().NumberU64()),
+		JustifiedEpoch:  engine.Justified().Epoch,
+	}
+	for _, info := range validators {
+		if info.Active {
+			stats.ActiveValidators++
+		}
+	}
+	if block := api.e.blockchain.CurrentFinalizedBlock(); block != nil {
+		stats.LastFinalizedBlock = block.NumberU64()
+	}
+	pool, err := api.callStaking(ctx, "rewardPool")
+	if err != nil {
+		return nil, err
+	}
+	stats.PendingRewards = (*hexutil.Big)(pool[0].(*big.Int))
+	return stats, nil
 }
 
 // GetFinalityStatus returns the finality status of a block.
 func (api *ValidatorAPI) GetFinalityStatus(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*FinalityStatus, error) {
-	// Check if hybrid consensus is active
-	if !api.e.blockchain.Config().IsHybrid(api.e.blockchain.CurrentBlock().Number()) {
-		return nil, errors.New("hybrid consensus not active")
+	engine, err := api.engine()
+	if err != nil {
+		return nil, err
 	}
-
-	// Get the block
-	var block *types.Block
-	if blockNr, ok := blockNrOrHash.Number(); ok {
-		block = api.e.blockchain.GetBlockByNumber(uint64(blockNr))
-	} else if hash, ok := blockNrOrHash.Hash(); ok {
-		block = api.e.blockchain.GetBlockByHash(hash)
+	header, err := api.e.APIBackend.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
 	}
-
-	if block == nil {
+	if header == nil {
 		return nil, errors.New("block not found")
 	}
-
-	// In a full implementation, this would query the hybrid consensus engine
-	// for attestation data
+	var (
+		number = header.Number.Uint64()
+		hash   = header.Hash()
+		status = engine.FinalityStatus(number, hash)
+	)
+	required := new(big.Int).Mul(status.TotalStake, new(big.Int).SetUint64(status.Threshold))
+	required.Div(required, big.NewInt(100))
+
+	// A block is final if it is canonical and not newer than the finalized block
+	finalized := false
+	if block := api.e.blockchain.CurrentFinalizedBlock(); block != nil && number <= block.NumberU64() {
+		finalized = api.e.blockchain.GetCanonicalHash(number) == hash
+	}
 	return &FinalityStatus{
-		BlockNumber:      block.NumberU64(),
-		BlockHash:        block.Hash(),
-		IsFinalized:      false, // Would check hybrid engine
-		AttestationCount: 0,
-		TotalStakeVoted:  (*hexutil.Big)(big.NewInt(0)),
-		RequiredStake:    (*hexutil.Big)(big.NewInt(0)),
-		Percentage:       0,
+		BlockNumber:      number,
+		BlockHash:        hash,
+		IsFinalized:      finalized,
+		AttestationCount: uint64(status.AttesterCount),
+		TotalStakeVoted:  (*hexutil.Big)(status.AttestingStake),
+		RequiredStake:    (*hexutil.Big)(required),
+		Percentage:       status.StakePercent,
 	}, nil
 }
 
@@ -175,14 +313,14 @@ func (api *ValidatorAPI) GetFinalityStatus(ctx context.Context, blockNrOrHash rp
 // This creates and sends a transaction to the staking contract.
 func (api *ValidatorAPI) Stake(ctx context.Context, from common.Address, amount *hexutil.Big) (common.Hash, error) {
 	// Check if hybrid consensus is active
-	if !api.e.blockchain.Config().IsHybrid(api.e.blockchain.CurrentBlock().Number()) {
-		return common.Hash{}, errors.New("hybrid consensus not active")
+	engine, err := api.engine()
+	if err != nil {
+		return common.Hash{}, err
 	}
 
 	// Validate minimum stake
-	minStake := (*big.Int)(api.e.blockchain.Config().Hybrid.MinStake)
-	if (*big.Int)(amount).Cmp(minStake) < 0 {
-		return common.Hash{}, errors.New("stake amount below minimum (32 ALT)")
+	if minStake := engine.Config().MinStake; (*big.Int)(amount).Cmp(minStake) < 0 {
+		return common.Hash{}, fmt.Errorf("stake amount below minimum of %v wei", minStake)
 	}
 
 	// The actual staking would be done by sending a transaction to the staking contract
@@ -235,11 +373,12 @@ func (api *ValidatorAPI) GetStakingContractAddress(ctx context.Context) (common.
 
 // GetMinimumStake returns the minimum stake required to become a validator.
 func (api *ValidatorAPI) GetMinimumStake(ctx context.Context) (*hexutil.Big, error) {
-	// Check if hybrid config exists
-	if api.e.blockchain.Config().Hybrid == nil {
+	// Check if hybrid config exists, the engine resolves the default minimum
+	engine := api.e.hybridEngine()
+	if engine == nil {
 		return nil, errors.New("hybrid consensus not configured")
 	}
-	return api.e.blockchain.Config().Hybrid.MinStake, nil
+	return (*hexutil.Big)(new(big.Int).Set(engine.Config().MinStake)), nil
 }
 
 // IsHybridActive returns whether hybrid consensus is currently active.
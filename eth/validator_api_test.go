@@ -0,0 +1,235 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/hybrid"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Tests that the validator API serves the staking state of a deployed staking
+// contract and the validator set tracked by the hybrid engine.
+func TestValidatorAPI(t *testing.T) {
+	code, err := os.ReadFile("../contracts/staking/build/ValidatorStaking.bin")
+	if err != nil {
+		t.Fatalf("failed to read staking contract code: %v", err)
+	}
+	abiJSON, err := os.ReadFile("../contracts/staking/build/ValidatorStaking.abi")
+	if err != nil {
+		t.Fatalf("failed to read staking contract ABI: %v", err)
+	}
+	staking, err := abi.JSON(strings.NewReader(string(abiJSON)))
+	if err != nil {
+		t.Fatalf("failed to parse staking contract ABI: %v", err)
+	}
+	var (
+		validatorKey, _ = crypto.GenerateKey()
+		delegatorKey, _ = crypto.GenerateKey()
+		validator       = crypto.PubkeyToAddress(validatorKey.PublicKey)
+		delegator       = crypto.PubkeyToAddress(delegatorKey.PublicKey)
+		contract        = crypto.CreateAddress(validator, 0)
+		ether           = big.NewInt(params.Ether)
+		config          = *params.TestChainConfig
+	)
+	config.HybridBlock = big.NewInt(0)
+	config.Hybrid = &params.HybridConfig{
+		StakingContract:        contract,
+		MinerRewardPercent:     50,
+		ValidatorRewardPercent: 50,
+	}
+	genesis := &core.Genesis{
+		Config:  &config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: core.GenesisAlloc{
+			validator: {Balance: new(big.Int).Mul(big.NewInt(2000), ether)},
+			delegator: {Balance: new(big.Int).Mul(big.NewInt(100), ether)},
+		},
+	}
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = hybrid.New(hybrid.NewConfig(config.Hybrid), db, ethash.Config{PowMode: ethash.ModeFake}, nil, false)
+		signer = types.LatestSigner(&config)
+	)
+	defer engine.Close()
+
+	// Deploy the staking contract, register a validator, then delegate to it
+	// and undelegate part of the delegation again
+	transact := func(b *core.BlockGen, key *ecdsa.PrivateKey, to *common.Address, value *big.Int, data []byte) {
+		tx, err := types.SignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    b.TxNonce(crypto.PubkeyToAddress(key.PublicKey)),
+			To:       to,
+			Value:    value,
+			Gas:      3000000,
+			GasPrice: big.NewInt(2 * params.InitialBaseFee),
+			Data:     data,
+		})
+		if err != nil {
+			t.Fatalf("failed to sign transaction: %v", err)
+		}
+		b.AddTx(tx)
+	}
+	pack := func(method string, args ...interface{}) []byte {
+		data, err := staking.Pack(method, args...)
+		if err != nil {
+			t.Fatalf("failed to pack %s: %v", method, err)
+		}
+		return data
+	}
+	blocks, _ := core.GenerateChain(&config, genesis.MustCommit(db), engine, db, 3, func(i int, b *core.BlockGen) {
+		switch i {
+		case 0:
+			transact(b, validatorKey, nil, new(big.Int), common.FromHex(strings.TrimSpace(string(code))))
+		case 1:
+			transact(b, validatorKey, &contract, new(big.Int).Mul(big.NewInt(1000), ether), pack("registerValidator", big.NewInt(10)))
+			transact(b, delegatorKey, &contract, new(big.Int).Mul(big.NewInt(20), ether), pack("delegate", validator))
+		case 2:
+			transact(b, delegatorKey, &contract, new(big.Int), pack("undelegate", validator, new(big.Int).Mul(big.NewInt(5), ether)))
+		}
+	})
+	stack, ethservice := startHybridService(t, genesis, blocks)
+	defer stack.Close()
+
+	for _, block := range blocks {
+		receipts := ethservice.BlockChain().GetReceiptsByHash(block.Hash())
+		for _, receipt := range receipts {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				t.Fatalf("transaction %x in block %d failed", receipt.TxHash, block.NumberU64())
+			}
+		}
+	}
+	var (
+		api = NewValidatorAPI(ethservice)
+		ctx = context.Background()
+	)
+	info, err := api.GetValidatorInfo(ctx, validator)
+	if err != nil {
+		t.Fatalf("failed to retrieve validator info: %v", err)
+	}
+	if have, want := info.SelfStake.ToInt(), new(big.Int).Mul(big.NewInt(1000), ether); have.Cmp(want) != 0 {
+		t.Errorf("self stake mismatch: have %v, want %v", have, want)
+	}
+	if have, want := info.Stake.ToInt(), new(big.Int).Mul(big.NewInt(1015), ether); have.Cmp(want) != 0 {
+		t.Errorf("stake mismatch: have %v, want %v", have, want)
+	}
+	if !info.IsActive || info.IsSlashed || info.Commission != 10 || info.WithdrawalPending {
+		t.Errorf("validator info mismatch: %+v", info)
+	}
+	delegation, err := api.GetDelegation(ctx, delegator, validator)
+	if err != nil {
+		t.Fatalf("failed to retrieve delegation: %v", err)
+	}
+	if have, want := delegation.Amount.ToInt(), new(big.Int).Mul(big.NewInt(15), ether); have.Cmp(want) != 0 {
+		t.Errorf("delegation mismatch: have %v, want %v", have, want)
+	}
+	withdrawals, err := api.GetPendingWithdrawals(ctx, delegator)
+	if err != nil {
+		t.Fatalf("failed to retrieve withdrawals: %v", err)
+	}
+	if have, want := withdrawals.Pending.ToInt(), new(big.Int).Mul(big.NewInt(5), ether); have.Cmp(want) != 0 || withdrawals.Ready.ToInt().Sign() != 0 {
+		t.Errorf("withdrawals mismatch: have %v/%v, want %v/0", have, withdrawals.Ready, want)
+	}
+	// The engine picks up the validator set of the new head asynchronously
+	var active []common.Address
+	for i := 0; i < 50; i++ {
+		if active, err = api.GetActiveValidators(ctx); err != nil || len(active) > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil || len(active) != 1 || active[0] != validator {
+		t.Fatalf("active validators mismatch: have %x (err %v), want [%x]", active, err, validator)
+	}
+	stats, err := api.GetNetworkStats(ctx)
+	if err != nil {
+		t.Fatalf("failed to retrieve network stats: %v", err)
+	}
+	if stats.TotalValidators != 1 || stats.ActiveValidators != 1 || stats.TotalStaked.ToInt().Cmp(info.Stake.ToInt()) != 0 {
+		t.Errorf("network stats mismatch: %+v", stats)
+	}
+	// Without attestations, the validator rewards of every block went to the pool
+	if have, want := stats.PendingRewards.ToInt(), new(big.Int).Mul(big.NewInt(3), ether); have.Cmp(want) != 0 {
+		t.Errorf("reward pool mismatch: have %v, want %v", have, want)
+	}
+	// The genesis leaves the minimum stake to the engine default
+	minStake, err := api.GetMinimumStake(ctx)
+	if err != nil {
+		t.Fatalf("failed to retrieve minimum stake: %v", err)
+	}
+	if have, want := minStake.ToInt(), new(big.Int).Mul(big.NewInt(32), ether); have.Cmp(want) != 0 {
+		t.Errorf("minimum stake mismatch: have %v, want %v", have, want)
+	}
+	if _, err := api.Stake(ctx, validator, (*hexutil.Big)(new(big.Int).Mul(big.NewInt(16), ether))); err == nil {
+		t.Errorf("stake below the minimum accepted")
+	}
+	status, err := api.GetFinalityStatus(ctx, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	if err != nil {
+		t.Fatalf("failed to retrieve finality status: %v", err)
+	}
+	if status.BlockHash != blocks[2].Hash() || status.IsFinalized || status.AttestationCount != 0 {
+		t.Errorf("finality status mismatch: %+v", status)
+	}
+}
+
+// startHybridService creates a full node with the given genesis and imports
+// the given blocks.
+func startHybridService(t *testing.T, genesis *core.Genesis, blocks []*types.Block) (*node.Node, *Ethereum) {
+	t.Helper()
+
+	stack, err := node.New(&node.Config{
+		P2P: p2p.Config{
+			ListenAddr:  "0.0.0.0:0",
+			NoDiscovery: true,
+			MaxPeers:    25,
+		}})
+	if err != nil {
+		t.Fatal("can't create node:", err)
+	}
+	ethcfg := &ethconfig.Config{Genesis: genesis, Ethash: ethash.Config{PowMode: ethash.ModeFake}, SyncMode: downloader.FullSync, TrieTimeout: time.Minute, TrieDirtyCache: 256, TrieCleanCache: 256, RPCGasCap: 50000000, RPCEVMTimeout: 5 * time.Second}
+	ethservice, err := New(stack, ethcfg)
+	if err != nil {
+		t.Fatal("can't create eth service:", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatal("can't start node:", err)
+	}
+	if _, err := ethservice.BlockChain().InsertChain(blocks); err != nil {
+		stack.Close()
+		t.Fatal("can't import test blocks:", err)
+	}
+	return stack, ethservice
+}
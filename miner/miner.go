@@ -26,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/peerdas"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -182,6 +183,12 @@ func (miner *Miner) SetExtra(extra []byte) error {
 	return nil
 }
 
+// SetDAValidator sets the data availability validator used to commit to the
+// data of post-Fusaka blocks and to serve their samples.
+func (miner *Miner) SetDAValidator(da *peerdas.DAValidator) {
+	miner.worker.setDAValidator(da)
+}
+
 // SetRecommitInterval sets the interval for sealing work resubmitting.
 func (miner *Miner) SetRecommitInterval(interval time.Duration) {
 	miner.worker.setRecommitInterval(interval)
@@ -27,10 +27,13 @@ import (
 	mapset "github.com/deckarep/golang-set"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/consensus/peerdas"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
@@ -75,6 +78,10 @@ const (
 
 	// staleThreshold is the maximum depth of the acceptable stale block.
 	staleThreshold = 7
+
+	// blockSizeBufferZone is the space kept free below the EIP-7934 block size
+	// limit for the header, uncles and list encoding overhead.
+	blockSizeBufferZone = 1_000_000
 )
 
 var (
@@ -98,6 +105,9 @@ type environment struct {
 	txs      []*types.Transaction
 	receipts []*types.Receipt
 	uncles   map[common.Hash]*types.Header
+	sidecars []*types.BlobTxSidecar // blob sidecars stripped from the included blob txs
+	blobs    int                    // number of blobs included in the block
+	size     uint64                 // RLP size of the included transactions
 }
 
 // copy creates a deep copy of environment.
@@ -111,6 +121,8 @@ func (env *environment) copy() *environment {
 		coinbase:  env.coinbase,
 		header:    types.CopyHeader(env.header),
 		receipts:  copyReceipts(env.receipts),
+		blobs:     env.blobs,
+		size:      env.size,
 	}
 	if env.gasPool != nil {
 		gasPool := *env.gasPool
@@ -120,6 +132,8 @@ func (env *environment) copy() *environment {
 	// to do the expensive deep copy for them.
 	cpy.txs = make([]*types.Transaction, len(env.txs))
 	copy(cpy.txs, env.txs)
+	cpy.sidecars = make([]*types.BlobTxSidecar, len(env.sidecars))
+	copy(cpy.sidecars, env.sidecars)
 	cpy.uncles = make(map[common.Hash]*types.Header)
 	for hash, uncle := range env.uncles {
 		cpy.uncles[hash] = uncle
@@ -151,6 +165,8 @@ type task struct {
 	receipts  []*types.Receipt
 	state     *state.StateDB
 	block     *types.Block
+	encoded   *peerdas.ErasureEncodedData // Erasure coded block data, post-Fusaka only
+	sidecars  []*types.BlobTxSidecar      // Blob sidecars of the included blob txs
 	createdAt time.Time
 }
 
@@ -218,9 +234,10 @@ type worker struct {
 	remoteUncles map[common.Hash]*types.Block // A set of side blocks as the possible uncle blocks.
 	unconfirmed  *unconfirmedBlocks           // A set of locally mined blocks pending canonicalness confirmations.
 
-	mu       sync.RWMutex // The lock used to protect the coinbase and extra fields
+	mu       sync.RWMutex // The lock used to protect the coinbase, extra and da fields
 	coinbase common.Address
 	extra    []byte
+	da       *peerdas.DAValidator // Data availability committer for post-Fusaka blocks
 
 	pendingMu    sync.RWMutex
 	pendingTasks map[common.Hash]*task
@@ -276,6 +293,9 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 		resubmitIntervalCh: make(chan time.Duration),
 		resubmitAdjustCh:   make(chan *intervalAdjust, resubmitAdjustChanSize),
 	}
+	if chainConfig.FusakaBlock != nil {
+		worker.da = peerdas.NewDAValidator(peerdas.NewPeerDAS(chainConfig), nil)
+	}
 	// Subscribe NewTxsEvent for tx pool
 	worker.txsSub = eth.TxPool().SubscribeNewTxsEvent(worker.txsCh)
 	// Subscribe events for blockchain
@@ -322,6 +342,14 @@ func (w *worker) setExtra(extra []byte) {
 	w.extra = extra
 }
 
+// setDAValidator sets the validator committing to the data of post-Fusaka
+// blocks and storing their samples.
+func (w *worker) setDAValidator(da *peerdas.DAValidator) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.da = da
+}
+
 // setRecommitInterval updates the interval for miner sealing work recommitting.
 func (w *worker) setRecommitInterval(interval time.Duration) {
 	select {
@@ -739,6 +767,10 @@ func (w *worker) resultLoop() {
 			log.Info("Successfully sealed new block", "number", block.Number(), "sealhash", sealhash, "hash", hash,
 				"elapsed", common.PrettyDuration(time.Since(task.createdAt)))
 
+			// Serve the samples of the block data to the network
+			w.storeSamples(task.encoded)
+			w.storeBlobSamples(block, task.sidecars)
+
 			// Broadcast the block and announce chain insertion event
 			w.mux.Post(core.NewMinedBlockEvent{Block: block})
 
@@ -830,8 +862,18 @@ func (w *worker) commitTransaction(env *environment, tx *types.Transaction) ([]*
 		env.state.RevertToSnapshot(snap)
 		return nil, err
 	}
+	// Blob sidecars are not part of the block, keep them aside for sampling
+	if sidecar := tx.BlobTxSidecar(); sidecar != nil {
+		env.sidecars = append(env.sidecars, sidecar)
+		tx = tx.WithoutBlobTxSidecar()
+	}
+	if tx.Type() == types.BlobTxType {
+		*env.header.BlobGasUsed += tx.BlobGas()
+		env.blobs += len(tx.BlobHashes())
+	}
 	env.txs = append(env.txs, tx)
 	env.receipts = append(env.receipts, receipt)
+	env.size += uint64(tx.Size())
 
 	return receipt.Logs, nil
 }
@@ -843,6 +885,7 @@ func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByP
 	}
 	var coalescedLogs []*types.Log
 
+	verifier, _ := w.innerEngine().(txVerifyingEngine)
 	for {
 		// In the following three cases, we will interrupt the execution of the transaction.
 		// (1) new head block event arrival, the interrupt signal is 1
@@ -894,6 +937,35 @@ func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByP
 			txs.Pop()
 			continue
 		}
+		// Skip transactions the consensus engine would reject the block for
+		if verifier != nil {
+			if err := verifier.VerifyTransaction(w.chain, env.header, tx); err != nil {
+				log.Trace("Ignoring transaction rejected by the consensus engine", "hash", tx.Hash(), "err", err)
+				txs.Pop()
+				continue
+			}
+		}
+		// If the blob transaction doesn't fit into the remaining blob space, skip it
+		if tx.Type() == types.BlobTxType {
+			if env.header.BlobGasUsed == nil {
+				log.Trace("Ignoring blob transaction before Cancun", "hash", tx.Hash())
+				txs.Pop()
+				continue
+			}
+			if left := params.BlobTxMaxBlobGasPerBlock/params.BlobTxBlobGasPerBlob - env.blobs; left < len(tx.BlobHashes()) {
+				log.Trace("Not enough blob space left for transaction", "hash", tx.Hash(), "left", left, "needed", len(tx.BlobHashes()))
+				txs.Pop()
+				continue
+			}
+		}
+		// If the transaction would push the block over the RLP size limit, the
+		// block is full (EIP-7934)
+		if w.chainConfig.IsFusaka(env.header.Number) {
+			if size := uint64(tx.WithoutBlobTxSidecar().Size()); env.size+size > params.MaxRLPBlockSizeFUSAKA-blockSizeBufferZone {
+				log.Trace("Not enough block space for further transactions", "have", env.size, "want", size)
+				break
+			}
+		}
 		// Start executing the transaction
 		env.state.Prepare(tx.Hash(), env.tcount)
 
@@ -925,6 +997,11 @@ func (w *worker) commitTransactions(env *environment, txs *types.TransactionsByP
 			log.Trace("Skipping unsupported transaction type", "sender", from, "type", tx.Type())
 			txs.Pop()
 
+		case errors.Is(err, core.ErrTransactionGasLimit):
+			// Pop the over-capped transaction without shifting in the next from the account
+			log.Trace("Skipping transaction above gas cap", "sender", from, "gas", tx.Gas())
+			txs.Pop()
+
 		default:
 			// Strange error, discard the transaction and get the next in line (note, the
 			// nonce-too-high clause will prevent us from executing in vain).
@@ -1017,6 +1094,17 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 			header.GasLimit = core.CalcGasLimitWithConfig(parentGasLimit, w.config.GasCeil, w.chainConfig, header.Number)
 		}
 	}
+	// Set the blob gas fields if we are on an EIP-4844 chain
+	if w.chainConfig.IsCancun(header.Number) {
+		var parentExcessBlobGas, parentBlobGasUsed uint64
+		if parent.ExcessBlobGas() != nil {
+			parentExcessBlobGas = *parent.ExcessBlobGas()
+			parentBlobGasUsed = *parent.BlobGasUsed()
+		}
+		excessBlobGas := misc.CalcExcessBlobGas(parentExcessBlobGas, parentBlobGasUsed)
+		header.ExcessBlobGas = &excessBlobGas
+		header.BlobGasUsed = new(uint64)
+	}
 	// Run the consensus preparation with the default or customized consensus engine.
 	if err := w.engine.Prepare(w.chain, header); err != nil {
 		log.Error("Failed to prepare header for sealing", "err", err)
@@ -1030,6 +1118,11 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 		log.Error("Failed to create sealing context", "err", err)
 		return nil, err
 	}
+	// Record the parent hash in the history storage contract (EIP-2935)
+	if w.chainConfig.IsPrague(header.Number) {
+		vmenv := vm.NewEVM(core.NewEVMBlockContext(header, w.chain, &env.coinbase), vm.TxContext{}, env.state, w.chainConfig, *w.chain.GetVMConfig())
+		core.ProcessParentBlockHash(header.ParentHash, vmenv, env.state)
+	}
 	// Accumulate the uncles for the sealing work only if it's allowed.
 	if !genParams.noUncle {
 		commitUncles := func(blocks map[common.Hash]*types.Block) {
@@ -1051,10 +1144,53 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 	return env, nil
 }
 
+// payloadEngine is implemented by consensus engines that require system
+// transactions to be embedded in the blocks they seal.
+type payloadEngine interface {
+	Payload(chain consensus.ChainHeaderReader, header *types.Header, statedb *state.StateDB) types.Transactions
+}
+
+// txVerifyingEngine is implemented by consensus engines that reject blocks with
+// transactions the transaction pool accepts.
+type txVerifyingEngine interface {
+	VerifyTransaction(chain consensus.ChainHeaderReader, header *types.Header, tx *types.Transaction) error
+}
+
+// innerEngine returns the consensus engine sealing pre-merge blocks.
+func (w *worker) innerEngine() consensus.Engine {
+	if b, ok := w.engine.(*beacon.Beacon); ok {
+		return b.InnerEngine()
+	}
+	return w.engine
+}
+
+// commitPayload commits the system transactions of the consensus engine, if it
+// has any, to the sealing block ahead of the pending transactions.
+func (w *worker) commitPayload(env *environment) {
+	pe, ok := w.innerEngine().(payloadEngine)
+	if !ok {
+		return
+	}
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+	for _, tx := range pe.Payload(w.chain, env.header, env.state) {
+		env.state.Prepare(tx.Hash(), env.tcount)
+		if _, err := w.commitTransaction(env, tx); err != nil {
+			log.Warn("Failed to commit consensus payload transaction", "hash", tx.Hash(), "err", err)
+			return
+		}
+		env.tcount++
+	}
+}
+
 // fillTransactions retrieves the pending transactions from the txpool and fills them
 // into the given sealing block. The transaction selection and ordering strategy can
 // be customized with the plugin in the future.
 func (w *worker) fillTransactions(interrupt *int32, env *environment) error {
+	// Embed the system transactions required by the consensus engine first
+	w.commitPayload(env)
+
 	// Split the pending transactions into locals and remotes
 	// Fill the block with all available pending transactions.
 	pending := w.eth.TxPool().Pending(true)
@@ -1091,7 +1227,19 @@ func (w *worker) generateWork(params *generateParams) (*types.Block, error) {
 	if !params.noTxs {
 		w.fillTransactions(nil, work)
 	}
-	return w.engine.FinalizeAndAssemble(w.chain, work.header, work.state, work.txs, work.unclelist(), work.receipts)
+	encoded, err := w.commitData(work.header, work.txs)
+	if err != nil {
+		return nil, err
+	}
+	block, err := w.engine.FinalizeAndAssemble(w.chain, work.header, work.state, work.txs, work.unclelist(), work.receipts)
+	if err != nil {
+		return nil, err
+	}
+	// The block is handed to the consensus client for proposal, make its
+	// samples available right away
+	w.storeSamples(encoded)
+	w.storeBlobSamples(block, work.sidecars)
+	return block, nil
 }
 
 // commitWork generates several new sealing tasks based on the parent block
@@ -1149,6 +1297,10 @@ func (w *worker) commit(env *environment, interval func(), update bool, start ti
 		// Create a local environment copy, avoid the data race with snapshot state.
 		// https://github.com/ethereum/go-ethereum/issues/24299
 		env := env.copy()
+		encoded, err := w.commitData(env.header, env.txs)
+		if err != nil {
+			return err
+		}
 		block, err := w.engine.FinalizeAndAssemble(w.chain, env.header, env.state, env.txs, env.unclelist(), env.receipts)
 		if err != nil {
 			return err
@@ -1156,7 +1308,7 @@ func (w *worker) commit(env *environment, interval func(), update bool, start ti
 		// If we're post merge, just ignore
 		if !w.isTTDReached(block.Header()) {
 			select {
-			case w.taskCh <- &task{receipts: env.receipts, state: env.state, block: block, createdAt: time.Now()}:
+			case w.taskCh <- &task{receipts: env.receipts, state: env.state, block: block, encoded: encoded, sidecars: env.sidecars, createdAt: time.Now()}:
 				w.unconfirmed.Shift(block.NumberU64() - 1)
 				log.Info("Commit new sealing work", "number", block.Number(), "sealhash", w.engine.SealHash(block.Header()),
 					"uncles", len(env.uncles), "txs", env.tcount,
@@ -1174,6 +1326,55 @@ func (w *worker) commit(env *environment, interval func(), update bool, start ti
 	return nil
 }
 
+// commitData sets the data availability commitment of a post-Fusaka header from
+// the transactions of the block (EIP-7594), returning the erasure coded data.
+func (w *worker) commitData(header *types.Header, txs types.Transactions) (*peerdas.ErasureEncodedData, error) {
+	if !w.chainConfig.IsFusaka(header.Number) {
+		return nil, nil
+	}
+	w.mu.RLock()
+	da := w.da
+	w.mu.RUnlock()
+
+	commitment, encoded, err := da.CommitBlock(header.Number, txs)
+	if err != nil {
+		return nil, err
+	}
+	hash := peerdas.HashDACommitment(commitment)
+	header.DACommitment = &hash
+	return encoded, nil
+}
+
+// storeSamples makes the samples of erasure coded block data available to the
+// network, if any.
+func (w *worker) storeSamples(encoded *peerdas.ErasureEncodedData) {
+	if encoded == nil {
+		return
+	}
+	w.mu.RLock()
+	da := w.da
+	w.mu.RUnlock()
+
+	da.StoreBlockSamples(encoded)
+}
+
+// storeBlobSamples makes the samples of the blobs carried by the blob txs of a
+// block available to the network.
+func (w *worker) storeBlobSamples(block *types.Block, sidecars []*types.BlobTxSidecar) {
+	if len(sidecars) == 0 {
+		return
+	}
+	w.mu.RLock()
+	da := w.da
+	w.mu.RUnlock()
+
+	for _, sidecar := range sidecars {
+		if err := da.StoreSidecarSamples(block.Number(), sidecar); err != nil {
+			log.Warn("Failed to sample blob sidecar", "number", block.Number(), "blobs", len(sidecar.Blobs), "err", err)
+		}
+	}
+}
+
 // getSealingBlock generates the sealing block based on the given parameters.
 // The generation result will be passed back via the given channel no matter
 // the generation itself succeeds or not.
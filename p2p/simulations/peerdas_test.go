@@ -0,0 +1,135 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simulations
+
+import (
+	"bytes"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/peerdas"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/simulations/adapters"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// peerdasService runs the `peerdas` protocol on a simulated node.
+type peerdasService struct {
+	proto *peerdas.Protocol
+}
+
+func (s *peerdasService) Start() error { return nil }
+func (s *peerdasService) Stop() error  { return nil }
+
+// Tests that data availability samples held by one node can be fetched by the
+// other nodes of a simulated network over the `peerdas` protocol.
+func TestPeerDASSampleExchange(t *testing.T) {
+	config := &params.ChainConfig{ChainID: big.NewInt(2330), FusakaBlock: big.NewInt(0)}
+
+	var (
+		lock   sync.Mutex
+		protos = make(map[enode.ID]*peerdas.Protocol)
+	)
+	adapter := adapters.NewSimAdapter(adapters.LifecycleConstructors{
+		"peerdas": func(ctx *adapters.ServiceContext, stack *node.Node) (node.Lifecycle, error) {
+			proto := peerdas.NewProtocol(peerdas.NewPeerDAS(config))
+			stack.RegisterProtocols(peerdas.MakeProtocols(proto))
+
+			lock.Lock()
+			protos[ctx.Config.ID] = proto
+			lock.Unlock()
+			return &peerdasService{proto: proto}, nil
+		},
+	})
+	network := NewNetwork(adapter, &NetworkConfig{DefaultService: "peerdas"})
+	defer network.Shutdown()
+
+	ids := make([]enode.ID, 3)
+	for i := range ids {
+		node, err := network.NewNodeWithConfig(adapters.RandomNodeConfig())
+		if err != nil {
+			t.Fatalf("error creating node: %v", err)
+		}
+		if err := network.Start(node.ID()); err != nil {
+			t.Fatalf("error starting node: %v", err)
+		}
+		ids[i] = node.ID()
+	}
+	if err := network.ConnectNodesFull(ids); err != nil {
+		t.Fatalf("error connecting nodes: %v", err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		connected := true
+		for _, id := range ids {
+			if protos[id].PeerCount() != len(ids)-1 {
+				connected = false
+			}
+		}
+		if connected {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for peerdas peers to connect")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Only the first node holds the samples of the block
+	var (
+		number = big.NewInt(100)
+		data   = bytes.Repeat([]byte("peerdas samples "), 256)
+	)
+	encoded, err := peerdas.NewPeerDAS(config).EncodeBlockData(data, number)
+	if err != nil {
+		t.Fatalf("failed to encode data: %v", err)
+	}
+	peerdas.NewDAValidator(peerdas.NewPeerDAS(config), protos[ids[0]]).StoreBlockSamples(encoded)
+
+	dataHash := encoded.DataHash
+	samples := protos[ids[0]].GetCachedSamples(dataHash)
+	byIndex := make(map[uint64]*peerdas.DataSample)
+	for _, sample := range samples {
+		byIndex[sample.SampleIndex] = sample
+	}
+
+	// Both other nodes must be able to fetch them, whichever peer they ask first
+	for _, id := range ids[1:] {
+		indices := []uint64{0, 2, 3}
+		fetched, err := protos[id].RequestSamples(number, dataHash, indices)
+		if err != nil {
+			t.Fatalf("node %v: failed to fetch samples: %v", id, err)
+		}
+		if len(fetched) != len(indices) {
+			t.Fatalf("node %v: fetched sample count mismatch: have %d, want %d", id, len(fetched), len(indices))
+		}
+		for _, sample := range fetched {
+			want := byIndex[sample.SampleIndex]
+			if !bytes.Equal(sample.SampleData, want.SampleData) || sample.Commitment != want.Commitment {
+				t.Errorf("node %v: sample %d mismatch", id, sample.SampleIndex)
+			}
+		}
+		if cached := protos[id].GetCachedSamples(dataHash); len(cached) != len(indices) {
+			t.Errorf("node %v: cached sample count mismatch: have %d, want %d", id, len(cached), len(indices))
+		}
+	}
+	// Samples nobody holds are reported as unavailable
+	if _, err := protos[ids[1]].RequestSamples(number, dataHash, []uint64{uint64(len(samples))}); err == nil {
+		t.Error("fetched sample unknown to the network")
+	}
+}
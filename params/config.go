@@ -289,16 +289,16 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, nil, false, nil, false, nil, nil, big.NewInt(1337), nil, false, new(EthashConfig), nil}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, nil, nil, false, nil, false, nil, nil, big.NewInt(1337), nil, false, new(EthashConfig), nil}
 
 	// AllCliqueProtocolChanges contains every protocol change (EIPs) introduced
 	// and accepted by the Ethereum core developers into the Clique consensus.
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, nil, nil, nil, false, nil, false, nil, nil, big.NewInt(1337), nil, false, nil, &CliqueConfig{Period: 0, Epoch: 30000}}
+	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, nil, nil, nil, nil, false, nil, false, nil, nil, big.NewInt(1337), nil, false, nil, &CliqueConfig{Period: 0, Epoch: 30000}}
 
-	TestChainConfig = &ChainConfig{big.NewInt(1), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, nil, false, nil, false, nil, nil, big.NewInt(1), nil, false, new(EthashConfig), nil}
+	TestChainConfig = &ChainConfig{big.NewInt(1), big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, nil, nil, nil, false, nil, false, nil, nil, big.NewInt(1), nil, false, new(EthashConfig), nil}
 	TestRules       = TestChainConfig.Rules(new(big.Int), false)
 )
 
@@ -391,6 +391,7 @@ type ChainConfig struct {
 	MergeNetsplitBlock  *big.Int `json:"mergeNetsplitBlock,omitempty"`  // Virtual fork after The Merge to use as a network splitter
 	ShanghaiBlock       *big.Int `json:"shanghaiBlock,omitempty"`       // Shanghai switch block (nil = no fork, 0 = already on shanghai)
 	CancunBlock         *big.Int `json:"cancunBlock,omitempty"`         // Cancun switch block (nil = no fork, 0 = already on cancun)
+	PragueBlock         *big.Int `json:"pragueBlock,omitempty"`         // Prague switch block (nil = no fork, 0 = already on prague)
 	FusakaBlock         *big.Int `json:"fusakaBlock,omitempty"`         // Fusaka switch block (nil = no fork, 0 = already on fusaka)
 	XeggeXForkBlock     *big.Int `json:"xeggexForkBlock,omitempty"`     // XeggeX recovery fork block (nil = no fork)
 	XeggeXForkSupport   bool     `json:"xeggexForkSupport,omitempty"`   // Whether to apply XeggeX fund recovery
@@ -512,6 +513,9 @@ func (c *ChainConfig) String() string {
 	if c.CancunBlock != nil {
 		banner += fmt.Sprintf(" - Cancun:                      %-8v\n", c.CancunBlock)
 	}
+	if c.PragueBlock != nil {
+		banner += fmt.Sprintf(" - Prague:                      %-8v\n", c.PragueBlock)
+	}
 	if c.FusakaBlock != nil {
 		banner += fmt.Sprintf(" - Fusaka:                      %-8v\n", c.FusakaBlock)
 	}
@@ -629,6 +633,11 @@ func (c *ChainConfig) IsCancun(num *big.Int) bool {
 	return isForked(c.CancunBlock, num)
 }
 
+// IsPrague returns whether num is either equal to the Prague fork block or greater.
+func (c *ChainConfig) IsPrague(num *big.Int) bool {
+	return isForked(c.PragueBlock, num)
+}
+
 // IsFusaka returns whether num is either equal to the Fusaka fork block or greater.
 func (c *ChainConfig) IsFusaka(num *big.Int) bool {
 	return isForked(c.FusakaBlock, num)
@@ -690,6 +699,7 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 		{name: "mergeNetsplitBlock", block: c.MergeNetsplitBlock, optional: true},
 		{name: "shanghaiBlock", block: c.ShanghaiBlock, optional: true},
 		{name: "cancunBlock", block: c.CancunBlock, optional: true},
+		{name: "pragueBlock", block: c.PragueBlock, optional: true},
 		{name: "fusakaBlock", block: c.FusakaBlock, optional: true},
 		{name: "xeggexForkBlock", block: c.XeggeXForkBlock, optional: true},
 		{name: "hybridBlock", block: c.HybridBlock, optional: true},
@@ -712,6 +722,18 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	// The Fusaka header extends the Cancun one, so it can't be enabled alone
+	if c.FusakaBlock != nil && c.CancunBlock == nil {
+		return fmt.Errorf("unsupported fork ordering: cancunBlock not enabled, but fusakaBlock enabled at %v", c.FusakaBlock)
+	}
+	// Prague builds on Cancun, and the EIP-2935 history storage contract it deploys
+	// uses PUSH0, so neither of them can be left out
+	if c.PragueBlock != nil && c.ShanghaiBlock == nil {
+		return fmt.Errorf("unsupported fork ordering: shanghaiBlock not enabled, but pragueBlock enabled at %v", c.PragueBlock)
+	}
+	if c.PragueBlock != nil && c.CancunBlock == nil {
+		return fmt.Errorf("unsupported fork ordering: cancunBlock not enabled, but pragueBlock enabled at %v", c.PragueBlock)
+	}
 	return nil
 }
 
@@ -777,6 +799,9 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.CancunBlock, newcfg.CancunBlock, head) {
 		return newCompatError("Cancun fork block", c.CancunBlock, newcfg.CancunBlock)
 	}
+	if isForkIncompatible(c.PragueBlock, newcfg.PragueBlock, head) {
+		return newCompatError("Prague fork block", c.PragueBlock, newcfg.PragueBlock)
+	}
 	if isForkIncompatible(c.FusakaBlock, newcfg.FusakaBlock, head) {
 		return newCompatError("Fusaka fork block", c.FusakaBlock, newcfg.FusakaBlock)
 	}
@@ -863,7 +888,8 @@ type Rules struct {
 	IsHomestead, IsEIP150, IsEIP155, IsEIP158               bool
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
 	IsBerlin, IsLondon                                      bool
-	IsMerge, IsShanghai, isCancun, IsFusaka, IsEthPoWFork   bool
+	IsMerge, IsShanghai, IsCancun, IsPrague, IsFusaka       bool
+	IsEthPoWFork                                            bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -886,7 +912,8 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool) Rules {
 		IsLondon:         c.IsLondon(num),
 		IsMerge:          isMerge,
 		IsShanghai:       c.IsShanghai(num),
-		isCancun:         c.IsCancun(num),
+		IsCancun:         c.IsCancun(num),
+		IsPrague:         c.IsPrague(num),
 		IsFusaka:         c.IsFusaka(num),
 		IsEthPoWFork:     c.IsEthPoWFork(num),
 	}
@@ -96,3 +96,28 @@ func TestCheckCompatible(t *testing.T) {
 		}
 	}
 }
+
+// Tests that Prague can't be scheduled without Shanghai and Cancun ahead of it.
+func TestCheckConfigForkOrderPrague(t *testing.T) {
+	config := func(shanghai, cancun, prague *big.Int) *ChainConfig {
+		config := *AllEthashProtocolChanges
+		config.ShanghaiBlock, config.CancunBlock, config.PragueBlock = shanghai, cancun, prague
+		return &config
+	}
+	tests := []struct {
+		config  *ChainConfig
+		wantErr bool
+	}{
+		{config: config(nil, nil, nil), wantErr: false},
+		{config: config(nil, nil, big.NewInt(0)), wantErr: true},
+		{config: config(big.NewInt(0), nil, big.NewInt(0)), wantErr: true},
+		{config: config(nil, big.NewInt(0), big.NewInt(0)), wantErr: true},
+		{config: config(big.NewInt(0), big.NewInt(0), big.NewInt(0)), wantErr: false},
+		{config: config(big.NewInt(0), big.NewInt(20), big.NewInt(10)), wantErr: true},
+	}
+	for i, test := range tests {
+		if err := test.config.CheckConfigForkOrder(); (err != nil) != test.wantErr {
+			t.Errorf("test %d: error mismatch: have %v, want error %v", i, err, test.wantErr)
+		}
+	}
+}
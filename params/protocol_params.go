@@ -16,14 +16,18 @@
 
 package params
 
-import "math/big"
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
 
 const (
 	GasLimitBoundDivisor uint64 = 1024               // The bound divisor of the gas limit, used in update calculations.
 	MinGasLimit          uint64 = 5000               // Minimum the gas limit may ever be.
 	MaxGasLimit          uint64 = 0x7fffffffffffffff // Maximum the gas limit (2^63-1).
 	GenesisGasLimit      uint64 = 4712388            // Gas limit of the Genesis block.
-	
+
 	// EIP-7825: Transaction Gas Upper Limit
 	// Maximum gas per transaction (2^24 = 16,777,216)
 	MaxTransactionGasFUSAKA uint64 = 16777216 // 0x1000000
@@ -32,6 +36,12 @@ const (
 	// Target block gas limit for FUSAKA upgrade (~150M gas)
 	TargetBlockGasLimitFUSAKA uint64 = 150000000 // 0x23BE7890
 
+	// EIP-7934: RLP Execution Block Size Limit
+	// Maximum RLP-encoded block size, the 10 MiB gossip limit less a 2 MiB margin
+	MaxBlockSizeFUSAKA          uint64 = 10485760                                         // 10 MiB
+	BlockSizeSafetyMarginFUSAKA uint64 = 2097152                                          // 2 MiB
+	MaxRLPBlockSizeFUSAKA       uint64 = MaxBlockSizeFUSAKA - BlockSizeSafetyMarginFUSAKA // 8 MiB
+
 	MaximumExtraDataSize  uint64 = 32    // Maximum size extra data may be after Genesis.
 	ExpByteGas            uint64 = 10    // Times ceil(log256(exponent)) for the EXP instruction.
 	SloadGas              uint64 = 50    // Multiplied by the number of 32-byte words that are copied (round up) for any *COPY operation and added.
@@ -46,6 +56,7 @@ const (
 
 	Keccak256Gas     uint64 = 30 // Once per KECCAK256 operation.
 	Keccak256WordGas uint64 = 6  // Once per word of the KECCAK256 operation's data.
+	InitCodeWordGas  uint64 = 2  // Once per word of the init code when creating a contract.
 
 	SstoreSetGas    uint64 = 20000 // Once per SSTORE operation.
 	SstoreResetGas  uint64 = 5000  // Once per SSTORE operation if the zeroness changes from zero.
@@ -92,10 +103,11 @@ const (
 	SelfdestructRefundGas uint64 = 24000 // Refunded following a selfdestruct operation.
 	MemoryGas             uint64 = 3     // Times the address of the (highest referenced byte in memory + 1). NOTE: referencing happens on read, write and in instructions such as RETURN and CALL.
 
-	TxDataNonZeroGasFrontier  uint64 = 68   // Per byte of data attached to a transaction that is not equal to zero. NOTE: Not payable on data of calls between transactions.
-	TxDataNonZeroGasEIP2028   uint64 = 16   // Per byte of non zero data attached to a transaction after EIP 2028 (part in Istanbul)
-	TxAccessListAddressGas    uint64 = 2400 // Per address specified in EIP 2930 access list
-	TxAccessListStorageKeyGas uint64 = 1900 // Per storage key specified in EIP 2930 access list
+	TxDataNonZeroGasFrontier  uint64 = 68    // Per byte of data attached to a transaction that is not equal to zero. NOTE: Not payable on data of calls between transactions.
+	TxDataNonZeroGasEIP2028   uint64 = 16    // Per byte of non zero data attached to a transaction after EIP 2028 (part in Istanbul)
+	TxAccessListAddressGas    uint64 = 2400  // Per address specified in EIP 2930 access list
+	TxAccessListStorageKeyGas uint64 = 1900  // Per storage key specified in EIP 2930 access list
+	TxAuthTupleGas            uint64 = 12500 // Per auth tuple code specified in EIP-7702, once the account exists
 
 	// These have been changed during the course of the chain
 	CallGasFrontier              uint64 = 40  // Once per CALL operation & message call transaction.
@@ -131,7 +143,8 @@ const (
 	ElasticityMultiplier     = 2          // Bounds the maximum gas limit an EIP-1559 block may have.
 	InitialBaseFee           = 1000000000 // Initial base fee for EIP-1559 blocks.
 
-	MaxCodeSize = 24576 // Maximum bytecode to permit for a contract
+	MaxCodeSize     = 24576           // Maximum bytecode to permit for a contract
+	MaxInitCodeSize = 2 * MaxCodeSize // Maximum initcode to permit in a creation transaction and create instructions
 
 	// Precompiled contract gas prices
 
@@ -161,6 +174,21 @@ const (
 	Bls12381MapG1Gas          uint64 = 5500   // Gas price for BLS12-381 mapping field element to G1 operation
 	Bls12381MapG2Gas          uint64 = 110000 // Gas price for BLS12-381 mapping field element to G2 operation
 
+	BlobTxBytesPerFieldElement         = 32                       // Size in bytes of a field element
+	BlobTxFieldElementsPerBlob         = 4096                     // Number of field elements stored in a single data blob
+	BlobTxHashVersion                  = 0x01                     // Version byte of the commitment hash
+	BlobTxBlobGasPerBlob               = 1 << 17                  // Gas consumption of a single data blob (== blob byte size)
+	BlobTxMaxBlobGasPerBlock           = 6 * BlobTxBlobGasPerBlob // Maximum consumable blob gas for data blobs per block
+	BlobTxTargetBlobGasPerBlock        = 3 * BlobTxBlobGasPerBlob // Target consumable blob gas for data blobs per block (for 1559-like pricing)
+	BlobTxMinBlobGasprice              = 1                        // Minimum gas price for data blobs
+	BlobTxBlobGaspriceUpdateFraction   = 3338477                  // Controls the maximum rate of change for blob gas price
+	BlobTxPointEvaluationPrecompileGas = 50000                    // Gas price for the point evaluation precompile.
+
+	P256VerifyGas uint64 = 6900 // Gas price for the secp256r1 signature verification precompile (EIP-7951)
+
+	HistoryServeWindow        = 8191       // Number of blocks to serve historical block hashes for (EIP-2935)
+	SystemCallGas      uint64 = 30_000_000 // Gas allowance for system contract calls made by the protocol
+
 	// The Refund Quotient is the cap on how much of the used gas can be refunded. Before EIP-3529,
 	// up to half the consumed gas could be refunded. Redefined as 1/5th in EIP-3529
 	RefundQuotient        uint64 = 2
@@ -177,3 +205,13 @@ var (
 	DurationLimit          = big.NewInt(13)                  // The decision boundary on the blocktime duration used to determine whether difficulty should go up or not.
 	ETHWStartDifficulty    = big.NewInt(197_198_199_200_201) // The ETHW start difficulty(Reset difficulty).
 )
+
+var (
+	// SystemAddress is the caller of protocol-level system contract calls.
+	SystemAddress = common.HexToAddress("0xfffffffffffffffffffffffffffffffffffffffe")
+
+	// HistoryStorageAddress is where the EIP-2935 historical block hashes
+	// contract lives, and HistoryStorageCode is its runtime bytecode.
+	HistoryStorageAddress = common.HexToAddress("0x0000F90827F1C53a10cb7A02335B175320002935")
+	HistoryStorageCode    = common.FromHex("3373fffffffffffffffffffffffffffffffffffffffe14604657602036036042575f35600143038111604257611fff81430311604257611fff9006545f5260205ff35b5f5ffd5b5f35611fff60014303065500")
+)
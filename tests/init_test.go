@@ -41,6 +41,7 @@ var (
 	rlpTestDir         = filepath.Join(baseDir, "RLPTests")
 	difficultyTestDir  = filepath.Join(baseDir, "BasicTests")
 	benchmarksDir      = filepath.Join(".", "evm-benchmarks", "benchmarks")
+	forkStateTestDir   = filepath.Join(".", "fork-tests", "GeneralStateTests")
 )
 
 func readJSON(reader io.Reader, value interface{}) error {
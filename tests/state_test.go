@@ -71,36 +71,53 @@ func TestState(t *testing.T) {
 		benchmarksDir,
 	} {
 		st.walk(t, dir, func(t *testing.T, name string, test *StateTest) {
-			for _, subtest := range test.Subtests() {
-				subtest := subtest
-				key := fmt.Sprintf("%s/%d", subtest.Fork, subtest.Index)
-
-				t.Run(key+"/trie", func(t *testing.T) {
-					withTrace(t, test.gasLimit(subtest), func(vmconfig vm.Config) error {
-						_, _, err := test.Run(subtest, vmconfig, false)
-						if err != nil && len(test.json.Post[subtest.Fork][subtest.Index].ExpectException) > 0 {
-							// Ignore expected errors (TODO MariusVanDerWijden check error string)
-							return nil
-						}
-						return st.checkFailure(t, err)
-					})
-				})
-				t.Run(key+"/snap", func(t *testing.T) {
-					withTrace(t, test.gasLimit(subtest), func(vmconfig vm.Config) error {
-						snaps, statedb, err := test.Run(subtest, vmconfig, true)
-						if snaps != nil && statedb != nil {
-							if _, err := snaps.Journal(statedb.IntermediateRoot(false)); err != nil {
-								return err
-							}
-						}
-						if err != nil && len(test.json.Post[subtest.Fork][subtest.Index].ExpectException) > 0 {
-							// Ignore expected errors (TODO MariusVanDerWijden check error string)
-							return nil
-						}
-						return st.checkFailure(t, err)
-					})
-				})
-			}
+			execStateTest(t, st, test)
+		})
+	}
+}
+
+// TestForkState runs the state tests shipped in this repository for the forks
+// scheduled on top of the upstream suite (Shanghai, Cancun).
+func TestForkState(t *testing.T) {
+	t.Parallel()
+
+	st := new(testMatcher)
+	st.walk(t, forkStateTestDir, func(t *testing.T, name string, test *StateTest) {
+		execStateTest(t, st, test)
+	})
+}
+
+// execStateTest runs all the subtests of a state test, both trie and
+// snapshot backed.
+func execStateTest(t *testing.T, st *testMatcher, test *StateTest) {
+	for _, subtest := range test.Subtests() {
+		subtest := subtest
+		key := fmt.Sprintf("%s/%d", subtest.Fork, subtest.Index)
+
+		t.Run(key+"/trie", func(t *testing.T) {
+			withTrace(t, test.gasLimit(subtest), func(vmconfig vm.Config) error {
+				_, _, err := test.Run(subtest, vmconfig, false)
+				if err != nil && len(test.json.Post[subtest.Fork][subtest.Index].ExpectException) > 0 {
+					// Ignore expected errors (TODO MariusVanDerWijden check error string)
+					return nil
+				}
+				return st.checkFailure(t, err)
+			})
+		})
+		t.Run(key+"/snap", func(t *testing.T) {
+			withTrace(t, test.gasLimit(subtest), func(vmconfig vm.Config) error {
+				snaps, statedb, err := test.Run(subtest, vmconfig, true)
+				if snaps != nil && statedb != nil {
+					if _, err := snaps.Journal(statedb.IntermediateRoot(false)); err != nil {
+						return err
+					}
+				}
+				if err != nil && len(test.json.Post[subtest.Fork][subtest.Index].ExpectException) > 0 {
+					// Ignore expected errors (TODO MariusVanDerWijden check error string)
+					return nil
+				}
+				return st.checkFailure(t, err)
+			})
 		})
 	}
 }
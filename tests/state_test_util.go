@@ -101,16 +101,17 @@ type stEnvMarshaling struct {
 //go:generate go run github.com/fjl/gencodec -type stTransaction -field-override stTransactionMarshaling -out gen_sttransaction.go
 
 type stTransaction struct {
-	GasPrice             *big.Int            `json:"gasPrice"`
-	MaxFeePerGas         *big.Int            `json:"maxFeePerGas"`
-	MaxPriorityFeePerGas *big.Int            `json:"maxPriorityFeePerGas"`
-	Nonce                uint64              `json:"nonce"`
-	To                   string              `json:"to"`
-	Data                 []string            `json:"data"`
-	AccessLists          []*types.AccessList `json:"accessLists,omitempty"`
-	GasLimit             []uint64            `json:"gasLimit"`
-	Value                []string            `json:"value"`
-	PrivateKey           []byte              `json:"secretKey"`
+	GasPrice             *big.Int                     `json:"gasPrice"`
+	MaxFeePerGas         *big.Int                     `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *big.Int                     `json:"maxPriorityFeePerGas"`
+	Nonce                uint64                       `json:"nonce"`
+	To                   string                       `json:"to"`
+	Data                 []string                     `json:"data"`
+	AccessLists          []*types.AccessList          `json:"accessLists,omitempty"`
+	AuthorizationList    []types.SetCodeAuthorization `json:"authorizationList,omitempty"`
+	GasLimit             []uint64                     `json:"gasLimit"`
+	Value                []string                     `json:"value"`
+	PrivateKey           []byte                       `json:"secretKey"`
 }
 
 type stTransactionMarshaling struct {
@@ -360,7 +361,7 @@ func (tx *stTransaction) toMessage(ps stPostState, baseFee *big.Int) (core.Messa
 	}
 
 	msg := types.NewMessage(from, to, tx.Nonce, value, gasLimit, gasPrice,
-		tx.MaxFeePerGas, tx.MaxPriorityFeePerGas, data, accessList, false)
+		tx.MaxFeePerGas, tx.MaxPriorityFeePerGas, data, accessList, tx.AuthorizationList, false)
 	return msg, nil
 }
 